@@ -0,0 +1,20 @@
+action "slicer_vm_reboot" "apply_sysctl" {
+  config {
+    host_group = slicer_vm.example.host_group
+    hostname   = slicer_vm.example.hostname
+  }
+}
+
+# Reboot the VM whenever the kernel parameters change
+resource "slicer_file" "sysctl" {
+  hostname    = slicer_vm.example.hostname
+  destination = "/etc/sysctl.d/99-tuning.conf"
+  content     = "vm.swappiness = 10\n"
+
+  lifecycle {
+    action_trigger {
+      events  = [after_create, after_update]
+      actions = [action.slicer_vm_reboot.apply_sysctl]
+    }
+  }
+}
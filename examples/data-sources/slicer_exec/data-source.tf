@@ -0,0 +1,9 @@
+data "slicer_exec" "kernel" {
+  hostname = "w1-medium-1"
+  command  = "uname"
+  args     = ["-r"]
+}
+
+output "kernel" {
+  value = trimspace(data.slicer_exec.kernel.stdout)
+}
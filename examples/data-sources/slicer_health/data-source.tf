@@ -0,0 +1,18 @@
+# Fail the plan early with a clear message when the Slicer API is down
+data "slicer_health" "api" {}
+
+output "slicer_version" {
+  value = data.slicer_health.api.version
+}
+
+# Or only report the status, e.g. in a check block
+check "slicer_health" {
+  data "slicer_health" "status" {
+    fail_on_unhealthy = false
+  }
+
+  assert {
+    condition     = data.slicer_health.status.healthy
+    error_message = "Slicer API is ${data.slicer_health.status.status}."
+  }
+}
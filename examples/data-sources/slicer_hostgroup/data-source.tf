@@ -0,0 +1,7 @@
+data "slicer_hostgroup" "gpu" {
+  name = "gpu-large"
+}
+
+output "gpu_free_slots" {
+  value = data.slicer_hostgroup.gpu.free_slots
+}
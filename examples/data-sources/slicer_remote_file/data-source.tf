@@ -0,0 +1,9 @@
+data "slicer_remote_file" "join_token" {
+  hostname = slicer_vm.k3s_server.hostname
+  path     = "/var/lib/rancher/k3s/server/node-token"
+}
+
+resource "slicer_secret" "k3s_token" {
+  name  = "k3s-token"
+  value = trimspace(data.slicer_remote_file.join_token.content)
+}
@@ -0,0 +1,7 @@
+data "slicer_secrets" "app" {
+  name_prefix = "app-"
+}
+
+output "app_secrets" {
+  value = data.slicer_secrets.app.names
+}
@@ -0,0 +1,9 @@
+data "slicer_server_info" "current" {}
+
+output "slicer_version" {
+  value = data.slicer_server_info.current.version
+}
+
+output "supports_hostgroup_management" {
+  value = contains(data.slicer_server_info.current.features, "hostgroup_management")
+}
@@ -0,0 +1,7 @@
+data "slicer_snapshot" "latest" {
+  hostname = "db-primary"
+}
+
+output "latest_snapshot" {
+  value = data.slicer_snapshot.latest.name
+}
@@ -0,0 +1,7 @@
+data "slicer_ssh_key" "ops" {
+  name = "ops"
+}
+
+output "ops_fingerprint" {
+  value = data.slicer_ssh_key.ops.fingerprint
+}
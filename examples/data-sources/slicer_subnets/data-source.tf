@@ -0,0 +1,7 @@
+data "slicer_subnets" "workers" {
+  host_group = "w1-medium"
+}
+
+output "worker_cidrs" {
+  value = [for subnet in data.slicer_subnets.workers.subnets : subnet.cidr]
+}
@@ -11,3 +11,18 @@ output "k3s_count" {
 output "k3s_ips" {
   value = [for vm in data.slicer_vms.k3s_nodes.vms : vm.ip]
 }
+
+data "slicer_vms" "all" {}
+
+output "vms_with_db_password" {
+  value = [for vm in data.slicer_vms.all.vms : vm.hostname if contains(coalesce(vm.secrets, []), "db-password")]
+}
+
+data "slicer_vms" "new_arm_workers" {
+  filter {
+    host_group     = "w1-arm"
+    arch           = "arm64"
+    hostname_regex = "^worker-\\d+$"
+    created_after  = "2024-06-01T00:00:00Z"
+  }
+}
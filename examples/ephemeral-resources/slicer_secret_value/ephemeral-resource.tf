@@ -0,0 +1,9 @@
+ephemeral "slicer_secret_value" "db_password" {
+  name = "db-password"
+}
+
+provider "postgresql" {
+  host     = slicer_vm.db.ip
+  username = "postgres"
+  password = ephemeral.slicer_secret_value.db_password.value
+}
@@ -11,3 +11,43 @@ resource "slicer_exec" "example" {
 output "stdout" {
   value = slicer_exec.example.stdout
 }
+
+# Keep secrets printed by a command out of plan output
+resource "slicer_exec" "join_token" {
+  hostname         = "w1-medium-1"
+  command          = "cat /var/lib/rancher/k3s/server/node-token"
+  sensitive_output = true
+}
+
+# Upload a local script, run it and remove it again
+resource "slicer_exec" "bootstrap" {
+  hostname = "w1-medium-1"
+  script   = "${path.module}/bootstrap.sh"
+  args     = ["--role", "worker"]
+}
+
+# Run the same command on several VMs, two at a time
+resource "slicer_exec" "upgrade" {
+  hostnames    = ["w1-medium-1", "w1-medium-2", "w1-medium-3"]
+  command      = "apt-get upgrade -y"
+  max_parallel = 2
+}
+
+output "upgrade_exit_codes" {
+  value = { for host, result in slicer_exec.upgrade.results : host => result.exit_code }
+}
+
+# grep exits 1 when nothing matches, which is not an error here
+resource "slicer_exec" "has_swap" {
+  hostname           = "w1-medium-1"
+  command            = "grep"
+  args               = ["-q", "swap", "/etc/fstab"]
+  success_exit_codes = [0, 1]
+}
+
+# Run as a service account, whose IDs are looked up on the VM
+resource "slicer_exec" "app_migrate" {
+  hostname = "w1-medium-1"
+  command  = "/opt/app/bin/migrate"
+  user     = "app"
+}
@@ -4,3 +4,31 @@ resource "slicer_file" "example" {
   content     = "Hello, World!"
   permissions = "0644"
 }
+
+# Owned by a service account, resolved by name on the VM
+resource "slicer_file" "app_env" {
+  hostname    = "w1-medium-1"
+  destination = "/etc/app/app.env"
+  content     = "LOG_LEVEL=info\n"
+  permissions = "0600"
+  owner_name  = "app"
+  group_name  = "app"
+}
+
+# Keep the previous version as /etc/app/config.yaml.bak
+resource "slicer_file" "config" {
+  hostname    = "w1-medium-1"
+  destination = "/etc/app/config.yaml"
+  content     = "log_level: info\n"
+  permissions = "0644"
+  backup      = true
+}
+
+# Stream a build artifact, refusing anything larger than 512 MiB
+resource "slicer_file" "app_binary" {
+  hostname    = "w1-medium-1"
+  destination = "/usr/local/bin/app"
+  source      = "dist/app"
+  permissions = "0755"
+  max_size_mb = 512
+}
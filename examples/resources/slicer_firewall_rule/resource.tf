@@ -0,0 +1,30 @@
+resource "slicer_vm" "db" {
+  host_group = "w1-medium"
+  tags = {
+    role = "db"
+  }
+}
+
+# Let the app VMs reach PostgreSQL on the database VMs
+resource "slicer_firewall_rule" "app_to_db" {
+  description = "PostgreSQL from app VMs"
+  protocol    = "tcp"
+  port        = "5432"
+  source_tags = {
+    role = "app"
+  }
+  target_tags = {
+    role = "db"
+  }
+}
+
+# Keep the database VMs off the internet
+resource "slicer_firewall_rule" "db_egress" {
+  direction   = "egress"
+  action      = "deny"
+  protocol    = "all"
+  destination = "0.0.0.0/0"
+  target_tags = {
+    role = "db"
+  }
+}
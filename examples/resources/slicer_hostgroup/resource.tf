@@ -0,0 +1,6 @@
+resource "slicer_hostgroup" "example" {
+  name     = "w1-medium"
+  vm_count = 0
+  cpus     = 2
+  ram_gb   = 4
+}
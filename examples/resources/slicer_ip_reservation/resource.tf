@@ -0,0 +1,8 @@
+resource "slicer_ip_reservation" "dns" {
+  host_group  = "w1-medium"
+  description = "Internal DNS server"
+}
+
+output "dns_ip" {
+  value = slicer_ip_reservation.dns.ip
+}
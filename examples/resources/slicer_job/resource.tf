@@ -0,0 +1,11 @@
+resource "slicer_job" "import" {
+  type = "image_import"
+  params = {
+    url  = "https://example.com/images/ubuntu-24.04.img"
+    name = "ubuntu-24.04"
+  }
+}
+
+output "imported_image" {
+  value = slicer_job.import.result["image"]
+}
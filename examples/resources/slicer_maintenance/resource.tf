@@ -0,0 +1,5 @@
+resource "slicer_maintenance" "example" {
+  host_group = "w1-medium"
+  reason     = "Firmware upgrade"
+  evacuate   = true
+}
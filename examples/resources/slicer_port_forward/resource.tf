@@ -0,0 +1,17 @@
+resource "slicer_vm" "web" {
+  host_group = "w1-medium"
+}
+
+# Publish the web server of the VM on port 80 of its host
+resource "slicer_port_forward" "web" {
+  hostname      = slicer_vm.web.hostname
+  vm_port       = 8080
+  external_port = 80
+}
+
+# Let the API pick a free port for DNS
+resource "slicer_port_forward" "dns" {
+  hostname = slicer_vm.web.hostname
+  vm_port  = 53
+  protocol = "udp"
+}
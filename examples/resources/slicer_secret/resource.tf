@@ -2,3 +2,23 @@ resource "slicer_secret" "example" {
   name  = "example-secret"
   value = "secret-value"
 }
+
+resource "slicer_secret" "generated" {
+  name = "generated-secret"
+
+  generate {
+    length  = 48
+    special = true
+  }
+}
+
+resource "slicer_secret" "write_only" {
+  name             = "write-only-secret"
+  value_wo         = "secret-value"
+  value_wo_version = 1
+}
+
+resource "slicer_secret" "binary" {
+  name         = "binary-secret"
+  value_base64 = filebase64("${path.module}/keystore.p12")
+}
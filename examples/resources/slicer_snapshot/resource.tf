@@ -0,0 +1,4 @@
+resource "slicer_snapshot" "pre_deploy" {
+  name     = "db-pre-deploy-${var.release}"
+  hostname = slicer_vm.db.hostname
+}
@@ -0,0 +1,9 @@
+resource "slicer_ssh_key" "alice" {
+  name       = "alice"
+  public_key = file("~/.ssh/id_ed25519.pub")
+}
+
+resource "slicer_vm" "example" {
+  host_group    = "w1-medium"
+  ssh_key_names = [slicer_ssh_key.alice.name]
+}
@@ -20,3 +20,40 @@ output "vm_hostname" {
 output "vm_ip" {
   value = slicer_vm.example.ip
 }
+
+output "vm_connection_info" {
+  value = slicer_vm.example.connection_info
+}
+
+# Build the userdata from structured cloud-config
+resource "slicer_vm" "web" {
+  host_group = "w1-medium"
+
+  cloud_init {
+    packages = ["nginx"]
+    runcmd   = ["systemctl enable --now nginx"]
+
+    write_files {
+      path        = "/var/www/html/index.html"
+      content     = "<h1>Hello from Slicer</h1>\n"
+      permissions = "0644"
+    }
+  }
+}
+
+# Give the VM a fixed address in a specific subnet
+resource "slicer_vm" "dns" {
+  host_group = "w1-medium"
+  subnet     = "10.10.0.0/24"
+  ip_address = "10.10.0.53"
+}
+
+# Pass instance configuration through the metadata service
+resource "slicer_vm" "worker" {
+  host_group = "w1-medium"
+
+  metadata = {
+    queue_url   = "amqp://mq.internal:5672/jobs"
+    concurrency = "8"
+  }
+}
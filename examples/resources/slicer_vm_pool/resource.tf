@@ -0,0 +1,15 @@
+resource "slicer_vm_pool" "runners" {
+  name       = "ci-runners"
+  host_group = "w1-medium"
+  size       = 5
+
+  cpus   = 4
+  ram_gb = 8
+  tags = {
+    role = "ci"
+  }
+}
+
+output "runner_ips" {
+  value = slicer_vm_pool.runners.ips
+}
@@ -0,0 +1,20 @@
+resource "slicer_vm_template" "example" {
+  name   = "worker"
+  cpus   = 2
+  ram_gb = 4
+
+  import_user = "gaarutyunov"
+
+  tags = {
+    role = "worker"
+  }
+}
+
+resource "slicer_vm" "from_template" {
+  host_group  = "w1-medium"
+  template_id = slicer_vm_template.example.id
+
+  lifecycle {
+    replace_triggered_by = [slicer_vm_template.example.revision]
+  }
+}
@@ -0,0 +1,10 @@
+resource "slicer_volume" "pgdata" {
+  name       = "pgdata"
+  host_group = "w1-medium"
+  size_gb    = 50
+}
+
+resource "slicer_vm" "db" {
+  host_group         = "w1-medium"
+  volume_attachments = [slicer_volume.pgdata.name]
+}
@@ -0,0 +1,69 @@
+// Copyright (c) German Arutyunov
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+
+	"github.com/gaarutyunov/terraform-provider-slicer/internal/slicer"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// capability is an optional feature that needs a minimum Slicer version.
+type capability struct {
+	name       string
+	minVersion string
+}
+
+var (
+	capabilityExecUID             = capability{name: "Running commands as a non-root uid", minVersion: "0.1.30"}
+	capabilityExecBase64          = capability{name: "binary_output", minVersion: "0.1.40"}
+	capabilityExecTimeout         = capability{name: "Exec timeouts", minVersion: "0.2.0"}
+	capabilityHostGroupManagement = capability{name: "Managing host groups", minVersion: "0.2.0"}
+	capabilityMaintenance         = capability{name: "Host group maintenance mode", minVersion: "0.2.0"}
+	capabilityVMPriority          = capability{name: "VM priority", minVersion: "0.2.0"}
+	capabilityVMSchedule          = capability{name: "VM schedules", minVersion: "0.2.0"}
+	capabilityVMTemplates         = capability{name: "VM templates", minVersion: "0.2.0"}
+	capabilityVMClone             = capability{name: "Cloning VMs", minVersion: "0.2.0"}
+	capabilityIPReservations      = capability{name: "IP reservations", minVersion: "0.2.0"}
+	capabilityReverseDNS          = capability{name: "Reverse DNS", minVersion: "0.2.0"}
+	capabilityVMCDROM             = capability{name: "CD-ROM images", minVersion: "0.2.0"}
+	capabilityVMFirmware          = capability{name: "Secure boot and vTPM", minVersion: "0.2.0"}
+	capabilityVMWatchdog          = capability{name: "Watchdog devices", minVersion: "0.2.0"}
+	capabilityVMNaming            = capability{name: "Choosing VM hostnames", minVersion: "0.2.0"}
+	capabilityJobs                = capability{name: "Asynchronous jobs", minVersion: "0.2.0"}
+	capabilityVMResize            = capability{name: "Resizing VMs", minVersion: "0.2.0"}
+	capabilitySecretValues        = capability{name: "Reading secret values", minVersion: "0.2.0"}
+	capabilitySnapshots           = capability{name: "VM snapshots", minVersion: "0.2.0"}
+	capabilityVolumes             = capability{name: "Volumes", minVersion: "0.2.0"}
+	capabilityVMPower             = capability{name: "Starting and stopping VMs", minVersion: "0.2.0"}
+	capabilityFirewallRules       = capability{name: "Firewall rules", minVersion: "0.2.0"}
+	capabilityVMDisks             = capability{name: "Additional disks", minVersion: "0.2.0"}
+	capabilitySSHKeys             = capability{name: "Stored SSH keys", minVersion: "0.2.0"}
+	capabilityVMAddressing        = capability{name: "Static VM addresses", minVersion: "0.2.0"}
+	capabilityPortForwards        = capability{name: "Port forwards", minVersion: "0.2.0"}
+	capabilityVMMetadata          = capability{name: "VM metadata", minVersion: "0.2.0"}
+)
+
+// checkCapability adds an error at attrPath when the server is known to be
+// older than the version c requires. When the server version could not be
+// determined at Configure the check is skipped and the API decides.
+func checkCapability(serverInfo *slicer.SlicerServerInfo, c capability, attrPath path.Path, diags *diag.Diagnostics) {
+	if supportsCapability(serverInfo, c) {
+		return
+	}
+
+	diags.AddAttributeError(
+		attrPath,
+		"Unsupported Slicer Version",
+		fmt.Sprintf("%s requires Slicer >= %s, but the server reports version %s.", c.name, c.minVersion, serverInfo.Version),
+	)
+}
+
+// supportsCapability reports whether the server supports c. A server whose
+// version is unknown is assumed to support everything.
+func supportsCapability(serverInfo *slicer.SlicerServerInfo, c capability) bool {
+	return serverInfo == nil || serverInfo.Version == "" || serverInfo.AtLeast(c.minVersion)
+}
@@ -0,0 +1,277 @@
+// Copyright (c) German Arutyunov
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gaarutyunov/terraform-provider-slicer/internal/slicer"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &ExecDataSource{}
+
+func NewExecDataSource() datasource.DataSource {
+	return &ExecDataSource{}
+}
+
+// maxExecDataSourceOutputBytes limits the output kept in state, so a
+// chatty command does not bloat it.
+const maxExecDataSourceOutputBytes = 1 << 20
+
+// ExecDataSource defines the data source implementation.
+type ExecDataSource struct {
+	client *slicer.SlicerClient
+}
+
+// ExecDataSourceModel describes the data source data model.
+type ExecDataSourceModel struct {
+	Hostname         types.String `tfsdk:"hostname"`
+	Command          types.String `tfsdk:"command"`
+	Args             types.List   `tfsdk:"args"`
+	UID              types.Int64  `tfsdk:"uid"`
+	GID              types.Int64  `tfsdk:"gid"`
+	Workdir          types.String `tfsdk:"workdir"`
+	Shell            types.String `tfsdk:"shell"`
+	Timeout          types.String `tfsdk:"timeout"`
+	SuccessExitCodes types.List   `tfsdk:"success_exit_codes"`
+	SensitiveOutput  types.Bool   `tfsdk:"sensitive_output"`
+	ExitCode         types.Int64  `tfsdk:"exit_code"`
+	Stdout           types.String `tfsdk:"stdout"`
+	Stderr           types.String `tfsdk:"stderr"`
+	SensitiveStdout  types.String `tfsdk:"sensitive_stdout"`
+	SensitiveStderr  types.String `tfsdk:"sensitive_stderr"`
+}
+
+func (d *ExecDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_exec"
+}
+
+func (d *ExecDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Runs a read-only command on a Slicer VM on every plan and refresh and exposes its output, e.g. to read the version of an installed package. Use the `slicer_exec` resource for commands that change the VM.",
+
+		Attributes: map[string]schema.Attribute{
+			"hostname": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The hostname of the VM to run the command on.",
+			},
+			"command": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The command to run. It must not change the VM, since it runs on every plan.",
+			},
+			"args": schema.ListAttribute{
+				Optional:            true,
+				MarkdownDescription: "Arguments to pass to the command.",
+				ElementType:         types.StringType,
+			},
+			"uid": schema.Int64Attribute{
+				Optional:            true,
+				MarkdownDescription: "User ID to run the command as. Defaults to 0 (root).",
+			},
+			"gid": schema.Int64Attribute{
+				Optional:            true,
+				MarkdownDescription: "Group ID to run the command as. Defaults to 0 (root).",
+			},
+			"workdir": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Working directory for the command.",
+			},
+			"shell": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Shell to use for command execution (e.g., '/bin/bash').",
+			},
+			"timeout": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "How long the command may run, e.g. `30s`. The read fails when it runs longer. Defaults to the provider's `timeout`.",
+			},
+			"success_exit_codes": schema.ListAttribute{
+				Optional:            true,
+				MarkdownDescription: "Exit codes that count as success, e.g. `[0, 1]` for `grep`. Any other exit code fails the read. Defaults to `[0]`.",
+				ElementType:         types.Int64Type,
+			},
+			"sensitive_output": schema.BoolAttribute{
+				Optional:            true,
+				MarkdownDescription: "Whether the command prints secrets such as tokens. The output is then kept in `sensitive_stdout` and `sensitive_stderr`, which are hidden in plan output, instead of `stdout` and `stderr`, and it is left out of error messages. Defaults to false.",
+			},
+			"exit_code": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "The exit code of the command.",
+			},
+			"stdout": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The standard output of the command. Null when `sensitive_output` is true.",
+			},
+			"stderr": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The standard error of the command. Null when `sensitive_output` is true.",
+			},
+			"sensitive_stdout": schema.StringAttribute{
+				Computed:            true,
+				Sensitive:           true,
+				MarkdownDescription: "The standard output of the command. Only set when `sensitive_output` is true.",
+			},
+			"sensitive_stderr": schema.StringAttribute{
+				Computed:            true,
+				Sensitive:           true,
+				MarkdownDescription: "The standard error of the command. Only set when `sensitive_output` is true.",
+			},
+		},
+	}
+}
+
+func (d *ExecDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*SlicerProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *SlicerProviderData, got: %T", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = providerData.Client
+}
+
+func (d *ExecDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data ExecDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	execReq := slicer.SlicerExecRequest{
+		Command: data.Command.ValueString(),
+		UID:     uint32(data.UID.ValueInt64()),
+		GID:     uint32(data.GID.ValueInt64()),
+		Cwd:     data.Workdir.ValueString(),
+		Shell:   data.Shell.ValueString(),
+		Stdout:  true,
+		Stderr:  true,
+	}
+
+	if !data.Args.IsNull() {
+		resp.Diagnostics.Append(data.Args.ElementsAs(ctx, &execReq.Args, false)...)
+	}
+
+	successExitCodes := []int64{0}
+	if !data.SuccessExitCodes.IsNull() {
+		resp.Diagnostics.Append(data.SuccessExitCodes.ElementsAs(ctx, &successExitCodes, false)...)
+		if len(successExitCodes) == 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("success_exit_codes"),
+				"Invalid Attribute Value",
+				"success_exit_codes must contain at least one exit code.",
+			)
+		}
+	}
+
+	if !data.Timeout.IsNull() {
+		timeout, err := time.ParseDuration(data.Timeout.ValueString())
+		if err != nil || timeout <= 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("timeout"),
+				"Invalid Duration",
+				fmt.Sprintf("timeout must be a positive duration such as '30s' or '5m', got: %s", data.Timeout.ValueString()),
+			)
+			return
+		}
+		execReq.Timeout = timeout
+
+		// Stop waiting if the agent does not enforce the timeout itself
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout+execTimeoutSlack)
+		defer cancel()
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	hostname := data.Hostname.ValueString()
+	sensitive := data.SensitiveOutput.ValueBool()
+
+	tflog.Debug(ctx, "Executing read-only command", map[string]interface{}{
+		"hostname": hostname,
+		"command":  execReq.Command,
+	})
+
+	resultChan, err := d.client.Exec(ctx, hostname, execReq)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to execute command on %s: %s", hostname, err))
+		return
+	}
+
+	stdout := &outputBuffer{max: maxExecDataSourceOutputBytes}
+	stderr := &outputBuffer{max: maxExecDataSourceOutputBytes}
+	exitCode := 0
+	var execErr string
+	for result := range resultChan {
+		if result.Error != "" {
+			execErr = result.Error
+		}
+		stdout.WriteString(result.Stdout)
+		stderr.WriteString(result.Stderr)
+		exitCode = result.ExitCode
+	}
+
+	// An exit code is only reported when the command ran; it explains the
+	// failure better than the agent error that may come with it
+	exited := execErr == "" || exitCode != 0
+	if exited && !slices.Contains(successExitCodes, int64(exitCode)) {
+		detail := fmt.Sprintf("The command on %s exited with code %d.", hostname, exitCode)
+		if msg := strings.TrimSpace(stderr.String()); msg != "" && !sensitive {
+			detail += "\n\nStderr:\n" + msg
+		}
+		resp.Diagnostics.AddError("Command Failed", detail)
+		return
+	}
+
+	if execErr != "" {
+		resp.Diagnostics.AddError("Exec Error", fmt.Sprintf("Command on %s failed: %s", hostname, execErr))
+		return
+	}
+
+	if stdout.truncated || stderr.truncated {
+		resp.Diagnostics.AddError(
+			"Output Too Large",
+			fmt.Sprintf("The command on %s printed more than %d bytes. Filter its output on the VM, e.g. with head or jq.", hostname, maxExecDataSourceOutputBytes),
+		)
+		return
+	}
+
+	data.ExitCode = types.Int64Value(int64(exitCode))
+	data.Stdout = types.StringNull()
+	data.Stderr = types.StringNull()
+	data.SensitiveStdout = types.StringNull()
+	data.SensitiveStderr = types.StringNull()
+	if sensitive {
+		data.SensitiveStdout = types.StringValue(stdout.String())
+		data.SensitiveStderr = types.StringValue(stderr.String())
+	} else {
+		data.Stdout = types.StringValue(stdout.String())
+		data.Stderr = types.StringValue(stderr.String())
+	}
+
+	tflog.Trace(ctx, "Executed read-only command", map[string]interface{}{
+		"hostname":  hostname,
+		"exit_code": exitCode,
+	})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,90 @@
+// Copyright (c) German Arutyunov
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// readExecDataSource reads a slicer_exec data source configured by config
+// and returns the resulting state.
+func readExecDataSource(t *testing.T, d *ExecDataSource, config ExecDataSourceModel) (ExecDataSourceModel, *datasource.ReadResponse) {
+	t.Helper()
+	ctx := context.Background()
+
+	var schemaResp datasource.SchemaResponse
+	d.Schema(ctx, datasource.SchemaRequest{}, &schemaResp)
+
+	// Build the raw config value from the model
+	raw := tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)}
+	if diags := raw.Set(ctx, &config); diags.HasError() {
+		t.Fatalf("Unable to build config: %v", diags)
+	}
+
+	req := datasource.ReadRequest{Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: raw.Raw}}
+	resp := &datasource.ReadResponse{State: tfsdk.State{Schema: schemaResp.Schema, Raw: raw.Raw}}
+	d.Read(ctx, req, resp)
+
+	var state ExecDataSourceModel
+	if !resp.Diagnostics.HasError() {
+		resp.Diagnostics.Append(resp.State.Get(ctx, &state)...)
+	}
+	return state, resp
+}
+
+// execDataSourceConfig returns a config running grep with successExitCodes.
+func execDataSourceConfig(successExitCodes types.List) ExecDataSourceModel {
+	return ExecDataSourceModel{
+		Hostname:         types.StringValue("vm-1"),
+		Command:          types.StringValue("grep"),
+		Args:             types.ListNull(types.StringType),
+		UID:              types.Int64Null(),
+		GID:              types.Int64Null(),
+		Workdir:          types.StringNull(),
+		Shell:            types.StringNull(),
+		Timeout:          types.StringNull(),
+		SuccessExitCodes: successExitCodes,
+		SensitiveOutput:  types.BoolNull(),
+		ExitCode:         types.Int64Null(),
+		Stdout:           types.StringNull(),
+		Stderr:           types.StringNull(),
+		SensitiveStdout:  types.StringNull(),
+		SensitiveStderr:  types.StringNull(),
+	}
+}
+
+func TestExecDataSource_SuccessExitCodes(t *testing.T) {
+	client, _ := newExecTestClient(t, 1)
+	d := &ExecDataSource{client: client}
+
+	state, resp := readExecDataSource(t, d, execDataSourceConfig(int64List(0, 1)))
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected error: %v", resp.Diagnostics)
+	}
+	if state.ExitCode.ValueInt64() != 1 {
+		t.Errorf("Want exit_code 1, got %s", state.ExitCode)
+	}
+	if state.Stdout.ValueString() != "attempt 1\n" {
+		t.Errorf("Want stdout %q, got %s", "attempt 1\n", state.Stdout)
+	}
+}
+
+func TestExecDataSource_CommandFailed(t *testing.T) {
+	client, _ := newExecTestClient(t, 1)
+	d := &ExecDataSource{client: client}
+
+	_, resp := readExecDataSource(t, d, execDataSourceConfig(types.ListNull(types.Int64Type)))
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("Want an error for exit code 1")
+	}
+	if summary := resp.Diagnostics.Errors()[0].Summary(); summary != "Command Failed" {
+		t.Errorf("Want 'Command Failed', got '%s'", summary)
+	}
+}
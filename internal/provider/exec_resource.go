@@ -5,12 +5,27 @@ package provider
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/gaarutyunov/terraform-provider-slicer/internal/slicer"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
@@ -21,6 +36,9 @@ import (
 
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &ExecResource{}
+var _ resource.ResourceWithValidateConfig = &ExecResource{}
+var _ resource.ResourceWithModifyPlan = &ExecResource{}
+var _ resource.ResourceWithMoveState = &ExecResource{}
 
 func NewExecResource() resource.Resource {
 	return &ExecResource{}
@@ -28,24 +46,94 @@ func NewExecResource() resource.Resource {
 
 // ExecResource defines the resource implementation.
 type ExecResource struct {
-	client *slicer.SlicerClient
+	client      *slicer.SlicerClient
+	serverInfo  *slicer.SlicerServerInfo
+	tokenScopes []string
+
+	// identities caches the IDs user resolves to, keyed by hostname, so
+	// retries, uploads and checks do not look them up again. The framework
+	// creates a new resource for every operation, so they are never stale.
+	identitiesMu sync.Mutex
+	identities   map[string][2]uint32
 }
 
 // ExecResourceModel describes the resource data model.
 type ExecResourceModel struct {
-	ID       types.String `tfsdk:"id"`
-	Hostname types.String `tfsdk:"hostname"`
-	Command  types.String `tfsdk:"command"`
-	Args     types.List   `tfsdk:"args"`
-	User     types.String `tfsdk:"user"`
-	UID      types.Int64  `tfsdk:"uid"`
-	GID      types.Int64  `tfsdk:"gid"`
-	Workdir  types.String `tfsdk:"workdir"`
-	Shell    types.String `tfsdk:"shell"`
-	Triggers types.Map    `tfsdk:"triggers"`
-	ExitCode types.Int64  `tfsdk:"exit_code"`
-	Stdout   types.String `tfsdk:"stdout"`
-	Stderr   types.String `tfsdk:"stderr"`
+	ID        types.String `tfsdk:"id"`
+	Hostname  types.String `tfsdk:"hostname"`
+	Hostnames types.List   `tfsdk:"hostnames"`
+	Command   types.String `tfsdk:"command"`
+	Args      types.List   `tfsdk:"args"`
+	Script    types.String `tfsdk:"script"`
+	Scripts   types.List   `tfsdk:"scripts"`
+	User      types.String `tfsdk:"user"`
+	UID       types.Int64  `tfsdk:"uid"`
+	GID       types.Int64  `tfsdk:"gid"`
+	Workdir   types.String `tfsdk:"workdir"`
+	Shell     types.String `tfsdk:"shell"`
+	Triggers  types.Map    `tfsdk:"triggers"`
+	ExitCode  types.Int64  `tfsdk:"exit_code"`
+	Stdout    types.String `tfsdk:"stdout"`
+	Stderr    types.String `tfsdk:"stderr"`
+
+	MaxParallel      types.Int64 `tfsdk:"max_parallel"`
+	Results          types.Map   `tfsdk:"results"`
+	SensitiveResults types.Map   `tfsdk:"sensitive_results"`
+
+	ScriptsSHA256 types.String `tfsdk:"scripts_sha256"`
+
+	BinaryOutput types.Bool   `tfsdk:"binary_output"`
+	StdoutBase64 types.String `tfsdk:"stdout_base64"`
+
+	SensitiveOutput       types.Bool   `tfsdk:"sensitive_output"`
+	SensitiveStdout       types.String `tfsdk:"sensitive_stdout"`
+	SensitiveStderr       types.String `tfsdk:"sensitive_stderr"`
+	SensitiveStdoutBase64 types.String `tfsdk:"sensitive_stdout_base64"`
+
+	MaxOutputBytes types.Int64  `tfsdk:"max_output_bytes"`
+	TruncateKeep   types.String `tfsdk:"truncate_keep"`
+	Truncated      types.Bool   `tfsdk:"truncated"`
+
+	UseSudo types.Bool `tfsdk:"use_sudo"`
+
+	Timeout         types.String `tfsdk:"timeout"`
+	KillSignal      types.String `tfsdk:"kill_signal"`
+	KillGracePeriod types.String `tfsdk:"kill_grace_period"`
+
+	Check       *ExecCheckModel `tfsdk:"check"`
+	CheckPassed types.Bool      `tfsdk:"check_passed"`
+
+	Retries       types.Int64     `tfsdk:"retries"`
+	RetryInterval types.String    `tfsdk:"retry_interval"`
+	Until         *ExecUntilModel `tfsdk:"until"`
+
+	SuccessExitCodes types.List `tfsdk:"success_exit_codes"`
+	FailureExitCodes types.List `tfsdk:"failure_exit_codes"`
+	FailOnError      types.Bool `tfsdk:"fail_on_error"`
+
+	Collect []ExecCollectModel `tfsdk:"collect"`
+
+	Timeouts *TimeoutsModel `tfsdk:"timeouts"`
+}
+
+// ExecCollectModel describes a collect block.
+type ExecCollectModel struct {
+	RemotePath  types.String `tfsdk:"remote_path"`
+	LocalPath   types.String `tfsdk:"local_path"`
+	Permissions types.String `tfsdk:"permissions"`
+}
+
+// ExecUntilModel describes the until block.
+type ExecUntilModel struct {
+	ExitCode    types.Int64  `tfsdk:"exit_code"`
+	StdoutRegex types.String `tfsdk:"stdout_regex"`
+}
+
+// ExecCheckModel describes the check block.
+type ExecCheckModel struct {
+	Command          types.String `tfsdk:"command"`
+	ExpectedOutput   types.String `tfsdk:"expected_output"`
+	ExpectedExitCode types.Int64  `tfsdk:"expected_exit_code"`
 }
 
 func (r *ExecResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -65,22 +153,61 @@ func (r *ExecResource) Schema(ctx context.Context, req resource.SchemaRequest, r
 				},
 			},
 			"hostname": schema.StringAttribute{
-				Required:            true,
-				MarkdownDescription: "The hostname of the VM to execute the command on.",
+				Optional:            true,
+				MarkdownDescription: "The hostname of the VM to execute the command on. Exactly one of `hostname` or `hostnames` must be set.",
+			},
+			"hostnames": schema.ListAttribute{
+				Optional:            true,
+				MarkdownDescription: "Hostnames of the VMs to execute the command on, up to `max_parallel` at a time in the order given. Once the command fails on a VM, it is not started on further VMs. Results are reported per VM in `results`. Conflicts with `collect` blocks.",
+				ElementType:         types.StringType,
+			},
+			"max_parallel": schema.Int64Attribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Maximum number of VMs in `hostnames` the command runs on at once. Set to `1` to run on one VM after the other, e.g. for a rolling restart. Defaults to 10.",
+				Default:             int64default.StaticInt64(defaultExecMaxParallel),
+			},
+			"results": schema.MapNestedAttribute{
+				Computed:            true,
+				MarkdownDescription: "Results of the command on each VM in `hostnames`, keyed by hostname. With `sensitive_output`, the output is null here and kept in `sensitive_results`. Null when `hostname` is set.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: execHostResultAttributes(false),
+				},
+			},
+			"sensitive_results": schema.MapNestedAttribute{
+				Computed:            true,
+				Sensitive:           true,
+				MarkdownDescription: "Results of the command on each VM in `hostnames`, keyed by hostname, when `sensitive_output` is true.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: execHostResultAttributes(true),
+				},
 			},
 			"command": schema.StringAttribute{
-				Required:            true,
-				MarkdownDescription: "The command to execute.",
+				Optional:            true,
+				MarkdownDescription: "The command to execute. Exactly one of `command`, `script` or `scripts` must be set.",
 			},
 			"args": schema.ListAttribute{
 				Optional:            true,
-				MarkdownDescription: "Arguments to pass to the command.",
+				MarkdownDescription: "Arguments to pass to the command or `script`.",
+				ElementType:         types.StringType,
+			},
+			"script": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Path of a local script to run instead of `command`. It is uploaded to a temporary path on the VM, made executable, run with `args` and removed afterwards, so it needs a shebang line. Changing its content runs it again.",
+			},
+			"scripts": schema.ListAttribute{
+				Optional:            true,
+				MarkdownDescription: "Paths of local scripts to run in order instead of `command`, stopping at the first that exits non-zero. They are uploaded and removed like `script`. Conflicts with `args`.",
 				ElementType:         types.StringType,
 			},
+			"scripts_sha256": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The hex encoded SHA256 of the content of `script` or `scripts`, used to run them again when they change. Null when `command` is set.",
+			},
 			"user": schema.StringAttribute{
 				Optional:            true,
 				Computed:            true,
-				MarkdownDescription: "User to run the command as (deprecated, use uid instead).",
+				MarkdownDescription: "Name of the user to run the command as, e.g. a service account whose UID differs between images. Resolved to its UID and primary GID with `getent` on each VM before the command runs. Conflicts with `uid` and `gid` unless it is `root`. Defaults to `root`.",
 				Default:             stringdefault.StaticString("root"),
 			},
 			"uid": schema.Int64Attribute{
@@ -95,6 +222,12 @@ func (r *ExecResource) Schema(ctx context.Context, req resource.SchemaRequest, r
 				MarkdownDescription: "Group ID to run the command as. Defaults to 0 (root).",
 				Default:             int64default.StaticInt64(0),
 			},
+			"use_sudo": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "When the Slicer agent is too old to run commands as `uid`/`gid`, run the command as root wrapped in `sudo -u` instead. Has no effect on agents that support `uid` natively. Requires `sudo` in the VM. Defaults to false.",
+				Default:             booldefault.StaticBool(false),
+			},
 			"workdir": schema.StringAttribute{
 				Optional:            true,
 				MarkdownDescription: "Working directory for the command.",
@@ -108,17 +241,164 @@ func (r *ExecResource) Schema(ctx context.Context, req resource.SchemaRequest, r
 				MarkdownDescription: "A map of values that, when changed, will cause the command to re-run.",
 				ElementType:         types.StringType,
 			},
+			"binary_output": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Transport command output base64 encoded so that non-UTF-8 bytes (e.g. from `tar -c`) survive. The raw output is exposed in `stdout_base64`. Defaults to false.",
+				Default:             booldefault.StaticBool(false),
+			},
+			"sensitive_output": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Whether the command prints secrets such as tokens or credentials. The output is then kept in `sensitive_stdout`, `sensitive_stderr` and `sensitive_stdout_base64`, which are hidden in plan output, instead of `stdout`, `stderr` and `stdout_base64`, and it is left out of error messages. Defaults to false.",
+				Default:             booldefault.StaticBool(false),
+			},
+			"max_output_bytes": schema.Int64Attribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Maximum number of bytes of stdout and of stderr kept in state. Longer output is truncated. Defaults to 0 (unlimited).",
+				Default:             int64default.StaticInt64(0),
+			},
+			"truncate_keep": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Which part of the output to keep when it exceeds `max_output_bytes`: `head` or `tail`. Defaults to `tail`.",
+				Default:             stringdefault.StaticString("tail"),
+			},
+			"timeout": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Maximum time the command may run (e.g., '5m'). When it is exceeded the agent sends `kill_signal` to the command and SIGKILL after `kill_grace_period`. The apply then fails with the output produced so far. Defaults to no timeout.",
+			},
+			"kill_signal": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Signal sent to the command when `timeout` is exceeded, e.g. `TERM`, `INT` or `QUIT`. Defaults to `TERM`.",
+				Default:             stringdefault.StaticString("TERM"),
+			},
+			"kill_grace_period": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "How long to wait after `kill_signal` before the command is killed with SIGKILL (e.g., '30s'). Defaults to '10s'.",
+				Default:             stringdefault.StaticString("10s"),
+			},
+			"retries": schema.Int64Attribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Number of times to run the command again when it fails or does not meet `until`, e.g. while an apt lock is held or a service is still starting. Defaults to 0.",
+				Default:             int64default.StaticInt64(0),
+			},
+			"retry_interval": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Time to wait between attempts (e.g., '10s'). Defaults to '5s'.",
+				Default:             stringdefault.StaticString("5s"),
+			},
+			"success_exit_codes": schema.ListAttribute{
+				Optional:            true,
+				MarkdownDescription: "Exit codes that count as success, e.g. `[0, 1]` for `grep`, where 1 means no match. Any other exit code fails the command. Conflicts with `failure_exit_codes`. By default every exit code is accepted, unless `retries` is set, which retries until the command exits 0.",
+				ElementType:         types.Int64Type,
+			},
+			"failure_exit_codes": schema.ListAttribute{
+				Optional:            true,
+				MarkdownDescription: "Exit codes that fail the command; any other exit code counts as success. Conflicts with `success_exit_codes`.",
+				ElementType:         types.Int64Type,
+			},
+			"fail_on_error": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Whether a command that fails by its exit code or does not meet `until` fails the apply. When false, the failure is reported as a warning and `exit_code` and the output are stored as usual, e.g. for a diff-style check whose result is used downstream. Timeouts and errors reaching the VM always fail the apply. Defaults to true.",
+				Default:             booldefault.StaticBool(true),
+			},
+			"truncated": schema.BoolAttribute{
+				Computed:            true,
+				MarkdownDescription: "Whether stdout or stderr was truncated to `max_output_bytes`, on any VM when `hostnames` is set.",
+			},
 			"exit_code": schema.Int64Attribute{
 				Computed:            true,
-				MarkdownDescription: "The exit code of the command.",
+				MarkdownDescription: "The exit code of the command. Null when `hostnames` is set; see `results`.",
 			},
 			"stdout": schema.StringAttribute{
 				Computed:            true,
-				MarkdownDescription: "The standard output of the command.",
+				MarkdownDescription: "The standard output of the command. Null when `hostnames` is set; see `results`.",
 			},
 			"stderr": schema.StringAttribute{
 				Computed:            true,
-				MarkdownDescription: "The standard error of the command.",
+				MarkdownDescription: "The standard error of the command. Null when `hostnames` is set; see `results`.",
+			},
+			"stdout_base64": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The standard output of the command, base64 encoded. Only set when `binary_output` is true.",
+			},
+			"sensitive_stdout": schema.StringAttribute{
+				Computed:            true,
+				Sensitive:           true,
+				MarkdownDescription: "The standard output of the command when `sensitive_output` is true.",
+			},
+			"sensitive_stderr": schema.StringAttribute{
+				Computed:            true,
+				Sensitive:           true,
+				MarkdownDescription: "The standard error of the command when `sensitive_output` is true.",
+			},
+			"sensitive_stdout_base64": schema.StringAttribute{
+				Computed:            true,
+				Sensitive:           true,
+				MarkdownDescription: "The standard output of the command, base64 encoded, when both `sensitive_output` and `binary_output` are true.",
+			},
+			"check_passed": schema.BoolAttribute{
+				Computed:            true,
+				MarkdownDescription: "Whether the `check` command passed when it last ran. Null without a `check` block.",
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeoutsBlock(),
+			"collect": schema.ListNestedBlock{
+				MarkdownDescription: "Files produced by the command to download from the VM after it completes, e.g. reports or generated certificates.",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"remote_path": schema.StringAttribute{
+							Required:            true,
+							MarkdownDescription: "Path of the file on the VM.",
+						},
+						"local_path": schema.StringAttribute{
+							Required:            true,
+							MarkdownDescription: "Local path to write the file to. Missing parent directories are created.",
+						},
+						"permissions": schema.StringAttribute{
+							Optional:            true,
+							MarkdownDescription: "Permissions of the local file (e.g., '0600'). Defaults to '0644'.",
+						},
+					},
+				},
+			},
+			"until": schema.SingleNestedBlock{
+				MarkdownDescription: "The condition an attempt must meet to succeed. An attempt that does not meet it is retried up to `retries` times, after which the apply fails. Without an `until` block, attempts are retried while their exit code fails by `success_exit_codes` or `failure_exit_codes`, or is non-zero if neither is set.",
+				Attributes: map[string]schema.Attribute{
+					"exit_code": schema.Int64Attribute{
+						Optional:            true,
+						MarkdownDescription: "The expected exit code. Defaults to 0.",
+					},
+					"stdout_regex": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "A regular expression the standard output must match. If not set, output is ignored.",
+					},
+				},
+			},
+			"check": schema.SingleNestedBlock{
+				MarkdownDescription: "A command run with `/bin/sh -c` on every refresh to verify that the effect of `command` is still in place. If it fails, `command` is run again on the next apply.",
+				Attributes: map[string]schema.Attribute{
+					"command": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "The check command. Required in a `check` block.",
+					},
+					"expected_output": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "The expected standard output of the check, compared after trimming surrounding whitespace. If not set, output is ignored.",
+					},
+					"expected_exit_code": schema.Int64Attribute{
+						Optional:            true,
+						MarkdownDescription: "The expected exit code of the check. Defaults to 0.",
+					},
+				},
 			},
 		},
 	}
@@ -139,6 +419,293 @@ func (r *ExecResource) Configure(ctx context.Context, req resource.ConfigureRequ
 	}
 
 	r.client = providerData.Client
+	r.tokenScopes = providerData.TokenScopes
+	r.serverInfo = providerData.ServerInfo
+}
+
+func (r *ExecResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	checkScope(req, r.tokenScopes, scopeVMsExec, "slicer_exec", &resp.Diagnostics)
+
+	// Nothing to do on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var binaryOutput types.Bool
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("binary_output"), &binaryOutput)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if binaryOutput.ValueBool() {
+		checkCapability(r.serverInfo, capabilityExecBase64, path.Root("binary_output"), &resp.Diagnostics)
+	}
+
+	var timeout types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("timeout"), &timeout)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !timeout.IsNull() {
+		checkCapability(r.serverInfo, capabilityExecTimeout, path.Root("timeout"), &resp.Diagnostics)
+	}
+
+	scriptsChanged := r.planScriptsHash(ctx, req, resp)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// A check that failed during refresh makes the command run again
+	if req.State.Raw.IsNull() {
+		return
+	}
+
+	if isMoved(ctx, req.Private, &resp.Diagnostics) {
+		resp.Diagnostics.AddWarning(
+			"Adopting Moved Provisioner",
+			"This slicer_exec was moved from a provisioner. Its attributes are filled in from the configuration and the command is not run; it runs again when its triggers change.",
+		)
+		return
+	}
+
+	var checkPassed types.Bool
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("check_passed"), &checkPassed)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !scriptsChanged && (checkPassed.IsNull() || checkPassed.ValueBool()) {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("exit_code"), types.Int64Unknown())...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("stdout"), types.StringUnknown())...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("stderr"), types.StringUnknown())...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("stdout_base64"), types.StringUnknown())...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("sensitive_stdout"), types.StringUnknown())...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("sensitive_stderr"), types.StringUnknown())...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("sensitive_stdout_base64"), types.StringUnknown())...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("truncated"), types.BoolUnknown())...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("check_passed"), types.BoolUnknown())...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("results"), types.MapUnknown(execHostResultType))...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("sensitive_results"), types.MapUnknown(execHostResultType))...)
+}
+
+func (r *ExecResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data ExecResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.MaxOutputBytes.IsNull() && !data.MaxOutputBytes.IsUnknown() && data.MaxOutputBytes.ValueInt64() < 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("max_output_bytes"),
+			"Invalid Max Output Bytes",
+			"max_output_bytes must not be negative.",
+		)
+	}
+
+	durations := []struct {
+		attr  string
+		value types.String
+	}{
+		{"timeout", data.Timeout},
+		{"kill_grace_period", data.KillGracePeriod},
+		{"retry_interval", data.RetryInterval},
+	}
+	for _, duration := range durations {
+		if duration.value.IsNull() || duration.value.IsUnknown() {
+			continue
+		}
+		if d, err := time.ParseDuration(duration.value.ValueString()); err != nil || d < 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(duration.attr),
+				"Invalid Duration",
+				fmt.Sprintf("%s must be a non-negative duration such as '30s' or '5m', got: %s", duration.attr, duration.value.ValueString()),
+			)
+		}
+	}
+
+	if !data.KillSignal.IsNull() && !data.KillSignal.IsUnknown() {
+		if _, ok := normalizeSignal(data.KillSignal.ValueString()); !ok {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("kill_signal"),
+				"Invalid Kill Signal",
+				fmt.Sprintf("kill_signal must be one of %s, got: %s", strings.Join(killSignals, ", "), data.KillSignal.ValueString()),
+			)
+		}
+	}
+
+	if !data.Retries.IsNull() && !data.Retries.IsUnknown() && data.Retries.ValueInt64() < 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("retries"),
+			"Invalid Retries",
+			"retries must not be negative.",
+		)
+	}
+
+	if data.Until != nil && !data.Until.StdoutRegex.IsNull() && !data.Until.StdoutRegex.IsUnknown() {
+		if _, err := regexp.Compile(data.Until.StdoutRegex.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("until").AtName("stdout_regex"),
+				"Invalid Regular Expression",
+				fmt.Sprintf("stdout_regex must be a valid regular expression: %s", err),
+			)
+		}
+	}
+
+	validateTimeouts(data.Timeouts, &resp.Diagnostics)
+
+	if !data.Hostname.IsUnknown() && !data.Hostnames.IsUnknown() && data.Hostname.IsNull() == data.Hostnames.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("hostname"),
+			"Invalid Attribute Combination",
+			"Exactly one of 'hostname' or 'hostnames' must be specified.",
+		)
+	}
+
+	if !data.Hostnames.IsNull() && !data.Hostnames.IsUnknown() {
+		var hostnames []types.String
+		resp.Diagnostics.Append(data.Hostnames.ElementsAs(ctx, &hostnames, false)...)
+
+		if len(hostnames) == 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("hostnames"),
+				"Missing Hostnames",
+				"hostnames must contain at least one hostname.",
+			)
+		}
+
+		seen := map[string]bool{}
+		for _, hostname := range hostnames {
+			if hostname.IsUnknown() {
+				continue
+			}
+			if seen[hostname.ValueString()] {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("hostnames"),
+					"Duplicate Hostname",
+					fmt.Sprintf("hostnames must not contain %s more than once.", hostname.ValueString()),
+				)
+			}
+			seen[hostname.ValueString()] = true
+		}
+	}
+
+	if !data.Hostnames.IsNull() && len(data.Collect) > 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("collect"),
+			"Conflicting Attributes",
+			"collect blocks cannot be used with 'hostnames', since every VM would write the same local files.",
+		)
+	}
+
+	if !data.SuccessExitCodes.IsNull() && !data.FailureExitCodes.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("failure_exit_codes"),
+			"Conflicting Attributes",
+			"Only one of 'success_exit_codes' or 'failure_exit_codes' can be specified.",
+		)
+	}
+
+	for _, exitCodes := range []struct {
+		name  string
+		value types.List
+	}{
+		{"success_exit_codes", data.SuccessExitCodes},
+		{"failure_exit_codes", data.FailureExitCodes},
+	} {
+		if exitCodes.value.IsNull() {
+			continue
+		}
+		if !exitCodes.value.IsUnknown() && len(exitCodes.value.Elements()) == 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(exitCodes.name),
+				"Missing Exit Codes",
+				fmt.Sprintf("%s must contain at least one exit code.", exitCodes.name),
+			)
+		}
+		if data.Until != nil && !data.Until.ExitCode.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("until").AtName("exit_code"),
+				"Conflicting Attributes",
+				fmt.Sprintf("Only one of 'until.exit_code' or '%s' can be specified.", exitCodes.name),
+			)
+		}
+	}
+
+	// user = "root" leaves uid and gid in charge, so it may be set with them
+	if !data.User.IsNull() && !data.User.IsUnknown() && data.User.ValueString() != "root" && (!data.UID.IsNull() || !data.GID.IsNull()) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("user"),
+			"Conflicting Attributes",
+			"Only one of 'user' or 'uid'/'gid' can be specified. The IDs of user are looked up on the VM.",
+		)
+	}
+
+	if !data.User.IsNull() && !data.User.IsUnknown() && data.User.ValueString() == "" {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("user"),
+			"Invalid User",
+			"user must not be empty.",
+		)
+	}
+
+	if !data.MaxParallel.IsNull() && !data.MaxParallel.IsUnknown() && data.MaxParallel.ValueInt64() < 1 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("max_parallel"),
+			"Invalid Max Parallel",
+			"max_parallel must be at least 1.",
+		)
+	}
+
+	commands := 0
+	for _, set := range []bool{!data.Command.IsNull(), !data.Script.IsNull(), !data.Scripts.IsNull()} {
+		if set {
+			commands++
+		}
+	}
+	if commands != 1 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("command"),
+			"Invalid Attribute Combination",
+			"Exactly one of 'command', 'script' or 'scripts' must be specified.",
+		)
+	}
+
+	if !data.Scripts.IsNull() && !data.Args.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("args"),
+			"Conflicting Attributes",
+			"Only one of 'scripts' or 'args' can be specified. Use 'script' to pass arguments to a single script.",
+		)
+	}
+
+	for i, collect := range data.Collect {
+		validatePermissions(collect.Permissions, path.Root("collect").AtListIndex(i).AtName("permissions"), &resp.Diagnostics)
+	}
+
+	if data.Check != nil && data.Check.Command.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("check").AtName("command"),
+			"Missing Check Command",
+			"The check block requires a command.",
+		)
+	}
+
+	if !data.TruncateKeep.IsNull() && !data.TruncateKeep.IsUnknown() {
+		switch data.TruncateKeep.ValueString() {
+		case "head", "tail":
+		default:
+			resp.Diagnostics.AddAttributeError(
+				path.Root("truncate_keep"),
+				"Invalid Truncate Keep Value",
+				fmt.Sprintf("truncate_keep must be either 'head' or 'tail', got: %s", data.TruncateKeep.ValueString()),
+			)
+		}
+	}
 }
 
 func (r *ExecResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -149,18 +716,23 @@ func (r *ExecResource) Create(ctx context.Context, req resource.CreateRequest, r
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, data.Timeouts, timeoutCreate)
+	defer cancel()
+
 	// Execute the command
-	stdout, stderr, exitCode, err := r.executeCommand(ctx, &data)
-	if err != nil {
-		resp.Diagnostics.AddError("Execution Error", fmt.Sprintf("Unable to execute command: %s", err))
+	r.execute(ctx, &data, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.ID = types.StringValue(fmt.Sprintf("%s/%s", strings.Join(execHostnames(ctx, &data), ","), execLabel(ctx, &data)))
+
+	if err := r.collectArtifacts(ctx, &data); err != nil {
+		resp.Diagnostics.AddError("Collect Error", fmt.Sprintf("Unable to collect files: %s", err))
 		return
 	}
 
-	// Set computed values
-	data.ID = types.StringValue(fmt.Sprintf("%s/%s", data.Hostname.ValueString(), data.Command.ValueString()))
-	data.ExitCode = types.Int64Value(int64(exitCode))
-	data.Stdout = types.StringValue(stdout)
-	data.Stderr = types.StringValue(stderr)
+	r.checkAfterExec(ctx, &data, &resp.Diagnostics)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
@@ -173,8 +745,30 @@ func (r *ExecResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
-	// Exec resources are not readable - they represent a one-time execution
-	// Just keep the existing state
+	ctx, cancel := withTimeout(ctx, data.Timeouts, timeoutRead)
+	defer cancel()
+
+	// Exec resources are not readable - they represent a one-time execution.
+	// Without a check the existing state is kept as is.
+	if data.Check != nil {
+		hostname, passed, err := r.runChecks(ctx, &data)
+		if err != nil {
+			// Leave the previous result in place, e.g. while the VM is rebooting
+			resp.Diagnostics.AddWarning(
+				"Check Failed to Run",
+				fmt.Sprintf("Unable to run the check command on %s, keeping the previous result: %s", hostname, err),
+			)
+		} else {
+			if !passed {
+				tflog.Info(ctx, "Exec check failed, the command will run again on the next apply", map[string]interface{}{
+					"hostname": hostname,
+					"command":  execLabel(ctx, &data),
+				})
+			}
+			data.CheckPassed = types.BoolValue(passed)
+		}
+	}
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
@@ -186,29 +780,122 @@ func (r *ExecResource) Update(ctx context.Context, req resource.UpdateRequest, r
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, data.Timeouts, timeoutUpdate)
+	defer cancel()
+
+	// The provisioner this resource was moved from already ran the command
+	if isMoved(ctx, req.Private, &resp.Diagnostics) {
+		var state ExecResourceModel
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		data.ExitCode = state.ExitCode
+		data.Stdout = state.Stdout
+		data.Stderr = state.Stderr
+		data.StdoutBase64 = state.StdoutBase64
+		data.SensitiveStdout = state.SensitiveStdout
+		data.SensitiveStderr = state.SensitiveStderr
+		data.SensitiveStdoutBase64 = state.SensitiveStdoutBase64
+		data.Truncated = state.Truncated
+		data.CheckPassed = state.CheckPassed
+		data.Results = state.Results
+		data.SensitiveResults = state.SensitiveResults
+
+		resp.Diagnostics.Append(resp.Private.SetKey(ctx, movedPrivateKey, nil)...)
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		return
+	}
+
 	// Re-execute the command when triggers change
-	stdout, stderr, exitCode, err := r.executeCommand(ctx, &data)
-	if err != nil {
-		resp.Diagnostics.AddError("Execution Error", fmt.Sprintf("Unable to execute command: %s", err))
+	r.execute(ctx, &data, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.collectArtifacts(ctx, &data); err != nil {
+		resp.Diagnostics.AddError("Collect Error", fmt.Sprintf("Unable to collect files: %s", err))
 		return
 	}
 
-	data.ExitCode = types.Int64Value(int64(exitCode))
-	data.Stdout = types.StringValue(stdout)
-	data.Stderr = types.StringValue(stderr)
+	r.checkAfterExec(ctx, &data, &resp.Diagnostics)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
+func (r *ExecResource) MoveState(ctx context.Context) []resource.StateMover {
+	return []resource.StateMover{
+		{
+			StateMover: func(ctx context.Context, req resource.MoveStateRequest, resp *resource.MoveStateResponse) {
+				moved := parseMovedProvisioner(req, &resp.Diagnostics)
+				if moved == nil {
+					return
+				}
+
+				triggers := types.MapNull(types.StringType)
+				if moved.Triggers != nil {
+					var diags diag.Diagnostics
+					triggers, diags = types.MapValueFrom(ctx, types.StringType, moved.Triggers)
+					resp.Diagnostics.Append(diags...)
+				}
+
+				// The command already ran; the remaining attributes are
+				// filled in from the configuration on the next apply
+				data := ExecResourceModel{
+					ID:          types.StringValue(moved.ID),
+					Hostnames:   types.ListNull(types.StringType),
+					Args:        types.ListNull(types.StringType),
+					Scripts:     types.ListNull(types.StringType),
+					Triggers:    triggers,
+					ExitCode:    types.Int64Value(0),
+					Stdout:      types.StringValue(""),
+					Stderr:      types.StringValue(""),
+					Truncated:   types.BoolValue(false),
+					CheckPassed: types.BoolNull(),
+
+					SensitiveStdout:       types.StringNull(),
+					SensitiveStderr:       types.StringNull(),
+					SensitiveStdoutBase64: types.StringNull(),
+
+					Results:          types.MapNull(execHostResultType),
+					SensitiveResults: types.MapNull(execHostResultType),
+
+					SuccessExitCodes: types.ListNull(types.Int64Type),
+					FailureExitCodes: types.ListNull(types.Int64Type),
+				}
+
+				resp.Diagnostics.Append(resp.TargetState.Set(ctx, &data)...)
+				markMoved(ctx, resp)
+			},
+		},
+	}
+}
+
 func (r *ExecResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 	// Nothing to delete - exec is a one-time operation
 }
 
-func (r *ExecResource) executeCommand(ctx context.Context, data *ExecResourceModel) (stdout, stderr string, exitCode int, err error) {
+// execResult holds the collected output of a command run.
+type execResult struct {
+	Stdout    string
+	Stderr    string
+	ExitCode  int
+	Truncated bool
+}
+
+// executeCommand runs the command once on hostname. scriptPaths are the paths
+// of the uploaded scripts, which are run instead of the command when set.
+func (r *ExecResource) executeCommand(ctx context.Context, data *ExecResourceModel, hostname string, scriptPaths []string) (execResult, error) {
+	uid, gid, err := r.execIdentity(ctx, data, hostname)
+	if err != nil {
+		return execResult{ExitCode: -1}, err
+	}
+
 	execReq := slicer.SlicerExecRequest{
 		Command: data.Command.ValueString(),
-		UID:     uint32(data.UID.ValueInt64()),
-		GID:     uint32(data.GID.ValueInt64()),
+		UID:     uid,
+		GID:     gid,
 		Stdout:  true,
 		Stderr:  true,
 	}
@@ -219,6 +906,14 @@ func (r *ExecResource) executeCommand(ctx context.Context, data *ExecResourceMod
 		execReq.Args = args
 	}
 
+	switch {
+	case len(scriptPaths) == 1:
+		execReq.Command = scriptPaths[0]
+	case len(scriptPaths) > 1:
+		execReq.Command = "/bin/sh"
+		execReq.Args = append([]string{"-c", runScriptsScript, "sh"}, scriptPaths...)
+	}
+
 	if !data.Workdir.IsNull() {
 		execReq.Cwd = data.Workdir.ValueString()
 	}
@@ -227,35 +922,849 @@ func (r *ExecResource) executeCommand(ctx context.Context, data *ExecResourceMod
 		execReq.Shell = data.Shell.ValueString()
 	}
 
+	if data.BinaryOutput.ValueBool() {
+		execReq.OutputEncoding = slicer.ExecOutputEncodingBase64
+	}
+
+	if data.UseSudo.ValueBool() && (execReq.UID != 0 || execReq.GID != 0) && !supportsCapability(r.serverInfo, capabilityExecUID) {
+		tflog.Debug(ctx, "Agent does not support uid, wrapping command in sudo", map[string]interface{}{
+			"uid": execReq.UID,
+			"gid": execReq.GID,
+		})
+		wrapInSudo(&execReq)
+	}
+
+	start := time.Now()
+	if !data.Timeout.IsNull() {
+		// Durations and the signal were checked in ValidateConfig
+		execReq.Timeout, _ = time.ParseDuration(data.Timeout.ValueString())
+		execReq.KillGracePeriod, _ = time.ParseDuration(data.KillGracePeriod.ValueString())
+		execReq.KillSignal, _ = normalizeSignal(data.KillSignal.ValueString())
+
+		// Stop waiting if the agent does not enforce the timeout itself
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, execReq.Timeout+execReq.KillGracePeriod+execTimeoutSlack)
+		defer cancel()
+	}
+
 	tflog.Debug(ctx, "Executing command", map[string]interface{}{
-		"hostname": data.Hostname.ValueString(),
-		"command":  data.Command.ValueString(),
+		"hostname": hostname,
+		"command":  execLabel(ctx, data),
 	})
 
-	resultChan, err := r.client.Exec(ctx, data.Hostname.ValueString(), execReq)
+	resultChan, err := r.client.Exec(ctx, hostname, execReq)
 	if err != nil {
-		return "", "", -1, err
+		return execResult{ExitCode: -1}, err
 	}
 
-	var stdoutBuilder, stderrBuilder strings.Builder
+	maxBytes := int(data.MaxOutputBytes.ValueInt64())
+	keepTail := data.TruncateKeep.ValueString() == "tail"
+	stdoutBuf := &outputBuffer{max: maxBytes, keepTail: keepTail}
+	stderrBuf := &outputBuffer{max: maxBytes, keepTail: keepTail}
 
-	for result := range resultChan {
-		if result.Error != "" {
-			return stdoutBuilder.String(), stderrBuilder.String(), result.ExitCode, fmt.Errorf("exec error: %s", result.Error)
+	collect := func(exitCode int) execResult {
+		return execResult{
+			Stdout:    stdoutBuf.String(),
+			Stderr:    stderrBuf.String(),
+			ExitCode:  exitCode,
+			Truncated: stdoutBuf.truncated || stderrBuf.truncated,
 		}
-		if result.Stdout != "" {
-			stdoutBuilder.WriteString(result.Stdout)
-		}
-		if result.Stderr != "" {
-			stderrBuilder.WriteString(result.Stderr)
+	}
+
+	// A command killed by the agent ends with a non-zero exit code once the
+	// timeout has passed; if the agent never reports back, the deadline ends
+	// the stream instead
+	timedOut := func(exitCode int) bool {
+		if execReq.Timeout == 0 {
+			return false
 		}
+		return errors.Is(ctx.Err(), context.DeadlineExceeded) || exitCode != 0 && time.Since(start) >= execReq.Timeout
+	}
+
+	// Error means the command could not be run to completion; its exit
+	// code is judged by checkUntil
+	exitCode := 0
+	for result := range resultChan {
+		// The last chunk may carry output even when the command failed
+		stdoutBuf.WriteString(result.Stdout)
+		stderrBuf.WriteString(result.Stderr)
 		exitCode = result.ExitCode
+		if result.Error != "" {
+			if timedOut(exitCode) {
+				return collect(exitCode), fmt.Errorf("%w after %s", errExecTimedOut, execReq.Timeout)
+			}
+			return collect(exitCode), fmt.Errorf("exec error: %s", result.Error)
+		}
+	}
+
+	if timedOut(exitCode) {
+		return collect(exitCode), fmt.Errorf("%w after %s", errExecTimedOut, execReq.Timeout)
 	}
 
 	tflog.Trace(ctx, "Command executed", map[string]interface{}{
-		"hostname":  data.Hostname.ValueString(),
+		"hostname":  hostname,
 		"exit_code": exitCode,
+		"truncated": stdoutBuf.truncated || stderrBuf.truncated,
+	})
+
+	return collect(exitCode), nil
+}
+
+// execute runs the command on every VM it targets and stores the results in
+// data. Failures are reported in diags, one error per VM.
+func (r *ExecResource) execute(ctx context.Context, data *ExecResourceModel, diags *diag.Diagnostics) {
+	hostnames := execHostnames(ctx, data)
+	results := make([]execResult, len(hostnames))
+	errs := make([]error, len(hostnames))
+	warnings := make([]error, len(hostnames))
+	started := make([]bool, len(hostnames))
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed bool
+	)
+	sem := make(chan struct{}, max(int(data.MaxParallel.ValueInt64()), 1))
+	for i, hostname := range hostnames {
+		sem <- struct{}{}
+
+		// Do not start on further VMs once the command failed, e.g. so a
+		// rolling restart stops at the first broken VM
+		mu.Lock()
+		stop := failed
+		mu.Unlock()
+		if stop {
+			<-sem
+			break
+		}
+
+		started[i] = true
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			results[i], errs[i] = r.run(ctx, data, hostname)
+
+			// The command ran to completion, so its result is kept
+			if errors.Is(errs[i], errUntilNotMet) && !data.FailOnError.IsNull() && !data.FailOnError.ValueBool() {
+				warnings[i], errs[i] = errs[i], nil
+			}
+			if errs[i] != nil {
+				mu.Lock()
+				failed = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	var skipped []string
+	for i, hostname := range hostnames {
+		switch {
+		case !started[i]:
+			skipped = append(skipped, hostname)
+		case errs[i] != nil:
+			addExecError(data, hostname, results[i], errs[i], diags)
+		case warnings[i] != nil:
+			var failure diag.Diagnostics
+			addExecError(data, hostname, results[i], warnings[i], &failure)
+			for _, d := range failure {
+				diags.AddWarning(d.Summary(), d.Detail())
+			}
+		}
+	}
+	if len(skipped) > 0 {
+		diags.AddError(
+			"Command Not Run",
+			fmt.Sprintf("The command was not run on %s because it failed on another VM.", strings.Join(skipped, ", ")),
+		)
+	}
+	if diags.HasError() {
+		return
+	}
+
+	if data.Hostnames.IsNull() {
+		setExecResult(data, results[0])
+		data.Results = types.MapNull(execHostResultType)
+		data.SensitiveResults = types.MapNull(execHostResultType)
+		return
+	}
+
+	diags.Append(setExecResults(data, hostnames, results)...)
+}
+
+// run uploads the scripts, if any, to hostname, runs the command there with
+// retries and removes the scripts again.
+func (r *ExecResource) run(ctx context.Context, data *ExecResourceModel, hostname string) (execResult, error) {
+	scriptPaths, err := r.uploadScripts(ctx, data, hostname)
+	if err != nil {
+		return execResult{ExitCode: -1}, err
+	}
+	defer r.removeScripts(ctx, hostname, scriptPaths)
+
+	return r.executeWithRetries(ctx, data, hostname, scriptPaths)
+}
+
+// executeWithRetries runs the command on hostname until an attempt succeeds
+// or retries are used up, and returns the result of the last attempt.
+func (r *ExecResource) executeWithRetries(ctx context.Context, data *ExecResourceModel, hostname string, scriptPaths []string) (execResult, error) {
+	retries := int(data.Retries.ValueInt64())
+	// Checked in ValidateConfig
+	interval, _ := time.ParseDuration(data.RetryInterval.ValueString())
+
+	for attempt := 1; ; attempt++ {
+		result, err := r.executeCommand(ctx, data, hostname, scriptPaths)
+		if err == nil {
+			err = checkUntil(ctx, data, result)
+		}
+		if err == nil || retries == 0 {
+			return result, err
+		}
+		if attempt > retries {
+			return result, fmt.Errorf("%w (after %d attempts)", err, attempt)
+		}
+
+		tflog.Info(ctx, "Command failed, retrying", map[string]interface{}{
+			"hostname": hostname,
+			"command":  execLabel(ctx, data),
+			"attempt":  attempt,
+			"retries":  retries,
+			"error":    err.Error(),
+		})
+
+		select {
+		case <-ctx.Done():
+			return result, err
+		case <-time.After(interval):
+		}
+	}
+}
+
+// runScriptsScript runs the scripts given as positional parameters in order
+// and exits with the status of the first that fails.
+const runScriptsScript = `for script; do "$script" || exit; done`
+
+// localScripts returns the local paths of script or scripts, nil when the
+// resource runs a command.
+func localScripts(ctx context.Context, data *ExecResourceModel) []string {
+	if !data.Script.IsNull() {
+		return []string{data.Script.ValueString()}
+	}
+
+	var scripts []string
+	if !data.Scripts.IsNull() {
+		data.Scripts.ElementsAs(ctx, &scripts, false)
+	}
+	return scripts
+}
+
+// execHostnames returns the VMs the command runs on.
+func execHostnames(ctx context.Context, data *ExecResourceModel) []string {
+	if data.Hostnames.IsNull() {
+		return []string{data.Hostname.ValueString()}
+	}
+
+	var hostnames []string
+	data.Hostnames.ElementsAs(ctx, &hostnames, false)
+	return hostnames
+}
+
+// execLabel describes what the resource runs, for the ID and logs.
+func execLabel(ctx context.Context, data *ExecResourceModel) string {
+	if scripts := localScripts(ctx, data); scripts != nil {
+		return strings.Join(scripts, ",")
+	}
+	return data.Command.ValueString()
+}
+
+// uploadScripts copies the local scripts to temporary paths on hostname,
+// owned by the user the command runs as, and returns the paths in order.
+func (r *ExecResource) uploadScripts(ctx context.Context, data *ExecResourceModel, hostname string) ([]string, error) {
+	scripts := localScripts(ctx, data)
+	if len(scripts) == 0 {
+		return nil, nil
+	}
+
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, fmt.Errorf("failed to generate script path: %w", err)
+	}
+	prefix := fmt.Sprintf("/tmp/slicer-exec-%x", suffix)
+
+	uid, gid, err := r.execIdentity(ctx, data, hostname)
+	if err != nil {
+		return nil, err
+	}
+
+	remotePaths := make([]string, 0, len(scripts))
+	for i, script := range scripts {
+		remotePath := fmt.Sprintf("%s-%d-%s", prefix, i, filepath.Base(script))
+
+		tflog.Debug(ctx, "Uploading script", map[string]interface{}{
+			"hostname":    hostname,
+			"script":      script,
+			"remote_path": remotePath,
+		})
+
+		err := r.client.CpToVM(ctx, hostname, script, remotePath, uid, gid, "0700", "binary")
+		if err != nil {
+			r.removeScripts(ctx, hostname, remotePaths)
+			return nil, fmt.Errorf("failed to upload script %s: %w", script, err)
+		}
+		remotePaths = append(remotePaths, remotePath)
+	}
+
+	return remotePaths, nil
+}
+
+// removeScripts deletes uploaded scripts. Failures only warn in the logs,
+// since the scripts live in /tmp.
+func (r *ExecResource) removeScripts(ctx context.Context, hostname string, remotePaths []string) {
+	if len(remotePaths) == 0 {
+		return
+	}
+
+	// Clean up even when the operation was cancelled
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+	defer cancel()
+
+	if _, err := runRemote(ctx, r.client, hostname, "rm", append([]string{"-f", "--"}, remotePaths...)...); err != nil {
+		tflog.Warn(ctx, "Unable to remove uploaded scripts", map[string]interface{}{
+			"hostname": hostname,
+			"paths":    remotePaths,
+			"error":    err.Error(),
+		})
+	}
+}
+
+// planScriptsHash plans scripts_sha256 from the current content of the local
+// scripts and reports whether it differs from the hash in state, i.e. whether
+// the scripts must run again.
+func (r *ExecResource) planScriptsHash(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) bool {
+	var plan ExecResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return false
+	}
+
+	hash := types.StringNull()
+	switch {
+	case plan.Script.IsUnknown() || plan.Scripts.IsUnknown():
+		hash = types.StringUnknown()
+	case !plan.Script.IsNull() || !plan.Scripts.IsNull():
+		h := sha256.New()
+		for _, script := range localScripts(ctx, &plan) {
+			content, err := os.ReadFile(script)
+			if err != nil {
+				attrPath := path.Root("script")
+				if plan.Script.IsNull() {
+					attrPath = path.Root("scripts")
+				}
+				resp.Diagnostics.AddAttributeError(attrPath, "Unable to Read Script", err.Error())
+				return false
+			}
+			h.Write(content)
+		}
+		hash = types.StringValue(fmt.Sprintf("%x", h.Sum(nil)))
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("scripts_sha256"), hash)...)
+
+	if req.State.Raw.IsNull() {
+		return false
+	}
+
+	var stateHash types.String
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("scripts_sha256"), &stateHash)...)
+	return !hash.Equal(stateHash)
+}
+
+// errUntilNotMet is returned when a command does not meet its until condition.
+var errUntilNotMet = errors.New("command did not succeed")
+
+// checkUntil reports whether result meets the until block and the expected
+// exit codes. Without either, a command with retries must exit 0; without
+// retries every exit code is accepted and recorded in exit_code.
+func checkUntil(ctx context.Context, data *ExecResourceModel, result execResult) error {
+	if data.Until == nil && data.Retries.ValueInt64() == 0 && data.SuccessExitCodes.IsNull() && data.FailureExitCodes.IsNull() {
+		return nil
+	}
+
+	if err := checkExitCode(ctx, data, int64(result.ExitCode)); err != nil {
+		return err
+	}
+
+	if data.Until != nil && !data.Until.StdoutRegex.IsNull() {
+		// Checked in ValidateConfig
+		re := regexp.MustCompile(data.Until.StdoutRegex.ValueString())
+		if !re.MatchString(result.Stdout) {
+			return fmt.Errorf("%w: stdout does not match %q", errUntilNotMet, data.Until.StdoutRegex.ValueString())
+		}
+	}
+
+	return nil
+}
+
+// checkExitCode reports whether exitCode counts as success, by until's exit
+// code, success_exit_codes or failure_exit_codes, or else by being 0.
+func checkExitCode(ctx context.Context, data *ExecResourceModel, exitCode int64) error {
+	var codes []int64
+	switch {
+	case data.Until != nil && !data.Until.ExitCode.IsNull():
+		if exitCode != data.Until.ExitCode.ValueInt64() {
+			return fmt.Errorf("%w: exit code %d, expected %d", errUntilNotMet, exitCode, data.Until.ExitCode.ValueInt64())
+		}
+	case !data.SuccessExitCodes.IsNull():
+		data.SuccessExitCodes.ElementsAs(ctx, &codes, false)
+		if !slices.Contains(codes, exitCode) {
+			return fmt.Errorf("%w: exit code %d, expected one of %s", errUntilNotMet, exitCode, formatExitCodes(codes))
+		}
+	case !data.FailureExitCodes.IsNull():
+		data.FailureExitCodes.ElementsAs(ctx, &codes, false)
+		if slices.Contains(codes, exitCode) {
+			return fmt.Errorf("%w: exit code %d is one of failure_exit_codes", errUntilNotMet, exitCode)
+		}
+	case exitCode != 0:
+		return fmt.Errorf("%w: exit code %d, expected 0", errUntilNotMet, exitCode)
+	}
+	return nil
+}
+
+// formatExitCodes lists exit codes for error messages, e.g. "0, 1".
+func formatExitCodes(codes []int64) string {
+	parts := make([]string, len(codes))
+	for i, code := range codes {
+		parts[i] = strconv.FormatInt(code, 10)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// collectArtifacts downloads the files listed in collect blocks from the VM.
+func (r *ExecResource) collectArtifacts(ctx context.Context, data *ExecResourceModel) error {
+	for _, collect := range data.Collect {
+		remotePath := collect.RemotePath.ValueString()
+		localPath := collect.LocalPath.ValueString()
+
+		permissions := "0644"
+		if !collect.Permissions.IsNull() {
+			permissions = collect.Permissions.ValueString()
+		}
+
+		if err := os.MkdirAll(filepath.Dir(localPath), 0o755); err != nil {
+			return fmt.Errorf("failed to create directory for %s: %w", localPath, err)
+		}
+
+		tflog.Debug(ctx, "Collecting file from VM", map[string]interface{}{
+			"hostname":    data.Hostname.ValueString(),
+			"remote_path": remotePath,
+			"local_path":  localPath,
+		})
+
+		if err := r.client.CpFromVM(ctx, data.Hostname.ValueString(), remotePath, localPath, permissions, "binary"); err != nil {
+			return fmt.Errorf("failed to copy %s from VM: %w", remotePath, err)
+		}
+	}
+
+	return nil
+}
+
+// checkAfterExec runs the check after the command so check_passed reflects the
+// new state. A failing check is reported as a warning since the command itself succeeded.
+func (r *ExecResource) checkAfterExec(ctx context.Context, data *ExecResourceModel, diags *diag.Diagnostics) {
+	if data.Check == nil {
+		data.CheckPassed = types.BoolNull()
+		return
+	}
+
+	hostname, passed, err := r.runChecks(ctx, data)
+	if err != nil {
+		diags.AddWarning(
+			"Check Failed to Run",
+			fmt.Sprintf("Unable to run the check command on %s: %s", hostname, err),
+		)
+		passed = false
+	} else if !passed {
+		diags.AddWarning(
+			"Check Did Not Pass",
+			fmt.Sprintf("The check command still fails on %s after running the command. It will run again on the next apply.", hostname),
+		)
+	}
+
+	data.CheckPassed = types.BoolValue(passed)
+}
+
+// runChecks runs the check on every VM the command runs on and reports
+// whether it passed on all of them. When it fails or cannot be run, the
+// hostname of the VM is returned with the result.
+func (r *ExecResource) runChecks(ctx context.Context, data *ExecResourceModel) (string, bool, error) {
+	for _, hostname := range execHostnames(ctx, data) {
+		passed, err := r.runCheck(ctx, data, hostname)
+		if err != nil || !passed {
+			return hostname, passed, err
+		}
+	}
+	return "", true, nil
+}
+
+// execIdentity returns the uid and gid the command runs as on hostname. A
+// user other than root is looked up on the VM, since its IDs may differ
+// between VMs.
+func (r *ExecResource) execIdentity(ctx context.Context, data *ExecResourceModel, hostname string) (uint32, uint32, error) {
+	user := data.User.ValueString()
+	if user == "" || user == "root" {
+		return uint32(data.UID.ValueInt64()), uint32(data.GID.ValueInt64()), nil
+	}
+
+	r.identitiesMu.Lock()
+	ids, ok := r.identities[hostname]
+	r.identitiesMu.Unlock()
+	if ok {
+		return ids[0], ids[1], nil
+	}
+
+	uid, gid, err := lookupUser(ctx, r.client, hostname, user)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	r.identitiesMu.Lock()
+	if r.identities == nil {
+		r.identities = map[string][2]uint32{}
+	}
+	r.identities[hostname] = [2]uint32{uid, gid}
+	r.identitiesMu.Unlock()
+
+	return uid, gid, nil
+}
+
+// runCheck runs the check command on hostname with the same uid and gid as
+// the command and reports whether its exit code and output match the
+// expectations.
+func (r *ExecResource) runCheck(ctx context.Context, data *ExecResourceModel, hostname string) (bool, error) {
+	uid, gid, err := r.execIdentity(ctx, data, hostname)
+	if err != nil {
+		return false, err
+	}
+
+	execReq := slicer.SlicerExecRequest{
+		Command: "/bin/sh",
+		Args:    []string{"-c", data.Check.Command.ValueString()},
+		UID:     uid,
+		GID:     gid,
+		Stdout:  true,
+		Stderr:  true,
+	}
+
+	if data.UseSudo.ValueBool() && (execReq.UID != 0 || execReq.GID != 0) && !supportsCapability(r.serverInfo, capabilityExecUID) {
+		wrapInSudo(&execReq)
+	}
+
+	tflog.Debug(ctx, "Running exec check", map[string]interface{}{
+		"hostname": hostname,
+		"check":    data.Check.Command.ValueString(),
 	})
 
-	return stdoutBuilder.String(), stderrBuilder.String(), exitCode, nil
+	resultChan, err := r.client.Exec(ctx, hostname, execReq)
+	if err != nil {
+		return false, err
+	}
+
+	var stdout strings.Builder
+	exitCode := 0
+	failed := false
+	for result := range resultChan {
+		stdout.WriteString(result.Stdout)
+		exitCode = result.ExitCode
+		failed = failed || result.Error != ""
+	}
+	if failed {
+		// The check could not be run to completion, so it matches no
+		// expected exit code
+		exitCode = -1
+	}
+
+	expectedExitCode := int64(0)
+	if !data.Check.ExpectedExitCode.IsNull() {
+		expectedExitCode = data.Check.ExpectedExitCode.ValueInt64()
+	}
+	if int64(exitCode) != expectedExitCode {
+		return false, nil
+	}
+
+	if !data.Check.ExpectedOutput.IsNull() && strings.TrimSpace(stdout.String()) != strings.TrimSpace(data.Check.ExpectedOutput.ValueString()) {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// wrapInSudo rewrites execReq to run as root and switch to its uid/gid with sudo.
+// A shell command is passed to the shell with -c, since sudo does not start one.
+func wrapInSudo(execReq *slicer.SlicerExecRequest) {
+	args := []string{"-n", "-u", fmt.Sprintf("#%d", execReq.UID)}
+	if execReq.GID != 0 {
+		args = append(args, "-g", fmt.Sprintf("#%d", execReq.GID))
+	}
+	args = append(args, "--")
+
+	if execReq.Shell != "" {
+		command := strings.Join(append([]string{execReq.Command}, execReq.Args...), " ")
+		args = append(args, execReq.Shell, "-c", command)
+	} else {
+		args = append(args, execReq.Command)
+		args = append(args, execReq.Args...)
+	}
+
+	execReq.Command = "sudo"
+	execReq.Args = args
+	execReq.Shell = ""
+	execReq.UID = 0
+	execReq.GID = 0
+}
+
+// errExecTimedOut is returned when a command exceeds its timeout.
+var errExecTimedOut = errors.New("command timed out")
+
+// execErrorOutputBytes limits how much of the output is shown in the
+// diagnostic for a command that timed out.
+const execErrorOutputBytes = 4096
+
+// addExecError reports a command that failed on hostname. Commands that timed
+// out or did not meet their until condition include the output of the last
+// attempt, unless it is sensitive.
+func addExecError(data *ExecResourceModel, hostname string, result execResult, err error, diags *diag.Diagnostics) {
+	var summary string
+	switch {
+	case errors.Is(err, errExecTimedOut):
+		summary = "Command Timed Out"
+	case errors.Is(err, errUntilNotMet):
+		summary = "Command Did Not Succeed"
+	default:
+		diags.AddError("Execution Error", fmt.Sprintf("Unable to execute command on %s: %s", hostname, err))
+		return
+	}
+
+	if data.SensitiveOutput.ValueBool() {
+		diags.AddError(
+			summary,
+			fmt.Sprintf("The command on %s %s. Its output is not shown because sensitive_output is set.", hostname, err),
+		)
+		return
+	}
+
+	diags.AddError(
+		summary,
+		fmt.Sprintf("The command on %s %s.\n\nstdout:\n%s\n\nstderr:\n%s",
+			hostname, err, outputTail(result.Stdout), outputTail(result.Stderr)),
+	)
+}
+
+// outputTail returns the last execErrorOutputBytes of output.
+func outputTail(output string) string {
+	if len(output) > execErrorOutputBytes {
+		output = "..." + output[len(output)-execErrorOutputBytes:]
+	}
+	return strings.ToValidUTF8(strings.TrimSpace(output), "")
+}
+
+// execTimeoutSlack is added to timeout and kill_grace_period before the
+// provider gives up waiting for the agent to report the killed command.
+const execTimeoutSlack = 30 * time.Second
+
+// killSignals lists the signals accepted for kill_signal.
+var killSignals = []string{"TERM", "INT", "QUIT", "HUP", "USR1", "USR2", "KILL"}
+
+// normalizeSignal returns the signal name without the "SIG" prefix in upper case.
+func normalizeSignal(signal string) (string, bool) {
+	name := strings.TrimPrefix(strings.ToUpper(signal), "SIG")
+	for _, s := range killSignals {
+		if s == name {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// outputBuffer collects command output, keeping at most max bytes from either
+// the head or the tail of the stream. A max of 0 means unlimited.
+type outputBuffer struct {
+	max       int
+	keepTail  bool
+	buf       []byte
+	truncated bool
+}
+
+func (b *outputBuffer) WriteString(s string) {
+	if b.max <= 0 {
+		b.buf = append(b.buf, s...)
+		return
+	}
+
+	if !b.keepTail {
+		if room := b.max - len(b.buf); room < len(s) {
+			s = s[:max(room, 0)]
+			b.truncated = true
+		}
+		b.buf = append(b.buf, s...)
+		return
+	}
+
+	b.buf = append(b.buf, s...)
+	// Trim lazily so long streams of small chunks are not copied on every write
+	if len(b.buf) > 2*b.max {
+		b.buf = append(b.buf[:0], b.buf[len(b.buf)-b.max:]...)
+		b.truncated = true
+	}
+}
+
+func (b *outputBuffer) String() string {
+	if b.keepTail && b.max > 0 && len(b.buf) > b.max {
+		b.buf = b.buf[len(b.buf)-b.max:]
+		b.truncated = true
+	}
+	return string(b.buf)
+}
+
+// setExecResult stores the command result in the model. With binary_output the raw
+// stdout is kept in stdout_base64 and stdout holds a UTF-8 safe rendering of it.
+// With sensitive_output the output is stored in the sensitive_ attributes
+// instead.
+func setExecResult(data *ExecResourceModel, result execResult) {
+	data.ExitCode = types.Int64Value(int64(result.ExitCode))
+	data.Truncated = types.BoolValue(result.Truncated)
+
+	var stdout, stderr, stdoutBase64 types.String
+	if data.BinaryOutput.ValueBool() {
+		stdout = types.StringValue(strings.ToValidUTF8(result.Stdout, "\uFFFD"))
+		stderr = types.StringValue(strings.ToValidUTF8(result.Stderr, "\uFFFD"))
+		stdoutBase64 = types.StringValue(base64.StdEncoding.EncodeToString([]byte(result.Stdout)))
+	} else {
+		// Truncation may split a multi-byte character, drop the partial bytes
+		stdout = types.StringValue(strings.ToValidUTF8(result.Stdout, ""))
+		stderr = types.StringValue(strings.ToValidUTF8(result.Stderr, ""))
+		stdoutBase64 = types.StringNull()
+	}
+
+	if data.SensitiveOutput.ValueBool() {
+		data.Stdout = types.StringNull()
+		data.Stderr = types.StringNull()
+		data.StdoutBase64 = types.StringNull()
+		data.SensitiveStdout = stdout
+		data.SensitiveStderr = stderr
+		data.SensitiveStdoutBase64 = stdoutBase64
+		return
+	}
+
+	data.Stdout = stdout
+	data.Stderr = stderr
+	data.StdoutBase64 = stdoutBase64
+	data.SensitiveStdout = types.StringNull()
+	data.SensitiveStderr = types.StringNull()
+	data.SensitiveStdoutBase64 = types.StringNull()
+}
+
+// defaultExecMaxParallel is the default number of VMs in hostnames the
+// command runs on at once.
+const defaultExecMaxParallel = 10
+
+// execHostResultAttrTypes describes the per-VM result objects in results and
+// sensitive_results.
+var execHostResultAttrTypes = map[string]attr.Type{
+	"exit_code":     types.Int64Type,
+	"stdout":        types.StringType,
+	"stderr":        types.StringType,
+	"stdout_base64": types.StringType,
+	"truncated":     types.BoolType,
+}
+
+// execHostResultType is the object type of the per-VM results.
+var execHostResultType = types.ObjectType{AttrTypes: execHostResultAttrTypes}
+
+// execHostResultAttributes returns the schema of a per-VM result.
+func execHostResultAttributes(sensitive bool) map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"exit_code": schema.Int64Attribute{
+			Computed:            true,
+			MarkdownDescription: "The exit code of the command on the VM.",
+		},
+		"stdout": schema.StringAttribute{
+			Computed:            true,
+			Sensitive:           sensitive,
+			MarkdownDescription: "The standard output of the command on the VM.",
+		},
+		"stderr": schema.StringAttribute{
+			Computed:            true,
+			Sensitive:           sensitive,
+			MarkdownDescription: "The standard error of the command on the VM.",
+		},
+		"stdout_base64": schema.StringAttribute{
+			Computed:            true,
+			Sensitive:           sensitive,
+			MarkdownDescription: "The standard output of the command on the VM, base64 encoded. Only set when `binary_output` is true.",
+		},
+		"truncated": schema.BoolAttribute{
+			Computed:            true,
+			MarkdownDescription: "Whether stdout or stderr was truncated to `max_output_bytes`.",
+		},
+	}
+}
+
+// setExecResults stores the results of a command run on hostnames in the
+// results maps. The single-VM output attributes are left null.
+func setExecResults(data *ExecResourceModel, hostnames []string, results []execResult) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	plain := make(map[string]attr.Value, len(hostnames))
+	sensitive := make(map[string]attr.Value, len(hostnames))
+	truncated := false
+	for i, hostname := range hostnames {
+		// Render each result like a single-VM run, including the split
+		// between plain and sensitive output
+		host := *data
+		setExecResult(&host, results[i])
+		truncated = truncated || results[i].Truncated
+
+		object, d := types.ObjectValue(execHostResultAttrTypes, map[string]attr.Value{
+			"exit_code":     host.ExitCode,
+			"stdout":        host.Stdout,
+			"stderr":        host.Stderr,
+			"stdout_base64": host.StdoutBase64,
+			"truncated":     host.Truncated,
+		})
+		diags.Append(d...)
+		plain[hostname] = object
+
+		object, d = types.ObjectValue(execHostResultAttrTypes, map[string]attr.Value{
+			"exit_code":     host.ExitCode,
+			"stdout":        host.SensitiveStdout,
+			"stderr":        host.SensitiveStderr,
+			"stdout_base64": host.SensitiveStdoutBase64,
+			"truncated":     host.Truncated,
+		})
+		diags.Append(d...)
+		sensitive[hostname] = object
+	}
+
+	data.ExitCode = types.Int64Null()
+	data.Stdout = types.StringNull()
+	data.Stderr = types.StringNull()
+	data.StdoutBase64 = types.StringNull()
+	data.SensitiveStdout = types.StringNull()
+	data.SensitiveStderr = types.StringNull()
+	data.SensitiveStdoutBase64 = types.StringNull()
+	data.Truncated = types.BoolValue(truncated)
+
+	resultsValue, d := types.MapValue(execHostResultType, plain)
+	diags.Append(d...)
+	data.Results = resultsValue
+
+	data.SensitiveResults = types.MapNull(execHostResultType)
+	if data.SensitiveOutput.ValueBool() {
+		sensitiveValue, d := types.MapValue(execHostResultType, sensitive)
+		diags.Append(d...)
+		data.SensitiveResults = sensitiveValue
+	}
+
+	return diags
 }
@@ -0,0 +1,407 @@
+// Copyright (c) German Arutyunov
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gaarutyunov/terraform-provider-slicer/internal/slicer"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// newExecTestClient returns a client for an agent whose n-th command prints
+// "attempt n" and exits with exitCodes[n-1], or the last exit code once they
+// are used up. calls counts the commands run.
+func newExecTestClient(t *testing.T, exitCodes ...int) (*slicer.SlicerClient, *atomic.Int32) {
+	t.Helper()
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(calls.Add(1))
+		exitCode := exitCodes[min(n, len(exitCodes))-1]
+		_, _ = fmt.Fprintf(w, "{\"stdout\":\"attempt %d\\n\"}\n{\"exit_code\":%d}\n", n, exitCode)
+	}))
+	t.Cleanup(server.Close)
+
+	return slicer.NewSlicerClient(server.URL, "token", "agent", nil), &calls
+}
+
+// int64List returns a list value of codes.
+func int64List(codes ...int64) types.List {
+	values := make([]attr.Value, len(codes))
+	for i, code := range codes {
+		values[i] = types.Int64Value(code)
+	}
+	return types.ListValueMust(types.Int64Type, values)
+}
+
+func TestExecuteWithRetries_ExitCodePolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    ExecResourceModel
+		wantErr bool
+	}{
+		{
+			name: "any exit code without policy",
+		},
+		{
+			name: "success_exit_codes",
+			data: ExecResourceModel{SuccessExitCodes: int64List(0, 1)},
+		},
+		{
+			name:    "not in success_exit_codes",
+			data:    ExecResourceModel{SuccessExitCodes: int64List(0)},
+			wantErr: true,
+		},
+		{
+			name:    "failure_exit_codes",
+			data:    ExecResourceModel{FailureExitCodes: int64List(1)},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, _ := newExecTestClient(t, 1)
+			r := &ExecResource{client: client}
+
+			tt.data.Command = types.StringValue("grep")
+			result, err := r.executeWithRetries(context.Background(), &tt.data, "vm-1", nil)
+			if result.ExitCode != 1 {
+				t.Errorf("Want exit code 1, got %d", result.ExitCode)
+			}
+			if tt.wantErr != errors.Is(err, errUntilNotMet) {
+				t.Errorf("Want errUntilNotMet: %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestExecute_FailOnErrorFalse(t *testing.T) {
+	client, _ := newExecTestClient(t, 1)
+	r := &ExecResource{client: client}
+
+	data := ExecResourceModel{
+		Hostname:         types.StringValue("vm-1"),
+		Command:          types.StringValue("grep"),
+		SuccessExitCodes: int64List(0),
+		FailOnError:      types.BoolValue(false),
+	}
+
+	var diags diag.Diagnostics
+	r.execute(context.Background(), &data, &diags)
+	if diags.HasError() {
+		t.Fatalf("Unexpected error: %v", diags)
+	}
+	if diags.WarningsCount() != 1 {
+		t.Errorf("Want 1 warning, got %v", diags)
+	}
+	if data.ExitCode.ValueInt64() != 1 {
+		t.Errorf("Want exit_code 1, got %s", data.ExitCode)
+	}
+}
+
+func TestExecuteWithRetries_UntilExitCode(t *testing.T) {
+	client, calls := newExecTestClient(t, 1, 0)
+	r := &ExecResource{client: client}
+
+	data := ExecResourceModel{
+		Command:       types.StringValue("systemctl"),
+		Retries:       types.Int64Value(3),
+		RetryInterval: types.StringValue("1ms"),
+		Until:         &ExecUntilModel{ExitCode: types.Int64Value(0)},
+	}
+
+	result, err := r.executeWithRetries(context.Background(), &data, "vm-1", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("Want 2 attempts, got %d", calls.Load())
+	}
+	if result.ExitCode != 0 || result.Stdout != "attempt 2\n" {
+		t.Errorf("Want the result of attempt 2, got %+v", result)
+	}
+}
+
+func TestExecuteWithRetries_UntilNonZeroExitCode(t *testing.T) {
+	client, calls := newExecTestClient(t, 0, 1)
+	r := &ExecResource{client: client}
+
+	// Wait for a service to stop, i.e. for the check to start failing
+	data := ExecResourceModel{
+		Command:       types.StringValue("pgrep"),
+		Retries:       types.Int64Value(3),
+		RetryInterval: types.StringValue("1ms"),
+		Until:         &ExecUntilModel{ExitCode: types.Int64Value(1)},
+	}
+
+	result, err := r.executeWithRetries(context.Background(), &data, "vm-1", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if calls.Load() != 2 || result.ExitCode != 1 {
+		t.Errorf("Want exit code 1 after 2 attempts, got %d after %d", result.ExitCode, calls.Load())
+	}
+}
+
+func TestExecuteCommand_KilledByTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The agent kills the command once the timeout has passed
+		time.Sleep(100 * time.Millisecond)
+		_, _ = w.Write([]byte(`{"stdout":"still migrating\n","exit_code":143}` + "\n"))
+	}))
+	defer server.Close()
+
+	r := &ExecResource{client: slicer.NewSlicerClient(server.URL, "token", "agent", nil)}
+	data := ExecResourceModel{
+		Command:         types.StringValue("migrate"),
+		Timeout:         types.StringValue("50ms"),
+		KillGracePeriod: types.StringValue("10s"),
+		KillSignal:      types.StringValue("TERM"),
+	}
+
+	result, err := r.executeCommand(context.Background(), &data, "vm-1", nil)
+	if !errors.Is(err, errExecTimedOut) {
+		t.Errorf("Want errExecTimedOut, got %v", err)
+	}
+	if result.ExitCode != 143 {
+		t.Errorf("Want exit code 143, got %d", result.ExitCode)
+	}
+	if result.Stdout != "still migrating\n" {
+		t.Errorf("Want the output of the killed command, got %q", result.Stdout)
+	}
+}
+
+func TestRunCheck_ExpectedExitCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		exitCode int
+		expected types.Int64
+		want     bool
+	}{
+		{name: "exit 0 by default", exitCode: 0, expected: types.Int64Null(), want: true},
+		{name: "non-zero exit by default", exitCode: 1, expected: types.Int64Null(), want: false},
+		{name: "expected non-zero exit", exitCode: 1, expected: types.Int64Value(1), want: true},
+		{name: "unexpected exit 0", exitCode: 0, expected: types.Int64Value(1), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, _ := newExecTestClient(t, tt.exitCode)
+			r := &ExecResource{client: client}
+
+			data := ExecResourceModel{
+				Check: &ExecCheckModel{
+					Command:          types.StringValue("test -f /etc/app.conf"),
+					ExpectedExitCode: tt.expected,
+				},
+			}
+
+			passed, err := r.runCheck(context.Background(), &data, "vm-1")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if passed != tt.want {
+				t.Errorf("Want passed %v, got %v", tt.want, passed)
+			}
+		})
+	}
+}
+
+func TestRunCheck_AgentError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"error":"command not found","exit_code":1}` + "\n"))
+	}))
+	defer server.Close()
+
+	r := &ExecResource{client: slicer.NewSlicerClient(server.URL, "token", "agent", nil)}
+	data := ExecResourceModel{
+		Check: &ExecCheckModel{
+			Command:          types.StringValue("test -f /etc/app.conf"),
+			ExpectedExitCode: types.Int64Value(1),
+		},
+	}
+
+	passed, err := r.runCheck(context.Background(), &data, "vm-1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if passed {
+		t.Error("Want a check that could not run to fail")
+	}
+}
+
+func TestExecuteWithRetries_UserResolvedOnce(t *testing.T) {
+	var lookups, runs atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("cmd") == "getent" {
+			lookups.Add(1)
+			_, _ = w.Write([]byte(`{"stdout":"app:x:1001:1002::/home/app:/bin/sh\n"}` + "\n"))
+			return
+		}
+
+		if q.Get("uid") != "1001" || q.Get("gid") != "1002" {
+			t.Errorf("Want uid 1001 and gid 1002, got %s and %s", q.Get("uid"), q.Get("gid"))
+		}
+		// Fail the first attempt, so the command is retried
+		exitCode := 0
+		if runs.Add(1) == 1 {
+			exitCode = 1
+		}
+		_, _ = fmt.Fprintf(w, "{\"exit_code\":%d}\n", exitCode)
+	}))
+	defer server.Close()
+
+	r := &ExecResource{client: slicer.NewSlicerClient(server.URL, "token", "agent", nil)}
+	data := ExecResourceModel{
+		Command:       types.StringValue("/opt/app/bin/migrate"),
+		User:          types.StringValue("app"),
+		Retries:       types.Int64Value(1),
+		RetryInterval: types.StringValue("1ms"),
+	}
+
+	if _, err := r.executeWithRetries(context.Background(), &data, "vm-1", nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if runs.Load() != 2 {
+		t.Errorf("Want 2 attempts, got %d", runs.Load())
+	}
+	if lookups.Load() != 1 {
+		t.Errorf("Want user looked up once, got %d lookups", lookups.Load())
+	}
+}
+
+func TestCheckExitCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     ExecResourceModel
+		exitCode int64
+		wantErr  bool
+	}{
+		{name: "zero by default", exitCode: 0},
+		{name: "non-zero by default", exitCode: 1, wantErr: true},
+		{
+			name:     "until exit code met",
+			data:     ExecResourceModel{Until: &ExecUntilModel{ExitCode: types.Int64Value(2)}},
+			exitCode: 2,
+		},
+		{
+			name:     "until exit code not met by zero",
+			data:     ExecResourceModel{Until: &ExecUntilModel{ExitCode: types.Int64Value(2)}},
+			exitCode: 0,
+			wantErr:  true,
+		},
+		{
+			name:     "until without exit code",
+			data:     ExecResourceModel{Until: &ExecUntilModel{StdoutRegex: types.StringValue("ready")}},
+			exitCode: 1,
+			wantErr:  true,
+		},
+		{
+			name:     "one of success_exit_codes",
+			data:     ExecResourceModel{SuccessExitCodes: int64List(0, 3)},
+			exitCode: 3,
+		},
+		{
+			name:     "not one of success_exit_codes",
+			data:     ExecResourceModel{SuccessExitCodes: int64List(0, 3)},
+			exitCode: 1,
+			wantErr:  true,
+		},
+		{
+			name:     "not one of failure_exit_codes",
+			data:     ExecResourceModel{FailureExitCodes: int64List(2)},
+			exitCode: 1,
+		},
+		{
+			name:     "one of failure_exit_codes",
+			data:     ExecResourceModel{FailureExitCodes: int64List(2)},
+			exitCode: 2,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkExitCode(context.Background(), &tt.data, tt.exitCode)
+			if tt.wantErr {
+				if !errors.Is(err, errUntilNotMet) {
+					t.Errorf("Want errUntilNotMet, got %v", err)
+				}
+			} else if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestCheckUntil(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    ExecResourceModel
+		result  execResult
+		wantErr bool
+	}{
+		{
+			name:   "no policy leaves non-zero exits to executeCommand",
+			result: execResult{ExitCode: 1},
+		},
+		{
+			name:    "retries fail on non-zero exits",
+			data:    ExecResourceModel{Retries: types.Int64Value(2)},
+			result:  execResult{ExitCode: 1},
+			wantErr: true,
+		},
+		{
+			name: "stdout matches",
+			data: ExecResourceModel{Until: &ExecUntilModel{
+				ExitCode:    types.Int64Null(),
+				StdoutRegex: types.StringValue(`^ready`),
+			}},
+			result: execResult{Stdout: "ready\n"},
+		},
+		{
+			name: "stdout does not match",
+			data: ExecResourceModel{Until: &ExecUntilModel{
+				ExitCode:    types.Int64Null(),
+				StdoutRegex: types.StringValue(`^ready`),
+			}},
+			result:  execResult{Stdout: "starting\n"},
+			wantErr: true,
+		},
+		{
+			name: "exit code met but stdout does not match",
+			data: ExecResourceModel{Until: &ExecUntilModel{
+				ExitCode:    types.Int64Value(1),
+				StdoutRegex: types.StringValue(`^ready`),
+			}},
+			result:  execResult{Stdout: "starting\n", ExitCode: 1},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkUntil(context.Background(), &tt.data, tt.result)
+			if tt.wantErr {
+				if !errors.Is(err, errUntilNotMet) {
+					t.Errorf("Want errUntilNotMet, got %v", err)
+				}
+			} else if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -7,11 +7,18 @@ import (
 	"context"
 	"crypto/sha256"
 	"fmt"
+	"io"
 	"os"
+	posixpath "path"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gaarutyunov/terraform-provider-slicer/internal/slicer"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
@@ -22,6 +29,16 @@ import (
 
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &FileResource{}
+var _ resource.ResourceWithValidateConfig = &FileResource{}
+var _ resource.ResourceWithModifyPlan = &FileResource{}
+var _ resource.ResourceWithMoveState = &FileResource{}
+
+const (
+	// progressLogThreshold is the upload size from which progress is logged.
+	progressLogThreshold = 64 * 1024 * 1024
+	// progressLogInterval is how often upload progress is logged.
+	progressLogInterval = 10 * time.Second
+)
 
 func NewFileResource() resource.Resource {
 	return &FileResource{}
@@ -29,7 +46,9 @@ func NewFileResource() resource.Resource {
 
 // FileResource defines the resource implementation.
 type FileResource struct {
-	client *slicer.SlicerClient
+	client           *slicer.SlicerClient
+	permissionPolicy PermissionPolicy
+	tokenScopes      []string
 }
 
 // FileResourceModel describes the resource data model.
@@ -39,10 +58,31 @@ type FileResourceModel struct {
 	Destination types.String `tfsdk:"destination"`
 	Content     types.String `tfsdk:"content"`
 	Source      types.String `tfsdk:"source"`
+
+	SensitiveContent types.Bool   `tfsdk:"sensitive_content"`
+	PlainContent     types.String `tfsdk:"plain_content"`
+
 	Permissions types.String `tfsdk:"permissions"`
+	Mode        types.Int64  `tfsdk:"mode"`
 	Owner       types.Int64  `tfsdk:"owner"`
 	Group       types.Int64  `tfsdk:"group"`
+	OwnerName   types.String `tfsdk:"owner_name"`
+	GroupName   types.String `tfsdk:"group_name"`
+	Compress    types.Bool   `tfsdk:"compress"`
+	MaxSizeMB   types.Int64  `tfsdk:"max_size_mb"`
+
+	CreateParents     types.Bool   `tfsdk:"create_parents"`
+	ParentPermissions types.String `tfsdk:"parent_permissions"`
+	ParentOwner       types.Int64  `tfsdk:"parent_owner"`
+	ParentGroup       types.Int64  `tfsdk:"parent_group"`
+
+	Immutable       types.Bool `tfsdk:"immutable"`
+	ChattrImmutable types.Bool `tfsdk:"chattr_immutable"`
+	Backup          types.Bool `tfsdk:"backup"`
+
 	ContentHash types.String `tfsdk:"content_hash"`
+
+	Timeouts *TimeoutsModel `tfsdk:"timeouts"`
 }
 
 func (r *FileResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -71,36 +111,113 @@ func (r *FileResource) Schema(ctx context.Context, req resource.SchemaRequest, r
 			},
 			"content": schema.StringAttribute{
 				Optional:            true,
-				MarkdownDescription: "The content of the file. Conflicts with `source`.",
+				MarkdownDescription: "The content of the file. Always hidden in plan output. Conflicts with `plain_content` and `source`.",
 				Sensitive:           true,
 			},
+			"sensitive_content": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Whether the file content is secret. Set to false to give the content in `plain_content`, whose changes are shown in plan diffs. Defaults to true.",
+				Default:             booldefault.StaticBool(true),
+			},
+			"plain_content": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "The content of a non-secret file, such as a motd or an nginx config, shown in plan diffs. Requires `sensitive_content = false`. Conflicts with `content` and `source`.",
+			},
 			"source": schema.StringAttribute{
 				Optional:            true,
-				MarkdownDescription: "The local source file path. Conflicts with `content`.",
+				MarkdownDescription: "The local source file path. Conflicts with `content` and `plain_content`.",
 			},
 			"permissions": schema.StringAttribute{
 				Optional:            true,
 				Computed:            true,
-				MarkdownDescription: "File permissions (e.g., '0644').",
+				MarkdownDescription: "File permissions as an octal string with a leading zero (e.g., '0644'). Conflicts with `mode`. Defaults to '0644'.",
 				Default:             stringdefault.StaticString("0644"),
 			},
+			"mode": schema.Int64Attribute{
+				Optional:            true,
+				MarkdownDescription: "File permissions as a number, e.g. `parseint(\"0644\", 8)`. Sets `permissions`. Conflicts with `permissions`.",
+			},
 			"owner": schema.Int64Attribute{
 				Optional:            true,
 				Computed:            true,
-				MarkdownDescription: "Owner UID. Defaults to 0 (root).",
+				MarkdownDescription: "Owner UID. Conflicts with `owner_name`. Defaults to 0 (root).",
 				Default:             int64default.StaticInt64(0),
 			},
 			"group": schema.Int64Attribute{
 				Optional:            true,
 				Computed:            true,
-				MarkdownDescription: "Group GID. Defaults to 0 (root).",
+				MarkdownDescription: "Group GID. Conflicts with `group_name`. Defaults to 0 (root).",
+				Default:             int64default.StaticInt64(0),
+			},
+			"owner_name": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Owner user name, resolved to `owner` on the VM, e.g. for service accounts whose UID differs between images. Conflicts with `owner`.",
+			},
+			"group_name": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Group name, resolved to `group` on the VM. Conflicts with `group`.",
+			},
+			"compress": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Gzip-compress the file in transit. Speeds up text-heavy uploads over slow links; falls back to an uncompressed upload if the VM agent does not support it. Defaults to false.",
+				Default:             booldefault.StaticBool(false),
+			},
+			"max_size_mb": schema.Int64Attribute{
+				Optional:            true,
+				MarkdownDescription: "Refuse to copy content larger than this many MiB, e.g. to catch a `source` pointing at the wrong build artifact. The plan fails when the limit is exceeded. If not set, there is no limit.",
+			},
+			"create_parents": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Create the destination directory, including missing parents, before copying. Existing directories are left untouched. Defaults to true.",
+				Default:             booldefault.StaticBool(true),
+			},
+			"parent_permissions": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Permissions of the destination directory when it is created (e.g., '0750'). Defaults to '0755'.",
+				Default:             stringdefault.StaticString("0755"),
+			},
+			"parent_owner": schema.Int64Attribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Owner UID of the destination directory when it is created. Defaults to 0 (root).",
 				Default:             int64default.StaticInt64(0),
 			},
+			"parent_group": schema.Int64Attribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Group GID of the destination directory when it is created. Defaults to 0 (root).",
+				Default:             int64default.StaticInt64(0),
+			},
+			"immutable": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Replace the file instead of overwriting it in place when `content` or `source` changes, so running services never read a partially written file. Defaults to false.",
+				Default:             booldefault.StaticBool(false),
+			},
+			"chattr_immutable": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Set the immutable attribute (`chattr +i`) on the file after writing it, so it cannot be changed on the VM. The provider clears it before updating or deleting the file. Requires a filesystem that supports it. Defaults to false.",
+				Default:             booldefault.StaticBool(false),
+			},
+			"backup": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Keep the previous file as `<destination>.bak` when it is overwritten. The backup is left on the VM when the resource is deleted. Defaults to false.",
+				Default:             booldefault.StaticBool(false),
+			},
 			"content_hash": schema.StringAttribute{
 				Computed:            true,
-				MarkdownDescription: "SHA256 hash of the file content.",
+				MarkdownDescription: "SHA256 hash of the file content. Refreshed from the VM, so a file modified or removed outside of Terraform is rewritten on the next apply.",
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeoutsBlock(),
+		},
 	}
 }
 
@@ -119,6 +236,171 @@ func (r *FileResource) Configure(ctx context.Context, req resource.ConfigureRequ
 	}
 
 	r.client = providerData.Client
+	r.tokenScopes = providerData.TokenScopes
+	r.permissionPolicy = providerData.PermissionPolicy
+}
+
+func (r *FileResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data FileResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	validatePermissions(data.Permissions, path.Root("permissions"), &resp.Diagnostics)
+	validatePermissions(data.ParentPermissions, path.Root("parent_permissions"), &resp.Diagnostics)
+	validateMode(data.Mode, path.Root("mode"), &resp.Diagnostics)
+	validateTimeouts(data.Timeouts, &resp.Diagnostics)
+
+	if !data.MaxSizeMB.IsNull() && !data.MaxSizeMB.IsUnknown() && data.MaxSizeMB.ValueInt64() < 1 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("max_size_mb"),
+			"Invalid Attribute Value",
+			fmt.Sprintf("max_size_mb must be at least 1, got: %d", data.MaxSizeMB.ValueInt64()),
+		)
+	}
+
+	if !data.Permissions.IsNull() && !data.Mode.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("mode"),
+			"Conflicting Attributes",
+			"Only one of 'permissions' or 'mode' can be specified.",
+		)
+	}
+
+	if !data.Owner.IsNull() && !data.OwnerName.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("owner_name"),
+			"Conflicting Attributes",
+			"Only one of 'owner' or 'owner_name' can be specified.",
+		)
+	}
+
+	if !data.Group.IsNull() && !data.GroupName.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("group_name"),
+			"Conflicting Attributes",
+			"Only one of 'group' or 'group_name' can be specified.",
+		)
+	}
+
+	// Sensitivity is fixed per attribute in the schema, so non-secret content
+	// goes in its own attribute
+	if data.SensitiveContent.IsUnknown() {
+		return
+	}
+	sensitive := data.SensitiveContent.IsNull() || data.SensitiveContent.ValueBool()
+	if sensitive && !data.PlainContent.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("plain_content"),
+			"Sensitive Content Not Disabled",
+			"Set sensitive_content = false to use plain_content, or use content for secret files.",
+		)
+	}
+	if !sensitive && !data.Content.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("content"),
+			"Sensitive Content Disabled",
+			"content is always hidden in plan output. Use plain_content when sensitive_content is false.",
+		)
+	}
+}
+
+func (r *FileResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	checkScope(req, r.tokenScopes, scopeVMsExec, "slicer_file", &resp.Diagnostics)
+
+	// Nothing to do on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var mode types.Int64
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("mode"), &mode)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !mode.IsNull() && !mode.IsUnknown() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("permissions"), modeToPermissions(mode.ValueInt64()))...)
+	}
+
+	var plan FileResourceModel
+	resp.Diagnostics.Append(resp.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	checkPermissionLimit(r.permissionPolicy.MaxFile, plan.Permissions, "File", path.Root("permissions"), &resp.Diagnostics)
+
+	if err := checkContentSize(&plan); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("max_size_mb"), "File Too Large", err.Error())
+	}
+	if plan.CreateParents.ValueBool() {
+		checkPermissionLimit(r.permissionPolicy.MaxDirectory, plan.ParentPermissions, "Directory", path.Root("parent_permissions"), &resp.Diagnostics)
+	}
+
+	var state *FileResourceModel
+	if !req.State.Raw.IsNull() {
+		state = &FileResourceModel{}
+		resp.Diagnostics.Append(req.State.Get(ctx, state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// IDs resolved from names are only known after the lookup, so keep the
+	// previous ID unless the name or the VM it is resolved on changed.
+	if !plan.OwnerName.IsNull() {
+		owner := types.Int64Unknown()
+		if state != nil && state.OwnerName.Equal(plan.OwnerName) && state.Hostname.Equal(plan.Hostname) {
+			owner = state.Owner
+		}
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("owner"), owner)...)
+	}
+
+	if !plan.GroupName.IsNull() {
+		group := types.Int64Unknown()
+		if state != nil && state.GroupName.Equal(plan.GroupName) && state.Hostname.Equal(plan.Hostname) {
+			group = state.Group
+		}
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("group"), group)...)
+	}
+
+	if state == nil {
+		return
+	}
+
+	// The first apply after a move writes the file in place
+	if isMoved(ctx, req.Private, &resp.Diagnostics) {
+		resp.Diagnostics.AddWarning(
+			"Adopting Moved Provisioner",
+			"This slicer_file was moved from a provisioner. The file is written in place on the next apply.",
+		)
+		return
+	}
+
+	// Rewrite files that were modified or removed on the VM
+	if plan.ContentHash.Equal(state.ContentHash) {
+		if contentHash, ok := configuredContentHash(&plan); ok && contentHash != state.ContentHash.ValueString() {
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("content_hash"), types.StringUnknown())...)
+		}
+	}
+
+	// Immutable files are replaced rather than rewritten when their content changes
+	if !plan.Immutable.ValueBool() {
+		return
+	}
+
+	if !plan.Content.Equal(state.Content) {
+		resp.RequiresReplace = append(resp.RequiresReplace, path.Root("content"))
+	}
+	if !plan.PlainContent.Equal(state.PlainContent) {
+		resp.RequiresReplace = append(resp.RequiresReplace, path.Root("plain_content"))
+	}
+	if !plan.Source.Equal(state.Source) {
+		resp.RequiresReplace = append(resp.RequiresReplace, path.Root("source"))
+	}
 }
 
 func (r *FileResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -129,23 +411,38 @@ func (r *FileResource) Create(ctx context.Context, req resource.CreateRequest, r
 		return
 	}
 
-	// Validate that either content or source is specified
-	if data.Content.IsNull() && data.Source.IsNull() {
+	ctx, cancel := withTimeout(ctx, data.Timeouts, timeoutCreate)
+	defer cancel()
+
+	// Validate that exactly one of content, plain_content or source is specified
+	contentSources := 0
+	for _, v := range []types.String{data.Content, data.PlainContent, data.Source} {
+		if !v.IsNull() {
+			contentSources++
+		}
+	}
+
+	if contentSources == 0 {
 		resp.Diagnostics.AddError(
 			"Missing File Content",
-			"Either 'content' or 'source' must be specified.",
+			"One of 'content', 'plain_content' or 'source' must be specified.",
 		)
 		return
 	}
 
-	if !data.Content.IsNull() && !data.Source.IsNull() {
+	if contentSources > 1 {
 		resp.Diagnostics.AddError(
 			"Conflicting Attributes",
-			"Only one of 'content' or 'source' can be specified.",
+			"Only one of 'content', 'plain_content' or 'source' can be specified.",
 		)
 		return
 	}
 
+	if err := r.resolveOwnership(ctx, &data); err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to resolve file owner: %s", err))
+		return
+	}
+
 	// Copy file to VM
 	contentHash, err := r.copyFile(ctx, &data)
 	if err != nil {
@@ -168,19 +465,70 @@ func (r *FileResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
-	// File resources are not fully readable from the VM
-	// We keep the existing state
+	ctx, cancel := withTimeout(ctx, data.Timeouts, timeoutRead)
+	defer cancel()
+
+	// Moved provisioners have no destination until their first apply
+	if data.Destination.IsNull() || data.ContentHash.IsNull() {
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		return
+	}
+
+	// Only the content is compared; ModifyPlan plans an update when the
+	// remote hash no longer matches the configured content
+	remoteHash, err := r.remoteContentHash(ctx, &data)
+	if err != nil {
+		resp.Diagnostics.AddWarning(
+			"Drift Check Skipped",
+			fmt.Sprintf("Unable to checksum %s on %s, keeping the existing state: %s", data.Destination.ValueString(), data.Hostname.ValueString(), err),
+		)
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		return
+	}
+
+	if remoteHash != data.ContentHash.ValueString() {
+		tflog.Debug(ctx, "File changed outside of Terraform", map[string]interface{}{
+			"hostname":     data.Hostname.ValueString(),
+			"destination":  data.Destination.ValueString(),
+			"content_hash": remoteHash,
+		})
+	}
+
+	// A removed file has no hash
+	if remoteHash == "" {
+		data.ContentHash = types.StringNull()
+	} else {
+		data.ContentHash = types.StringValue(remoteHash)
+	}
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *FileResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var data FileResourceModel
+	var data, state FileResourceModel
 
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, data.Timeouts, timeoutUpdate)
+	defer cancel()
+
+	// chattr fails on a file that was removed outside of Terraform
+	if state.ChattrImmutable.ValueBool() && !state.ContentHash.IsNull() {
+		if err := r.setImmutableAttribute(ctx, &state, false); err != nil {
+			resp.Diagnostics.AddError("Copy Error", fmt.Sprintf("Unable to update file: %s", err))
+			return
+		}
+	}
+
+	if err := r.resolveOwnership(ctx, &data); err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to resolve file owner: %s", err))
+		return
+	}
+
 	// Re-copy the file
 	contentHash, err := r.copyFile(ctx, &data)
 	if err != nil {
@@ -190,9 +538,33 @@ func (r *FileResource) Update(ctx context.Context, req resource.UpdateRequest, r
 
 	data.ContentHash = types.StringValue(contentHash)
 
+	// Applied at least once, a moved file is no longer special
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, movedPrivateKey, nil)...)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
+func (r *FileResource) MoveState(ctx context.Context) []resource.StateMover {
+	return []resource.StateMover{
+		{
+			StateMover: func(ctx context.Context, req resource.MoveStateRequest, resp *resource.MoveStateResponse) {
+				moved := parseMovedProvisioner(req, &resp.Diagnostics)
+				if moved == nil {
+					return
+				}
+
+				// The remaining attributes are filled in from the
+				// configuration on the next apply
+				data := FileResourceModel{
+					ID: types.StringValue(moved.ID),
+				}
+
+				resp.Diagnostics.Append(resp.TargetState.Set(ctx, &data)...)
+				markMoved(ctx, resp)
+			},
+		},
+	}
+}
+
 func (r *FileResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 	var data FileResourceModel
 
@@ -201,6 +573,16 @@ func (r *FileResource) Delete(ctx context.Context, req resource.DeleteRequest, r
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, data.Timeouts, timeoutDelete)
+	defer cancel()
+
+	if data.ChattrImmutable.ValueBool() {
+		if err := r.setImmutableAttribute(ctx, &data, false); err != nil {
+			resp.Diagnostics.AddWarning("Delete Warning", fmt.Sprintf("Unable to delete file: %s", err))
+			return
+		}
+	}
+
 	// Delete the file from VM by executing rm command
 	execReq := slicer.SlicerExecRequest{
 		Command: "rm",
@@ -225,57 +607,228 @@ func (r *FileResource) Delete(ctx context.Context, req resource.DeleteRequest, r
 	})
 }
 
-func (r *FileResource) copyFile(ctx context.Context, data *FileResourceModel) (string, error) {
-	var content []byte
-	var err error
+// resolveOwnership looks up owner_name and group_name on the VM and stores
+// the numeric IDs in data. IDs already known from state are kept.
+func (r *FileResource) resolveOwnership(ctx context.Context, data *FileResourceModel) error {
+	if !data.OwnerName.IsNull() && data.Owner.IsUnknown() {
+		uid, err := lookupID(ctx, r.client, data.Hostname.ValueString(), "passwd", data.OwnerName.ValueString())
+		if err != nil {
+			return err
+		}
+		data.Owner = types.Int64Value(uid)
+	}
+
+	if !data.GroupName.IsNull() && data.Group.IsUnknown() {
+		gid, err := lookupID(ctx, r.client, data.Hostname.ValueString(), "group", data.GroupName.ValueString())
+		if err != nil {
+			return err
+		}
+		data.Group = types.Int64Value(gid)
+	}
+
+	return nil
+}
 
+// openContent opens the configured content of the file. A source file is
+// streamed rather than read into memory, so large artifacts can be copied.
+func openContent(data *FileResourceModel) (io.ReadCloser, int64, error) {
 	if !data.Content.IsNull() {
-		content = []byte(data.Content.ValueString())
-	} else {
-		content, err = os.ReadFile(data.Source.ValueString())
+		return io.NopCloser(strings.NewReader(data.Content.ValueString())), int64(len(data.Content.ValueString())), nil
+	}
+	if !data.PlainContent.IsNull() {
+		return io.NopCloser(strings.NewReader(data.PlainContent.ValueString())), int64(len(data.PlainContent.ValueString())), nil
+	}
+
+	f, err := os.Open(data.Source.ValueString())
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read source file: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, 0, fmt.Errorf("failed to stat source file: %w", err)
+	}
+	return f, info.Size(), nil
+}
+
+// contentHash returns the SHA256 hash and size of the configured content.
+func contentHash(data *FileResourceModel) (string, int64, error) {
+	content, size, err := openContent(data)
+	if err != nil {
+		return "", 0, err
+	}
+	defer content.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, content); err != nil {
+		return "", 0, fmt.Errorf("failed to read source file: %w", err)
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil)), size, nil
+}
+
+// checkContentSize returns an error if the configured content is known and
+// larger than max_size_mb.
+func checkContentSize(data *FileResourceModel) error {
+	if data.MaxSizeMB.IsNull() || data.MaxSizeMB.IsUnknown() || data.Content.IsUnknown() || data.PlainContent.IsUnknown() || data.Source.IsUnknown() {
+		return nil
+	}
+	if data.Content.IsNull() && data.PlainContent.IsNull() && data.Source.IsNull() {
+		return nil
+	}
+
+	var size int64
+	switch {
+	case !data.Content.IsNull():
+		size = int64(len(data.Content.ValueString()))
+	case !data.PlainContent.IsNull():
+		size = int64(len(data.PlainContent.ValueString()))
+	default:
+		info, err := os.Stat(data.Source.ValueString())
 		if err != nil {
-			return "", fmt.Errorf("failed to read source file: %w", err)
+			// The source may only be created during the apply
+			return nil
 		}
+		size = info.Size()
+	}
+
+	if limit := data.MaxSizeMB.ValueInt64() << 20; size > limit {
+		return fmt.Errorf("the content is %d bytes, more than max_size_mb = %d allows", size, data.MaxSizeMB.ValueInt64())
 	}
+	return nil
+}
 
-	// Calculate content hash
-	hash := sha256.Sum256(content)
-	contentHash := fmt.Sprintf("%x", hash)
+// configuredContentHash returns the hash of the configured content. ok is
+// false while the content is unknown or cannot be read yet.
+func configuredContentHash(data *FileResourceModel) (string, bool) {
+	for _, v := range []types.String{data.Content, data.PlainContent, data.Source} {
+		if v.IsUnknown() {
+			return "", false
+		}
+	}
+	if data.Content.IsNull() && data.PlainContent.IsNull() && data.Source.IsNull() {
+		return "", false
+	}
 
-	// Write content to temp file
-	tmpFile, err := os.CreateTemp("", "slicer-file-*")
+	hash, _, err := contentHash(data)
 	if err != nil {
-		return "", fmt.Errorf("failed to create temp file: %w", err)
+		return "", false
 	}
-	defer os.Remove(tmpFile.Name())
+	return hash, true
+}
 
-	if _, err := tmpFile.Write(content); err != nil {
+// remoteContentHashScript prints the SHA256 hash of file $1, or nothing if
+// the file does not exist.
+const remoteContentHashScript = `if [ -e "$1" ]; then sha256sum < "$1"; fi`
+
+// remoteContentHash returns the SHA256 hash of the destination file on the
+// VM, or an empty string if the file was removed.
+func (r *FileResource) remoteContentHash(ctx context.Context, data *FileResourceModel) (string, error) {
+	out, err := runRemoteScript(ctx, r.client, data.Hostname.ValueString(), remoteContentHashScript, data.Destination.ValueString())
+	if err != nil {
+		return "", err
+	}
+
+	// sha256sum prints "<hash>  -" when reading stdin
+	fields := strings.Fields(out)
+	if len(fields) == 0 {
+		return "", nil
+	}
+	return fields[0], nil
+}
+
+func (r *FileResource) copyFile(ctx context.Context, data *FileResourceModel) (string, error) {
+	// The source may have grown since the plan
+	if err := checkContentSize(data); err != nil {
+		return "", err
+	}
+
+	contentHash, size, err := contentHash(data)
+	if err != nil {
+		return "", err
+	}
+
+	// A source file is uploaded straight from its path; inline content is
+	// written to a temp file first
+	localPath := data.Source.ValueString()
+	if data.Source.IsNull() {
+		tmpFile, err := os.CreateTemp("", "slicer-file-*")
+		if err != nil {
+			return "", fmt.Errorf("failed to create temp file: %w", err)
+		}
+		defer os.Remove(tmpFile.Name())
+
+		content := data.Content.ValueString()
+		if data.Content.IsNull() {
+			content = data.PlainContent.ValueString()
+		}
+		_, err = tmpFile.WriteString(content)
 		tmpFile.Close()
-		return "", fmt.Errorf("failed to write temp file: %w", err)
+		if err != nil {
+			return "", fmt.Errorf("failed to write temp file: %w", err)
+		}
+		localPath = tmpFile.Name()
 	}
-	tmpFile.Close()
 
 	tflog.Debug(ctx, "Copying file to VM", map[string]interface{}{
 		"hostname":    data.Hostname.ValueString(),
 		"destination": data.Destination.ValueString(),
-		"size":        len(content),
+		"size":        size,
 	})
 
+	if data.CreateParents.ValueBool() {
+		if err := r.createParents(ctx, data); err != nil {
+			return "", err
+		}
+	}
+
+	var cpOpts []slicer.CpOption
+	if data.Compress.ValueBool() {
+		cpOpts = append(cpOpts, slicer.WithCompression())
+	}
+
+	if size >= progressLogThreshold {
+		cpOpts = append(cpOpts, slicer.WithProgress(progressLogInterval, uploadProgressLogger(ctx, data.Hostname.ValueString(), data.Destination.ValueString())))
+	}
+
+	// Upload next to the destination and rename it into place, so an
+	// interrupted apply never leaves a half-written file behind. The name is
+	// fixed, so a leftover upload is overwritten by the next apply.
+	destination := data.Destination.ValueString()
+	uploadPath := uploadPathFor(destination)
+
 	// Copy file to VM using binary mode
 	err = r.client.CpToVM(
 		ctx,
 		data.Hostname.ValueString(),
-		tmpFile.Name(),
-		data.Destination.ValueString(),
+		localPath,
+		uploadPath,
 		uint32(data.Owner.ValueInt64()),
 		uint32(data.Group.ValueInt64()),
 		data.Permissions.ValueString(),
 		"binary",
+		cpOpts...,
 	)
 	if err != nil {
+		r.removeUpload(ctx, data, uploadPath)
 		return "", fmt.Errorf("failed to copy file to VM: %w", err)
 	}
 
+	_, err = runRemoteScript(ctx, r.client, data.Hostname.ValueString(), installFileScript,
+		uploadPath,
+		destination,
+		strconv.FormatBool(data.Backup.ValueBool()),
+	)
+	if err != nil {
+		r.removeUpload(ctx, data, uploadPath)
+		return "", fmt.Errorf("failed to move %s into place: %w", uploadPath, err)
+	}
+
+	if data.ChattrImmutable.ValueBool() {
+		if err := r.setImmutableAttribute(ctx, data, true); err != nil {
+			return "", err
+		}
+	}
+
 	tflog.Trace(ctx, "Copied file to VM", map[string]interface{}{
 		"hostname":     data.Hostname.ValueString(),
 		"destination":  data.Destination.ValueString(),
@@ -284,3 +837,100 @@ func (r *FileResource) copyFile(ctx context.Context, data *FileResourceModel) (s
 
 	return contentHash, nil
 }
+
+// installFileScript renames the uploaded file $1 to $2, first copying an
+// existing $2 to $2.bak if $3 is "true". The upload is removed on failure.
+const installFileScript = `if [ "$3" = true ] && [ -e "$2" ]; then cp -p "$2" "$2.bak" || { rm -f "$1"; exit 1; }; fi
+mv -f "$1" "$2" || { rm -f "$1"; exit 1; }`
+
+// uploadPathFor returns the temporary path a file is uploaded to before it is
+// renamed to destination, e.g. /etc/.app.conf.slicer-tmp for /etc/app.conf.
+func uploadPathFor(destination string) string {
+	return posixpath.Join(posixpath.Dir(destination), "."+posixpath.Base(destination)+".slicer-tmp")
+}
+
+// removeUpload removes a partially uploaded file. Failures are only logged,
+// since the upload error is what gets reported.
+func (r *FileResource) removeUpload(ctx context.Context, data *FileResourceModel, uploadPath string) {
+	// Clean up even when the operation was cancelled
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+	defer cancel()
+
+	if _, err := runRemote(ctx, r.client, data.Hostname.ValueString(), "rm", "-f", uploadPath); err != nil {
+		tflog.Warn(ctx, "Unable to remove partial upload", map[string]interface{}{
+			"hostname": data.Hostname.ValueString(),
+			"path":     uploadPath,
+			"error":    err.Error(),
+		})
+	}
+}
+
+// createParentsScript creates directory $1 with mode $2 and owner $3:$4
+// unless it already exists, so existing directories keep their permissions.
+const createParentsScript = `test -d "$1" || install -d -m "$2" -o "$3" -g "$4" "$1"`
+
+// createParents makes sure the directory of the destination exists on the VM.
+func (r *FileResource) createParents(ctx context.Context, data *FileResourceModel) error {
+	dir := posixpath.Dir(data.Destination.ValueString())
+	if dir == "/" || dir == "." {
+		return nil
+	}
+
+	tflog.Debug(ctx, "Creating destination directory", map[string]interface{}{
+		"hostname":  data.Hostname.ValueString(),
+		"directory": dir,
+	})
+
+	_, err := runRemoteScript(ctx, r.client, data.Hostname.ValueString(), createParentsScript,
+		dir,
+		data.ParentPermissions.ValueString(),
+		strconv.FormatInt(data.ParentOwner.ValueInt64(), 10),
+		strconv.FormatInt(data.ParentGroup.ValueInt64(), 10),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+
+	return nil
+}
+
+// setImmutableAttribute sets or clears the immutable attribute of the destination file.
+func (r *FileResource) setImmutableAttribute(ctx context.Context, data *FileResourceModel, immutable bool) error {
+	flag := "-i"
+	if immutable {
+		flag = "+i"
+	}
+
+	tflog.Debug(ctx, "Changing immutable attribute of file", map[string]interface{}{
+		"hostname":    data.Hostname.ValueString(),
+		"destination": data.Destination.ValueString(),
+		"flag":        flag,
+	})
+
+	if _, err := runRemote(ctx, r.client, data.Hostname.ValueString(), "chattr", flag, data.Destination.ValueString()); err != nil {
+		return fmt.Errorf("failed to run chattr %s on %s: %w", flag, data.Destination.ValueString(), err)
+	}
+
+	return nil
+}
+
+// uploadProgressLogger returns a ProgressFunc that logs bytes sent and the
+// transfer rate, so that long uploads can be told apart from a hung apply.
+func uploadProgressLogger(ctx context.Context, hostname, destination string) slicer.ProgressFunc {
+	start := time.Now()
+	return func(sent, total int64) {
+		fields := map[string]interface{}{
+			"hostname":    hostname,
+			"destination": destination,
+			"bytes_sent":  sent,
+		}
+		if total > 0 {
+			fields["bytes_total"] = total
+			fields["percent"] = sent * 100 / total
+		}
+		if elapsed := time.Since(start).Seconds(); elapsed > 0 {
+			fields["bytes_per_second"] = int64(float64(sent) / elapsed)
+		}
+		tflog.Info(ctx, "Uploading file to VM", fields)
+	}
+}
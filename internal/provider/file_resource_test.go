@@ -0,0 +1,25 @@
+// Copyright (c) German Arutyunov
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import "testing"
+
+func TestUploadPathFor(t *testing.T) {
+	tests := []struct {
+		destination string
+		want        string
+	}{
+		{destination: "/etc/app.conf", want: "/etc/.app.conf.slicer-tmp"},
+		{destination: "/usr/local/bin/app", want: "/usr/local/bin/.app.slicer-tmp"},
+		{destination: "relative.txt", want: ".relative.txt.slicer-tmp"},
+	}
+
+	for _, tt := range tests {
+		// The path must not change between applies, so a leftover upload is
+		// overwritten and recorded uploads can be replayed
+		if got := uploadPathFor(tt.destination); got != tt.want {
+			t.Errorf("Want %s for %s, got %s", tt.want, tt.destination, got)
+		}
+	}
+}
@@ -0,0 +1,482 @@
+// Copyright (c) German Arutyunov
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/gaarutyunov/terraform-provider-slicer/internal/slicer"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &FirewallRuleResource{}
+var _ resource.ResourceWithImportState = &FirewallRuleResource{}
+var _ resource.ResourceWithValidateConfig = &FirewallRuleResource{}
+var _ resource.ResourceWithModifyPlan = &FirewallRuleResource{}
+
+func NewFirewallRuleResource() resource.Resource {
+	return &FirewallRuleResource{}
+}
+
+// FirewallRuleResource defines the resource implementation.
+type FirewallRuleResource struct {
+	client      *slicer.SlicerClient
+	serverInfo  *slicer.SlicerServerInfo
+	tokenScopes []string
+}
+
+// FirewallRuleResourceModel describes the resource data model.
+type FirewallRuleResourceModel struct {
+	ID          types.String `tfsdk:"id"`
+	Description types.String `tfsdk:"description"`
+	Direction   types.String `tfsdk:"direction"`
+	Action      types.String `tfsdk:"action"`
+	Protocol    types.String `tfsdk:"protocol"`
+	Port        types.String `tfsdk:"port"`
+	Source      types.String `tfsdk:"source"`
+	SourceTags  types.Map    `tfsdk:"source_tags"`
+	Destination types.String `tfsdk:"destination"`
+	TargetTags  types.Map    `tfsdk:"target_tags"`
+}
+
+// Protocols a firewall rule can match.
+const (
+	firewallProtocolTCP  = "tcp"
+	firewallProtocolUDP  = "udp"
+	firewallProtocolICMP = "icmp"
+	firewallProtocolAll  = "all"
+)
+
+func (r *FirewallRuleResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_firewall_rule"
+}
+
+func (r *FirewallRuleResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages a rule of Slicer's network policy. A rule is enforced on the VMs whose tags match `target_tags` and allows or denies their ingress traffic from `source` or `source_tags`, or their egress traffic to `destination`.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The unique identifier of the rule, assigned by the API.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"description": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "A free-form note about the rule.",
+			},
+			"direction": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Direction of the traffic the rule matches, as seen from the target VMs: `ingress` or `egress`. Defaults to `ingress`.",
+				Default:             stringdefault.StaticString(slicer.FirewallDirectionIngress),
+			},
+			"action": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "What happens to matching traffic: `allow` or `deny`. Defaults to `allow`.",
+				Default:             stringdefault.StaticString(slicer.FirewallActionAllow),
+			},
+			"protocol": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Protocol the rule matches: `tcp`, `udp`, `icmp` or `all`. Defaults to `tcp`.",
+				Default:             stringdefault.StaticString(firewallProtocolTCP),
+			},
+			"port": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Destination port, e.g. `22`, or inclusive port range, e.g. `8000-8080`. Only valid for `tcp` and `udp`. If not set, every port matches.",
+			},
+			"source": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "CIDR that ingress traffic comes from, e.g. `10.0.0.0/8`. If neither `source` nor `source_tags` is set, traffic from any address matches. Only valid for `ingress` rules.",
+			},
+			"source_tags": schema.MapAttribute{
+				Optional:            true,
+				MarkdownDescription: "Tags of the VMs that ingress traffic comes from, for VM-to-VM rules. Conflicts with `source`. Only valid for `ingress` rules.",
+				ElementType:         types.StringType,
+			},
+			"destination": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "CIDR that egress traffic goes to, e.g. `0.0.0.0/0`. If not set, traffic to any address matches. Only valid for `egress` rules.",
+			},
+			"target_tags": schema.MapAttribute{
+				Required:            true,
+				MarkdownDescription: "Tags of the VMs the rule is enforced on. A VM is targeted when it has all of these tags.",
+				ElementType:         types.StringType,
+			},
+		},
+	}
+}
+
+func (r *FirewallRuleResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*SlicerProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *SlicerProviderData, got: %T", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = providerData.Client
+	r.tokenScopes = providerData.TokenScopes
+	r.serverInfo = providerData.ServerInfo
+}
+
+func (r *FirewallRuleResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data FirewallRuleResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	direction := data.Direction.ValueString()
+	if data.Direction.IsNull() {
+		direction = slicer.FirewallDirectionIngress
+	}
+	if !data.Direction.IsUnknown() && direction != slicer.FirewallDirectionIngress && direction != slicer.FirewallDirectionEgress {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("direction"),
+			"Invalid Direction",
+			fmt.Sprintf("direction must be one of 'ingress' or 'egress', got: %s", direction),
+		)
+	}
+
+	if !data.Action.IsNull() && !data.Action.IsUnknown() {
+		action := data.Action.ValueString()
+		if action != slicer.FirewallActionAllow && action != slicer.FirewallActionDeny {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("action"),
+				"Invalid Action",
+				fmt.Sprintf("action must be one of 'allow' or 'deny', got: %s", action),
+			)
+		}
+	}
+
+	protocol := data.Protocol.ValueString()
+	if data.Protocol.IsNull() {
+		protocol = firewallProtocolTCP
+	}
+	switch {
+	case data.Protocol.IsUnknown():
+	case protocol != firewallProtocolTCP && protocol != firewallProtocolUDP && protocol != firewallProtocolICMP && protocol != firewallProtocolAll:
+		resp.Diagnostics.AddAttributeError(
+			path.Root("protocol"),
+			"Invalid Protocol",
+			fmt.Sprintf("protocol must be one of 'tcp', 'udp', 'icmp' or 'all', got: %s", protocol),
+		)
+	case !data.Port.IsNull() && protocol != firewallProtocolTCP && protocol != firewallProtocolUDP:
+		resp.Diagnostics.AddAttributeError(
+			path.Root("port"),
+			"Invalid Port",
+			fmt.Sprintf("port can only be set for tcp and udp rules, got protocol: %s", protocol),
+		)
+	}
+
+	if !data.Port.IsNull() && !data.Port.IsUnknown() && !validFirewallPort(data.Port.ValueString()) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("port"),
+			"Invalid Port",
+			fmt.Sprintf("port must be a port between 1 and 65535 such as '22', or a range such as '8000-8080', got: %s", data.Port.ValueString()),
+		)
+	}
+
+	validateCIDRAttribute(data.Source, path.Root("source"), &resp.Diagnostics)
+	validateCIDRAttribute(data.Destination, path.Root("destination"), &resp.Diagnostics)
+
+	if !data.Source.IsNull() && !data.SourceTags.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("source_tags"),
+			"Conflicting Attributes",
+			"source_tags cannot be set together with source.",
+		)
+	}
+
+	if !data.TargetTags.IsUnknown() && len(data.TargetTags.Elements()) == 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("target_tags"),
+			"Missing Target Tags",
+			"target_tags must contain at least one tag, otherwise the rule would not apply to any VM.",
+		)
+	}
+
+	if data.Direction.IsUnknown() {
+		return
+	}
+
+	switch direction {
+	case slicer.FirewallDirectionIngress:
+		if !data.Destination.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("destination"),
+				"Invalid Attribute For Direction",
+				"destination can only be set for egress rules. Ingress rules always match traffic to the target VMs.",
+			)
+		}
+	case slicer.FirewallDirectionEgress:
+		if !data.Source.IsNull() || !data.SourceTags.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("direction"),
+				"Invalid Attribute For Direction",
+				"source and source_tags can only be set for ingress rules. Egress rules always match traffic from the target VMs.",
+			)
+		}
+	}
+}
+
+func (r *FirewallRuleResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	checkScope(req, r.tokenScopes, scopeNetworkWrite, "slicer_firewall_rule", &resp.Diagnostics)
+
+	// Only creation depends on the server version
+	if req.Plan.Raw.IsNull() || !req.State.Raw.IsNull() {
+		return
+	}
+
+	checkCapability(r.serverInfo, capabilityFirewallRules, path.Root("target_tags"), &resp.Diagnostics)
+}
+
+func (r *FirewallRuleResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data FirewallRuleResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	rule, diags := firewallRuleFromModel(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Creating firewall rule", map[string]interface{}{
+		"direction":   rule.Direction,
+		"protocol":    rule.Protocol,
+		"port":        rule.Port,
+		"target_tags": rule.TargetTags,
+	})
+
+	created, err := r.client.CreateFirewallRule(ctx, rule)
+	if errors.Is(err, slicer.ErrNotSupported) {
+		resp.Diagnostics.AddError(
+			"Firewall Rules Not Supported",
+			"The Slicer API does not support network policies.",
+		)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create firewall rule: %s", err))
+		return
+	}
+
+	data.ID = types.StringValue(created.ID)
+
+	tflog.Trace(ctx, "Created firewall rule", map[string]interface{}{
+		"id": created.ID,
+	})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *FirewallRuleResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data FirewallRuleResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	rules, err := r.client.ListFirewallRules(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list firewall rules: %s", err))
+		return
+	}
+
+	for _, rule := range rules {
+		if rule.ID == data.ID.ValueString() {
+			resp.Diagnostics.Append(setFirewallRule(ctx, &data, &rule)...)
+			resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+			return
+		}
+	}
+
+	// Rule was deleted outside of Terraform
+	resp.State.RemoveResource(ctx)
+}
+
+func (r *FirewallRuleResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data FirewallRuleResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	rule, diags := firewallRuleFromModel(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Updating firewall rule", map[string]interface{}{
+		"id": data.ID.ValueString(),
+	})
+
+	_, err := r.client.UpdateFirewallRule(ctx, data.ID.ValueString(), rule)
+	if errors.Is(err, slicer.ErrNotSupported) {
+		resp.Diagnostics.AddError(
+			"Firewall Rules Not Supported",
+			"The Slicer API does not support network policies.",
+		)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update firewall rule: %s", err))
+		return
+	}
+
+	tflog.Trace(ctx, "Updated firewall rule", map[string]interface{}{
+		"id": data.ID.ValueString(),
+	})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *FirewallRuleResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data FirewallRuleResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Deleting firewall rule", map[string]interface{}{
+		"id": data.ID.ValueString(),
+	})
+
+	err := r.client.DeleteFirewallRule(ctx, data.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete firewall rule: %s", err))
+		return
+	}
+
+	tflog.Trace(ctx, "Deleted firewall rule", map[string]interface{}{
+		"id": data.ID.ValueString(),
+	})
+}
+
+func (r *FirewallRuleResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// firewallRuleFromModel builds the API payload for a rule from the model.
+func firewallRuleFromModel(ctx context.Context, data *FirewallRuleResourceModel) (slicer.SlicerFirewallRule, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	rule := slicer.SlicerFirewallRule{
+		Description: data.Description.ValueString(),
+		Direction:   data.Direction.ValueString(),
+		Action:      data.Action.ValueString(),
+		Protocol:    data.Protocol.ValueString(),
+		Port:        data.Port.ValueString(),
+		Source:      data.Source.ValueString(),
+		Destination: data.Destination.ValueString(),
+	}
+
+	targetTags := map[string]string{}
+	diags.Append(data.TargetTags.ElementsAs(ctx, &targetTags, false)...)
+	rule.TargetTags = tagsToAPI(targetTags)
+
+	if !data.SourceTags.IsNull() {
+		sourceTags := map[string]string{}
+		diags.Append(data.SourceTags.ElementsAs(ctx, &sourceTags, false)...)
+		rule.SourceTags = tagsToAPI(sourceTags)
+	}
+
+	return rule, diags
+}
+
+// setFirewallRule stores a firewall rule returned by the API in the model.
+func setFirewallRule(ctx context.Context, data *FirewallRuleResourceModel, rule *slicer.SlicerFirewallRule) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	data.ID = types.StringValue(rule.ID)
+	data.Description = optionalString(rule.Description)
+	data.Direction = types.StringValue(rule.Direction)
+	data.Action = types.StringValue(rule.Action)
+	data.Protocol = types.StringValue(rule.Protocol)
+	data.Port = optionalString(rule.Port)
+	data.Source = optionalString(rule.Source)
+	data.Destination = optionalString(rule.Destination)
+
+	targetTags, d := types.MapValueFrom(ctx, types.StringType, tagsFromAPI(rule.TargetTags))
+	diags.Append(d...)
+	data.TargetTags = targetTags
+
+	data.SourceTags = types.MapNull(types.StringType)
+	if len(rule.SourceTags) > 0 {
+		sourceTags, d := types.MapValueFrom(ctx, types.StringType, tagsFromAPI(rule.SourceTags))
+		diags.Append(d...)
+		data.SourceTags = sourceTags
+	}
+
+	return diags
+}
+
+// validFirewallPort reports whether port is a port such as "22" or an
+// inclusive range such as "8000-8080".
+func validFirewallPort(port string) bool {
+	from, to, isRange := strings.Cut(port, "-")
+	if !isRange {
+		to = from
+	}
+
+	first, err := strconv.Atoi(from)
+	if err != nil || first < 1 || first > 65535 {
+		return false
+	}
+	last, err := strconv.Atoi(to)
+	if err != nil || last < first || last > 65535 {
+		return false
+	}
+	return true
+}
+
+// validateCIDRAttribute adds an error at attrPath when value is set but not a
+// CIDR such as 10.0.0.0/8.
+func validateCIDRAttribute(value types.String, attrPath path.Path, diags *diag.Diagnostics) {
+	if value.IsNull() || value.IsUnknown() {
+		return
+	}
+
+	if _, _, err := net.ParseCIDR(value.ValueString()); err != nil {
+		diags.AddAttributeError(
+			attrPath,
+			"Invalid CIDR",
+			fmt.Sprintf("%s must be a CIDR such as '10.0.0.0/8', got: %s", attrPath, value.ValueString()),
+		)
+	}
+}
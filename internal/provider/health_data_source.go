@@ -0,0 +1,186 @@
+// Copyright (c) German Arutyunov
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gaarutyunov/terraform-provider-slicer/internal/slicer"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &HealthDataSource{}
+
+func NewHealthDataSource() datasource.DataSource {
+	return &HealthDataSource{}
+}
+
+// HealthDataSource defines the data source implementation.
+type HealthDataSource struct {
+	client *slicer.SlicerClient
+}
+
+// HealthDataSourceModel describes the data source data model.
+type HealthDataSourceModel struct {
+	FailOnUnhealthy types.Bool   `tfsdk:"fail_on_unhealthy"`
+	Healthy         types.Bool   `tfsdk:"healthy"`
+	Status          types.String `tfsdk:"status"`
+	Error           types.String `tfsdk:"error"`
+	Checks          types.Map    `tfsdk:"checks"`
+	Version         types.String `tfsdk:"version"`
+	GitCommit       types.String `tfsdk:"git_commit"`
+}
+
+// healthStatusUnreachable is reported when the API could not be reached at
+// all.
+const healthStatusUnreachable = "unreachable"
+
+func (d *HealthDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_health"
+}
+
+func (d *HealthDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Checks that the Slicer API is reachable and healthy and reports its version, e.g. to fail a plan early with a clear message when the endpoint is down, or to gate features on the backend version.",
+
+		Attributes: map[string]schema.Attribute{
+			"fail_on_unhealthy": schema.BoolAttribute{
+				Optional:            true,
+				MarkdownDescription: "Whether reading the data source fails when the API is unreachable or unhealthy. Set to `false` to only report the status, e.g. for a `check` block. Defaults to `true`.",
+			},
+			"healthy": schema.BoolAttribute{
+				Computed:            true,
+				MarkdownDescription: "Whether the API is reachable and reports itself healthy.",
+			},
+			"status": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Status of the API: `ok`, `unavailable` when the control plane reports itself unhealthy, or `unreachable` when it could not be contacted. Other values reported by the control plane are passed through.",
+			},
+			"error": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Why the API could not be reached. Null when it answered.",
+			},
+			"checks": schema.MapAttribute{
+				Computed:            true,
+				MarkdownDescription: "Status of the control plane's components, e.g. its database, keyed by component. Empty if the API does not report them.",
+				ElementType:         types.StringType,
+			},
+			"version": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Version of the Slicer control plane. Null if the API does not report it or is unreachable.",
+			},
+			"git_commit": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Commit the Slicer control plane was built from. Null if the API does not report it or is unreachable.",
+			},
+		},
+	}
+}
+
+func (d *HealthDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*SlicerProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *SlicerProviderData, got: %T", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = providerData.Client
+}
+
+func (d *HealthDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data HealthDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Checking Slicer API health")
+
+	data.Error = types.StringNull()
+	data.Version = types.StringNull()
+	data.GitCommit = types.StringNull()
+
+	health, err := d.client.GetHealth(ctx)
+	if errors.Is(err, slicer.ErrNotSupported) {
+		// Older control planes have no health endpoint, but answering at
+		// all shows they are up
+		health = &slicer.SlicerHealth{Status: slicer.HealthStatusOK}
+	} else if err != nil {
+		health = &slicer.SlicerHealth{Status: healthStatusUnreachable}
+		data.Error = types.StringValue(err.Error())
+	}
+
+	if data.Error.IsNull() {
+		info, err := d.client.GetServerInfo(ctx)
+		if err != nil {
+			tflog.Debug(ctx, "Unable to read Slicer server version", map[string]interface{}{
+				"error": err.Error(),
+			})
+		} else {
+			data.Version = optionalString(info.Version)
+			data.GitCommit = optionalString(info.GitCommit)
+		}
+	}
+
+	checks := health.Checks
+	if checks == nil {
+		checks = map[string]string{}
+	}
+	checksValue, diags := types.MapValueFrom(ctx, types.StringType, checks)
+	resp.Diagnostics.Append(diags...)
+	data.Checks = checksValue
+
+	data.Healthy = types.BoolValue(health.Healthy())
+	data.Status = types.StringValue(health.Status)
+
+	tflog.Trace(ctx, "Checked Slicer API health", map[string]interface{}{
+		"status":  health.Status,
+		"version": data.Version.ValueString(),
+	})
+
+	if !health.Healthy() && (data.FailOnUnhealthy.IsNull() || data.FailOnUnhealthy.ValueBool()) {
+		resp.Diagnostics.AddError("Slicer API Unavailable", unhealthyMessage(health, data.Error.ValueString()))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// unhealthyMessage explains why the API is unhealthy, listing the failing
+// checks it reported.
+func unhealthyMessage(health *slicer.SlicerHealth, reachErr string) string {
+	if health.Status == healthStatusUnreachable {
+		return fmt.Sprintf("The Slicer API could not be reached: %s", reachErr)
+	}
+
+	var failing []string
+	for component, status := range health.Checks {
+		if status != slicer.HealthStatusOK {
+			failing = append(failing, fmt.Sprintf("%s: %s", component, status))
+		}
+	}
+	sort.Strings(failing)
+
+	message := fmt.Sprintf("The Slicer API reports status %q.", health.Status)
+	if len(failing) > 0 {
+		message += " Failing checks: " + strings.Join(failing, "; ") + "."
+	}
+	return message
+}
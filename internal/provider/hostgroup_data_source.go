@@ -0,0 +1,164 @@
+// Copyright (c) German Arutyunov
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gaarutyunov/terraform-provider-slicer/internal/slicer"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &HostgroupDataSource{}
+
+func NewHostgroupDataSource() datasource.DataSource {
+	return &HostgroupDataSource{}
+}
+
+// HostgroupDataSource defines the data source implementation.
+type HostgroupDataSource struct {
+	client *slicer.SlicerClient
+}
+
+// HostgroupDataSourceModel describes the data source data model.
+type HostgroupDataSourceModel struct {
+	Name     types.String `tfsdk:"name"`
+	VMCount  types.Int64  `tfsdk:"vm_count"`
+	CPUs     types.Int64  `tfsdk:"cpus"`
+	RamGB    types.Int64  `tfsdk:"ram_gb"`
+	Arch     types.String `tfsdk:"arch"`
+	GPUCount types.Int64  `tfsdk:"gpu_count"`
+
+	UsedSlots types.Int64 `tfsdk:"used_slots"`
+	FreeSlots types.Int64 `tfsdk:"free_slots"`
+}
+
+func (d *HostgroupDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_hostgroup"
+}
+
+func (d *HostgroupDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Fetches a single Slicer host group and its available capacity.",
+
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The name of the host group.",
+			},
+			"vm_count": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "Number of VMs the host group can run.",
+			},
+			"cpus": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "Number of CPUs per VM.",
+			},
+			"ram_gb": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "RAM per VM in GB.",
+			},
+			"arch": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Architecture of the host group.",
+			},
+			"gpu_count": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "Number of GPUs per VM.",
+			},
+			"used_slots": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "Number of VMs currently running in the host group.",
+			},
+			"free_slots": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "Number of VMs that can still be created in the host group.",
+			},
+		},
+	}
+}
+
+func (d *HostgroupDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*SlicerProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *SlicerProviderData, got: %T", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = providerData.Client
+}
+
+func (d *HostgroupDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data HostgroupDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Reading host group", map[string]interface{}{
+		"name": data.Name.ValueString(),
+	})
+
+	hostgroups, err := d.client.GetHostGroups(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list host groups: %s", err))
+		return
+	}
+
+	var found *slicer.SlicerHostGroup
+	for _, hg := range hostgroups {
+		if hg.Name == data.Name.ValueString() {
+			found = &hg
+			break
+		}
+	}
+
+	if found == nil {
+		names := make([]string, 0, len(hostgroups))
+		for _, hg := range hostgroups {
+			names = append(names, hg.Name)
+		}
+		resp.Diagnostics.AddError(
+			"Not Found",
+			fmt.Sprintf("Host group '%s' not found. Available host groups: %v", data.Name.ValueString(), names),
+		)
+		return
+	}
+
+	nodes, err := d.client.GetHostGroupNodes(ctx, found.Name)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list VMs in host group: %s", err))
+		return
+	}
+
+	data.VMCount = types.Int64Value(int64(found.Count))
+	data.CPUs = types.Int64Value(int64(found.CPUs))
+	data.RamGB = types.Int64Value(slicer.BytesToGiB(found.RamBytes))
+	data.Arch = types.StringValue(found.Arch)
+	data.GPUCount = types.Int64Value(int64(found.GPUCount))
+	data.UsedSlots = types.Int64Value(int64(len(nodes)))
+
+	// A group shrunk below its running VMs has no free slots
+	data.FreeSlots = types.Int64Value(max(int64(found.Count-len(nodes)), 0))
+
+	tflog.Trace(ctx, "Read host group", map[string]interface{}{
+		"name":       found.Name,
+		"free_slots": data.FreeSlots.ValueInt64(),
+	})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
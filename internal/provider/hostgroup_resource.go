@@ -0,0 +1,285 @@
+// Copyright (c) German Arutyunov
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gaarutyunov/terraform-provider-slicer/internal/slicer"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &HostgroupResource{}
+var _ resource.ResourceWithImportState = &HostgroupResource{}
+var _ resource.ResourceWithModifyPlan = &HostgroupResource{}
+
+func NewHostgroupResource() resource.Resource {
+	return &HostgroupResource{}
+}
+
+// HostgroupResource defines the resource implementation.
+type HostgroupResource struct {
+	client      *slicer.SlicerClient
+	serverInfo  *slicer.SlicerServerInfo
+	tokenScopes []string
+}
+
+// HostgroupResourceModel describes the resource data model.
+type HostgroupResourceModel struct {
+	ID       types.String `tfsdk:"id"`
+	Name     types.String `tfsdk:"name"`
+	Count    types.Int64  `tfsdk:"vm_count"`
+	CPUs     types.Int64  `tfsdk:"cpus"`
+	RamGB    types.Int64  `tfsdk:"ram_gb"`
+	Arch     types.String `tfsdk:"arch"`
+	GPUCount types.Int64  `tfsdk:"gpu_count"`
+}
+
+func (r *HostgroupResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_hostgroup"
+}
+
+func (r *HostgroupResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages a Slicer host group. Requires a Slicer installation that allows host group management through the API.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The unique identifier of the host group (name).",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The name of the host group (e.g., 'w1-medium').",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"vm_count": schema.Int64Attribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Number of VMs in the host group. Defaults to 0.",
+				Default:             int64default.StaticInt64(0),
+			},
+			"cpus": schema.Int64Attribute{
+				Required:            true,
+				MarkdownDescription: "Number of CPUs per VM.",
+			},
+			"ram_gb": schema.Int64Attribute{
+				Required:            true,
+				MarkdownDescription: "RAM per VM in GB.",
+			},
+			"arch": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Architecture of the host group (e.g., 'amd64'). Defaults to the architecture of the Slicer host.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"gpu_count": schema.Int64Attribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Number of GPUs per VM. Defaults to 0.",
+				Default:             int64default.StaticInt64(0),
+			},
+		},
+	}
+}
+
+func (r *HostgroupResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*SlicerProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *SlicerProviderData, got: %T", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = providerData.Client
+	r.tokenScopes = providerData.TokenScopes
+	r.serverInfo = providerData.ServerInfo
+}
+
+func (r *HostgroupResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	checkScope(req, r.tokenScopes, scopeHostGroupsWrite, "slicer_hostgroup", &resp.Diagnostics)
+
+	// Only creation depends on the server version
+	if req.Plan.Raw.IsNull() || !req.State.Raw.IsNull() {
+		return
+	}
+
+	checkCapability(r.serverInfo, capabilityHostGroupManagement, path.Root("name"), &resp.Diagnostics)
+}
+
+func (r *HostgroupResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data HostgroupResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Creating host group", map[string]interface{}{
+		"name": data.Name.ValueString(),
+	})
+
+	created, err := r.client.CreateHostGroup(ctx, hostgroupFromModel(&data))
+	if errors.Is(err, slicer.ErrNotSupported) {
+		resp.Diagnostics.AddError(
+			"Host Group Management Not Supported",
+			"The Slicer API does not allow creating host groups. Host groups must be configured on the Slicer host.",
+		)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create host group: %s", err))
+		return
+	}
+
+	data.ID = data.Name
+	setHostgroupModel(&data, created)
+
+	tflog.Trace(ctx, "Created host group", map[string]interface{}{
+		"name": data.Name.ValueString(),
+	})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *HostgroupResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data HostgroupResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	hostGroups, err := r.client.GetHostGroups(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list host groups: %s", err))
+		return
+	}
+
+	var found *slicer.SlicerHostGroup
+	for _, hg := range hostGroups {
+		if hg.Name == data.Name.ValueString() {
+			found = &hg
+			break
+		}
+	}
+
+	if found == nil {
+		// Host group was deleted outside of Terraform
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	data.ID = data.Name
+	setHostgroupModel(&data, found)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *HostgroupResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data HostgroupResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Updating host group", map[string]interface{}{
+		"name": data.Name.ValueString(),
+	})
+
+	updated, err := r.client.UpdateHostGroup(ctx, data.Name.ValueString(), hostgroupFromModel(&data))
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update host group: %s", err))
+		return
+	}
+
+	setHostgroupModel(&data, updated)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *HostgroupResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data HostgroupResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Deleting host group", map[string]interface{}{
+		"name": data.Name.ValueString(),
+	})
+
+	err := r.client.DeleteHostGroup(ctx, data.Name.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete host group: %s", err))
+		return
+	}
+
+	tflog.Trace(ctx, "Deleted host group", map[string]interface{}{
+		"name": data.Name.ValueString(),
+	})
+}
+
+func (r *HostgroupResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+}
+
+// hostgroupFromModel builds the API payload from the resource model.
+func hostgroupFromModel(data *HostgroupResourceModel) slicer.SlicerHostGroup {
+	hg := slicer.SlicerHostGroup{
+		Name:     data.Name.ValueString(),
+		Count:    int(data.Count.ValueInt64()),
+		CPUs:     int(data.CPUs.ValueInt64()),
+		RamBytes: slicer.GiB(data.RamGB.ValueInt64()),
+		GPUCount: int(data.GPUCount.ValueInt64()),
+	}
+	if !data.Arch.IsNull() && !data.Arch.IsUnknown() {
+		hg.Arch = data.Arch.ValueString()
+	}
+	return hg
+}
+
+// setHostgroupModel copies values reported by the API into the resource model.
+func setHostgroupModel(data *HostgroupResourceModel, hg *slicer.SlicerHostGroup) {
+	data.Count = types.Int64Value(int64(hg.Count))
+	data.GPUCount = types.Int64Value(int64(hg.GPUCount))
+	if hg.CPUs > 0 {
+		data.CPUs = types.Int64Value(int64(hg.CPUs))
+	}
+	if hg.RamBytes > 0 {
+		data.RamGB = types.Int64Value(hg.RamBytes / (1024 * 1024 * 1024))
+	}
+	if hg.Arch != "" {
+		data.Arch = types.StringValue(hg.Arch)
+	} else if data.Arch.IsUnknown() {
+		data.Arch = types.StringNull()
+	}
+}
@@ -41,6 +41,10 @@ type HostgroupModel struct {
 	RamGB    types.Int64  `tfsdk:"ram_gb"`
 	Arch     types.String `tfsdk:"arch"`
 	GPUCount types.Int64  `tfsdk:"gpu_count"`
+
+	DiskGB               types.Int64  `tfsdk:"disk_gb"`
+	DiskType             types.String `tfsdk:"disk_type"`
+	NetworkBandwidthMbps types.Int64  `tfsdk:"network_bandwidth_mbps"`
 }
 
 func (d *HostgroupsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
@@ -86,6 +90,18 @@ func (d *HostgroupsDataSource) Schema(ctx context.Context, req datasource.Schema
 							Computed:            true,
 							MarkdownDescription: "Number of GPUs per VM.",
 						},
+						"disk_gb": schema.Int64Attribute{
+							Computed:            true,
+							MarkdownDescription: "Disk size per VM in GB. Null if not reported by the API.",
+						},
+						"disk_type": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "Type of the disk backing the VMs, `ssd` or `hdd`. Null if not reported by the API.",
+						},
+						"network_bandwidth_mbps": schema.Int64Attribute{
+							Computed:            true,
+							MarkdownDescription: "Network bandwidth per VM in Mbps. Null if not reported by the API.",
+						},
 					},
 				},
 			},
@@ -143,10 +159,26 @@ func (d *HostgroupsDataSource) Read(ctx context.Context, req datasource.ReadRequ
 			Name:     types.StringValue(hg.Name),
 			Count:    types.Int64Value(int64(hg.Count)),
 			CPUs:     types.Int64Value(int64(hg.CPUs)),
-			RamGB:    types.Int64Value(hg.RamBytes / (1024 * 1024 * 1024)),
+			RamGB:    types.Int64Value(slicer.BytesToGiB(hg.RamBytes)),
 			Arch:     types.StringValue(hg.Arch),
 			GPUCount: types.Int64Value(int64(hg.GPUCount)),
+
+			DiskGB:               types.Int64Null(),
+			DiskType:             types.StringNull(),
+			NetworkBandwidthMbps: types.Int64Null(),
+		}
+
+		// Older Slicer versions do not report storage or network specs
+		if hg.DiskBytes > 0 {
+			hgModel.DiskGB = types.Int64Value(slicer.BytesToGiB(hg.DiskBytes))
+		}
+		if hg.DiskType != "" {
+			hgModel.DiskType = types.StringValue(hg.DiskType)
 		}
+		if hg.NetworkBandwidthMbps > 0 {
+			hgModel.NetworkBandwidthMbps = types.Int64Value(hg.NetworkBandwidthMbps)
+		}
+
 		hgModels = append(hgModels, hgModel)
 	}
 
@@ -158,6 +190,10 @@ func (d *HostgroupsDataSource) Read(ctx context.Context, req datasource.ReadRequ
 			"ram_gb":    types.Int64Type,
 			"arch":      types.StringType,
 			"gpu_count": types.Int64Type,
+
+			"disk_gb":                types.Int64Type,
+			"disk_type":              types.StringType,
+			"network_bandwidth_mbps": types.Int64Type,
 		},
 	}, hgModels)
 	resp.Diagnostics.Append(diags...)
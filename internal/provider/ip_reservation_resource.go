@@ -0,0 +1,277 @@
+// Copyright (c) German Arutyunov
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/gaarutyunov/terraform-provider-slicer/internal/slicer"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &IPReservationResource{}
+var _ resource.ResourceWithImportState = &IPReservationResource{}
+var _ resource.ResourceWithValidateConfig = &IPReservationResource{}
+var _ resource.ResourceWithModifyPlan = &IPReservationResource{}
+
+func NewIPReservationResource() resource.Resource {
+	return &IPReservationResource{}
+}
+
+// IPReservationResource defines the resource implementation.
+type IPReservationResource struct {
+	client      *slicer.SlicerClient
+	serverInfo  *slicer.SlicerServerInfo
+	tokenScopes []string
+}
+
+// IPReservationResourceModel describes the resource data model.
+type IPReservationResourceModel struct {
+	ID          types.String `tfsdk:"id"`
+	HostGroup   types.String `tfsdk:"host_group"`
+	IP          types.String `tfsdk:"ip"`
+	Description types.String `tfsdk:"description"`
+	Hostname    types.String `tfsdk:"hostname"`
+}
+
+func (r *IPReservationResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_ip_reservation"
+}
+
+func (r *IPReservationResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Reserves an IP address from a Slicer host group's pool independently of any VM, so the address survives VM replacement.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The unique identifier of the reservation (`host_group/ip`).",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"host_group": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The host group whose pool the address is reserved from.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"ip": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "The IPv4 address to reserve. If not set, the next free address in the pool is reserved.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"description": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Free-form description of what the address is for.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"hostname": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The VM currently using the address, or empty if it is unused.",
+			},
+		},
+	}
+}
+
+func (r *IPReservationResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*SlicerProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *SlicerProviderData, got: %T", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = providerData.Client
+	r.tokenScopes = providerData.TokenScopes
+	r.serverInfo = providerData.ServerInfo
+}
+
+func (r *IPReservationResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var ip types.String
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("ip"), &ip)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if ip.IsNull() || ip.IsUnknown() {
+		return
+	}
+
+	if parsed := net.ParseIP(ip.ValueString()); parsed == nil || parsed.To4() == nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("ip"),
+			"Invalid IP Address",
+			fmt.Sprintf("ip must be an IPv4 address without a prefix length, got: %s", ip.ValueString()),
+		)
+	}
+}
+
+func (r *IPReservationResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	checkScope(req, r.tokenScopes, scopeVMsWrite, "slicer_ip_reservation", &resp.Diagnostics)
+
+	// Only creation depends on the server version
+	if req.Plan.Raw.IsNull() || !req.State.Raw.IsNull() {
+		return
+	}
+
+	checkCapability(r.serverInfo, capabilityIPReservations, path.Root("host_group"), &resp.Diagnostics)
+}
+
+func (r *IPReservationResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data IPReservationResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	reservation := slicer.SlicerIPReservation{
+		Description: data.Description.ValueString(),
+	}
+	if !data.IP.IsUnknown() {
+		reservation.IP = data.IP.ValueString()
+	}
+
+	tflog.Debug(ctx, "Reserving IP address", map[string]interface{}{
+		"host_group": data.HostGroup.ValueString(),
+		"ip":         reservation.IP,
+	})
+
+	created, err := r.client.CreateIPReservation(ctx, data.HostGroup.ValueString(), reservation)
+	if errors.Is(err, slicer.ErrNotSupported) {
+		resp.Diagnostics.AddError(
+			"IP Reservations Not Supported",
+			"The Slicer API does not support reserving IP addresses.",
+		)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to reserve IP address: %s", err))
+		return
+	}
+
+	setIPReservation(&data, created)
+
+	tflog.Trace(ctx, "Reserved IP address", map[string]interface{}{
+		"host_group": data.HostGroup.ValueString(),
+		"ip":         data.IP.ValueString(),
+	})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *IPReservationResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data IPReservationResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	reservations, err := r.client.ListIPReservations(ctx, data.HostGroup.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list IP reservations: %s", err))
+		return
+	}
+
+	for _, reservation := range reservations {
+		if reservation.IP == data.IP.ValueString() {
+			setIPReservation(&data, &reservation)
+			resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+			return
+		}
+	}
+
+	// Reservation was released outside of Terraform
+	resp.State.RemoveResource(ctx)
+}
+
+func (r *IPReservationResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data IPReservationResourceModel
+
+	// All configurable attributes require replacement, so there is nothing to
+	// send to the API.
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *IPReservationResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data IPReservationResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Releasing IP address", map[string]interface{}{
+		"host_group": data.HostGroup.ValueString(),
+		"ip":         data.IP.ValueString(),
+	})
+
+	err := r.client.DeleteIPReservation(ctx, data.HostGroup.ValueString(), data.IP.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to release IP address: %s", err))
+		return
+	}
+
+	tflog.Trace(ctx, "Released IP address", map[string]interface{}{
+		"host_group": data.HostGroup.ValueString(),
+		"ip":         data.IP.ValueString(),
+	})
+}
+
+func (r *IPReservationResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	hostGroup, ip, ok := strings.Cut(req.ID, "/")
+	if !ok || hostGroup == "" || ip == "" {
+		resp.Diagnostics.AddError(
+			"Invalid Import ID",
+			fmt.Sprintf("Expected an import ID of the form host_group/ip, got: %s", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("host_group"), hostGroup)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("ip"), ip)...)
+}
+
+// setIPReservation stores an IP reservation returned by the API in the model.
+func setIPReservation(data *IPReservationResourceModel, reservation *slicer.SlicerIPReservation) {
+	data.ID = types.StringValue(data.HostGroup.ValueString() + "/" + reservation.IP)
+	data.IP = types.StringValue(reservation.IP)
+	data.Hostname = types.StringValue(reservation.Hostname)
+	if reservation.Description != "" {
+		data.Description = types.StringValue(reservation.Description)
+	}
+}
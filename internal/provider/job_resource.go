@@ -0,0 +1,251 @@
+// Copyright (c) German Arutyunov
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gaarutyunov/terraform-provider-slicer/internal/slicer"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &JobResource{}
+var _ resource.ResourceWithModifyPlan = &JobResource{}
+
+func NewJobResource() resource.Resource {
+	return &JobResource{}
+}
+
+// JobResource defines the resource implementation.
+type JobResource struct {
+	client      *slicer.SlicerClient
+	serverInfo  *slicer.SlicerServerInfo
+	tokenScopes []string
+}
+
+// JobResourceModel describes the resource data model.
+type JobResourceModel struct {
+	ID       types.String `tfsdk:"id"`
+	Type     types.String `tfsdk:"type"`
+	Params   types.Map    `tfsdk:"params"`
+	Status   types.String `tfsdk:"status"`
+	Progress types.Int64  `tfsdk:"progress"`
+	Result   types.Map    `tfsdk:"result"`
+}
+
+func (r *JobResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_job"
+}
+
+func (r *JobResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Runs a long-running Slicer backend operation, such as an image import or a large clone, as an asynchronous job. " +
+			"The provider submits the job and polls it until it finishes; a failed job fails the apply with the job's error. " +
+			"Destroying the resource only removes it from state.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The job ID assigned by Slicer.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"type": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The kind of operation, e.g. `image_import`. Changing it runs a new job.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"params": schema.MapAttribute{
+				Optional:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "Operation specific parameters. Changing them runs a new job.",
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			"status": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The final status of the job.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"progress": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "The completion percentage of the job.",
+			},
+			"result": schema.MapAttribute{
+				Computed:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "Outputs of the job, e.g. the name of an imported image.",
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *JobResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*SlicerProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *SlicerProviderData, got: %T", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = providerData.Client
+	r.tokenScopes = providerData.TokenScopes
+	r.serverInfo = providerData.ServerInfo
+}
+
+func (r *JobResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	checkScope(req, r.tokenScopes, scopeJobsWrite, "slicer_job", &resp.Diagnostics)
+
+	// Only creation depends on the server version
+	if req.Plan.Raw.IsNull() || !req.State.Raw.IsNull() {
+		return
+	}
+
+	checkCapability(r.serverInfo, capabilityJobs, path.Root("type"), &resp.Diagnostics)
+}
+
+func (r *JobResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data JobResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	jobReq := slicer.SlicerJobRequest{
+		Type: data.Type.ValueString(),
+	}
+	if !data.Params.IsNull() {
+		resp.Diagnostics.Append(data.Params.ElementsAs(ctx, &jobReq.Params, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	tflog.Debug(ctx, "Submitting job", map[string]interface{}{
+		"type": jobReq.Type,
+	})
+
+	job, err := r.client.SubmitJob(ctx, jobReq)
+	if errors.Is(err, slicer.ErrNotSupported) {
+		resp.Diagnostics.AddError(
+			"Jobs Not Supported",
+			"The Slicer API does not support asynchronous jobs.",
+		)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to submit job: %s", err))
+		return
+	}
+
+	lastProgress := -1
+	job, err = r.client.WaitForJob(ctx, job.ID, func(j *slicer.SlicerJob) {
+		if j.Progress == lastProgress {
+			return
+		}
+		lastProgress = j.Progress
+		tflog.Info(ctx, "Waiting for job", map[string]interface{}{
+			"id":       j.ID,
+			"status":   j.Status,
+			"progress": j.Progress,
+			"message":  j.Message,
+		})
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Job Error", fmt.Sprintf("Unable to complete job: %s", err))
+		return
+	}
+
+	resp.Diagnostics.Append(setJob(ctx, &data, job)...)
+
+	tflog.Trace(ctx, "Completed job", map[string]interface{}{
+		"id":   job.ID,
+		"type": job.Type,
+	})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *JobResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data JobResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	job, err := r.client.GetJob(ctx, data.ID.ValueString())
+	if errors.Is(err, slicer.ErrJobNotFound) {
+		// Finished jobs expire from the API; keep the recorded result rather
+		// than running the job again
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read job: %s", err))
+		return
+	}
+
+	resp.Diagnostics.Append(setJob(ctx, &data, job)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *JobResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data JobResourceModel
+
+	// All configurable attributes require replacement, so there is nothing to
+	// send to the API.
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *JobResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Nothing to delete - a finished job cannot be undone
+}
+
+// setJob stores the status of a job returned by the API in the model.
+func setJob(ctx context.Context, data *JobResourceModel, job *slicer.SlicerJob) diag.Diagnostics {
+	data.ID = types.StringValue(job.ID)
+	data.Status = types.StringValue(job.Status)
+	data.Progress = types.Int64Value(int64(job.Progress))
+
+	resultMap := job.Result
+	if resultMap == nil {
+		resultMap = map[string]string{}
+	}
+	result, diags := types.MapValueFrom(ctx, types.StringType, resultMap)
+	data.Result = result
+	return diags
+}
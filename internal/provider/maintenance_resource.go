@@ -0,0 +1,264 @@
+// Copyright (c) German Arutyunov
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gaarutyunov/terraform-provider-slicer/internal/slicer"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &MaintenanceResource{}
+var _ resource.ResourceWithImportState = &MaintenanceResource{}
+var _ resource.ResourceWithModifyPlan = &MaintenanceResource{}
+
+func NewMaintenanceResource() resource.Resource {
+	return &MaintenanceResource{}
+}
+
+// MaintenanceResource defines the resource implementation.
+type MaintenanceResource struct {
+	client      *slicer.SlicerClient
+	serverInfo  *slicer.SlicerServerInfo
+	tokenScopes []string
+	vms         *vmListCache
+}
+
+// MaintenanceResourceModel describes the resource data model.
+type MaintenanceResourceModel struct {
+	ID          types.String `tfsdk:"id"`
+	HostGroup   types.String `tfsdk:"host_group"`
+	Reason      types.String `tfsdk:"reason"`
+	Evacuate    types.Bool   `tfsdk:"evacuate"`
+	MigratedVMs types.List   `tfsdk:"migrated_vms"`
+}
+
+func (r *MaintenanceResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_maintenance"
+}
+
+func (r *MaintenanceResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Puts a Slicer host group into maintenance mode. While the resource exists the host group is cordoned and no new VMs are placed in it; destroying the resource uncordons the host group.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The unique identifier of the maintenance window (host group name).",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"host_group": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The host group to cordon.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"reason": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Reason for the maintenance, recorded by the Slicer API.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"evacuate": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Migrate existing VMs out of the host group after cordoning it. Defaults to false.",
+				Default:             booldefault.StaticBool(false),
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"migrated_vms": schema.ListAttribute{
+				Computed:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "Hostnames of the VMs migrated out of the host group by `evacuate`.",
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *MaintenanceResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*SlicerProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *SlicerProviderData, got: %T", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = providerData.Client
+	r.tokenScopes = providerData.TokenScopes
+	r.serverInfo = providerData.ServerInfo
+	r.vms = providerData.VMs
+}
+
+func (r *MaintenanceResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	checkScope(req, r.tokenScopes, scopeHostGroupsWrite, "slicer_maintenance", &resp.Diagnostics)
+
+	// Only creation depends on the server version
+	if req.Plan.Raw.IsNull() || !req.State.Raw.IsNull() {
+		return
+	}
+
+	checkCapability(r.serverInfo, capabilityMaintenance, path.Root("host_group"), &resp.Diagnostics)
+}
+
+func (r *MaintenanceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data MaintenanceResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	hostGroup := data.HostGroup.ValueString()
+
+	tflog.Debug(ctx, "Cordoning host group", map[string]interface{}{
+		"host_group": hostGroup,
+	})
+
+	err := r.client.CordonHostGroup(ctx, hostGroup, data.Reason.ValueString())
+	if errors.Is(err, slicer.ErrNotSupported) {
+		resp.Diagnostics.AddError(
+			"Maintenance Mode Not Supported",
+			"The Slicer API does not support cordoning host groups.",
+		)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to cordon host group: %s", err))
+		return
+	}
+
+	data.ID = data.HostGroup
+	migrated := []string{}
+
+	if data.Evacuate.ValueBool() {
+		tflog.Debug(ctx, "Evacuating host group", map[string]interface{}{
+			"host_group": hostGroup,
+		})
+
+		// Evacuated VMs move to another host group
+		defer r.vms.invalidate()
+
+		result, err := r.client.EvacuateHostGroup(ctx, hostGroup)
+		if result != nil {
+			migrated = result.Migrated
+		}
+		if err != nil {
+			// The host group stays cordoned, so keep it in state to uncordon on destroy
+			data.MigratedVMs, _ = types.ListValueFrom(ctx, types.StringType, migrated)
+			resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to evacuate host group: %s", err))
+			return
+		}
+	}
+
+	migratedList, diags := types.ListValueFrom(ctx, types.StringType, migrated)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.MigratedVMs = migratedList
+
+	tflog.Trace(ctx, "Cordoned host group", map[string]interface{}{
+		"host_group":   hostGroup,
+		"migrated_vms": len(migrated),
+	})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *MaintenanceResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data MaintenanceResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	hostGroups, err := r.client.GetHostGroups(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list host groups: %s", err))
+		return
+	}
+
+	for _, hg := range hostGroups {
+		if hg.Name == data.HostGroup.ValueString() {
+			resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+			return
+		}
+	}
+
+	// Host group was deleted outside of Terraform
+	resp.State.RemoveResource(ctx)
+}
+
+func (r *MaintenanceResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data MaintenanceResourceModel
+
+	// All configurable attributes require replacement, so there is nothing to
+	// send to the API.
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *MaintenanceResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data MaintenanceResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Uncordoning host group", map[string]interface{}{
+		"host_group": data.HostGroup.ValueString(),
+	})
+
+	err := r.client.UncordonHostGroup(ctx, data.HostGroup.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to uncordon host group: %s", err))
+		return
+	}
+
+	tflog.Trace(ctx, "Uncordoned host group", map[string]interface{}{
+		"host_group": data.HostGroup.ValueString(),
+	})
+}
+
+func (r *MaintenanceResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("host_group"), req, resp)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("evacuate"), false)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("migrated_vms"), []string{})...)
+}
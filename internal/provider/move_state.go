@@ -0,0 +1,70 @@
+// Copyright (c) German Arutyunov
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+)
+
+// movedPrivateKey marks resources adopted from a provisioner with a moved
+// block in private state until their first apply.
+const movedPrivateKey = "moved_from_provisioner"
+
+// movedProvisioner is what is known about a null_resource or terraform_data
+// adopted by a slicer resource. Everything else comes from the new config.
+type movedProvisioner struct {
+	ID       string            `json:"id"`
+	Triggers map[string]string `json:"triggers"`
+}
+
+// parseMovedProvisioner decodes the source of a moved block. It returns nil
+// when the source is not a null_resource or terraform_data, so other state
+// movers can handle it.
+func parseMovedProvisioner(req resource.MoveStateRequest, diags *diag.Diagnostics) *movedProvisioner {
+	isNullResource := req.SourceTypeName == "null_resource" && strings.HasSuffix(req.SourceProviderAddress, "hashicorp/null")
+	isTerraformData := req.SourceTypeName == "terraform_data" && strings.HasSuffix(req.SourceProviderAddress, "builtin/terraform")
+	if !isNullResource && !isTerraformData || req.SourceRawState == nil {
+		return nil
+	}
+
+	var moved movedProvisioner
+	if err := json.Unmarshal(req.SourceRawState.JSON, &moved); err != nil {
+		diags.AddError(
+			"Unable to Move Resource State",
+			"Could not decode the state of "+req.SourceTypeName+": "+err.Error(),
+		)
+		return nil
+	}
+
+	// terraform_data has no string triggers to carry over
+	if isTerraformData {
+		moved.Triggers = nil
+	}
+
+	return &moved
+}
+
+// markMoved records in private state that the resource was adopted, so its
+// first apply does not redo the provisioner's work.
+func markMoved(ctx context.Context, resp *resource.MoveStateResponse) {
+	resp.Diagnostics.Append(resp.TargetPrivate.SetKey(ctx, movedPrivateKey, []byte("true"))...)
+}
+
+// privateGetter is implemented by the private state of plan and apply requests.
+type privateGetter interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+}
+
+// isMoved reports whether the resource was adopted with a moved block and has
+// not been applied since.
+func isMoved(ctx context.Context, private privateGetter, diags *diag.Diagnostics) bool {
+	value, getDiags := private.GetKey(ctx, movedPrivateKey)
+	diags.Append(getDiags...)
+	return string(value) == "true"
+}
@@ -0,0 +1,130 @@
+// Copyright (c) German Arutyunov
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// maxMode is the largest permission mode, including setuid, setgid and sticky bits.
+const maxMode = 0o7777
+
+// permissionsPattern matches octal permission strings with a leading zero,
+// e.g. "0644" or "01777". The leading zero is required so that "644" is not
+// mistaken for a decimal number; further leading zeros are rejected so every
+// mode has exactly one spelling.
+var permissionsPattern = regexp.MustCompile(`^0([0-7]{3}|[1-7][0-7]{3})$`)
+
+// validatePermissions adds an error at attrPath when value is set but is not
+// an octal permission string.
+func validatePermissions(value types.String, attrPath path.Path, diags *diag.Diagnostics) {
+	if value.IsNull() || value.IsUnknown() || permissionsPattern.MatchString(value.ValueString()) {
+		return
+	}
+
+	diags.AddAttributeError(
+		attrPath,
+		"Invalid Permissions",
+		fmt.Sprintf("Expected an octal permission string with a leading zero such as '0644', got: %s", value.ValueString()),
+	)
+}
+
+// validateMode adds an error at attrPath when value is set but is not a valid permission mode.
+func validateMode(value types.Int64, attrPath path.Path, diags *diag.Diagnostics) {
+	if value.IsNull() || value.IsUnknown() {
+		return
+	}
+
+	if mode := value.ValueInt64(); mode < 0 || mode > maxMode {
+		diags.AddAttributeError(
+			attrPath,
+			"Invalid Mode",
+			fmt.Sprintf("Expected a permission mode between 0 and %d (octal 07777), got: %d. Use parseint(\"0644\", 8) to convert an octal string.", maxMode, mode),
+		)
+	}
+}
+
+// modeToPermissions formats a numeric mode as an octal permission string with
+// a leading zero, e.g. 420 as "0644".
+func modeToPermissions(mode int64) string {
+	return fmt.Sprintf("0%03o", mode)
+}
+
+// normalizePermissions rewrites octal permissions reported by the API, which may
+// lack the leading zero, to the form used in configuration.
+// Values that are not octal are returned unchanged.
+func normalizePermissions(permissions string) string {
+	mode, err := strconv.ParseInt(permissions, 8, 64)
+	if err != nil || mode < 0 || mode > maxMode {
+		return permissions
+	}
+	return modeToPermissions(mode)
+}
+
+// effectiveMode returns the numeric mode configured through either a mode or a
+// permissions attribute. ok is false when neither is known yet or the
+// permissions string is invalid.
+func effectiveMode(permissions types.String, mode types.Int64) (int64, bool) {
+	if !mode.IsNull() {
+		return mode.ValueInt64(), !mode.IsUnknown()
+	}
+
+	if permissions.IsNull() || permissions.IsUnknown() || !permissionsPattern.MatchString(permissions.ValueString()) {
+		return 0, false
+	}
+
+	parsed, err := strconv.ParseInt(permissions.ValueString(), 8, 64)
+	return parsed, err == nil
+}
+
+// worldAccessible reports whether mode grants read or write access to all users.
+func worldAccessible(mode int64) bool {
+	return mode&0o006 != 0
+}
+
+// PermissionPolicy caps the permissions resources may set on files,
+// directories and secrets. A nil limit allows any mode.
+type PermissionPolicy struct {
+	MaxFile      *int64
+	MaxDirectory *int64
+	MaxSecret    *int64
+}
+
+// checkPermissionLimit adds an error at attrPath when permissions grant any
+// bit not present in limit. Unknown, null or invalid permissions are skipped;
+// they are reported by attribute validation instead.
+func checkPermissionLimit(limit *int64, permissions types.String, kind string, attrPath path.Path, diags *diag.Diagnostics) {
+	if limit == nil {
+		return
+	}
+
+	mode, ok := effectiveMode(permissions, types.Int64Null())
+	if !ok || mode&^*limit == 0 {
+		return
+	}
+
+	diags.AddAttributeError(
+		attrPath,
+		"Permission Policy Violation",
+		fmt.Sprintf("%s permissions %s exceed the provider permission_policy maximum of %s.", kind, modeToPermissions(mode), modeToPermissions(*limit)),
+	)
+}
+
+// parsePermissionLimit parses a permission_policy limit, returning nil when
+// it is not set. Invalid values are reported at attrPath.
+func parsePermissionLimit(value types.String, attrPath path.Path, diags *diag.Diagnostics) *int64 {
+	validatePermissions(value, attrPath, diags)
+
+	limit, ok := effectiveMode(value, types.Int64Null())
+	if !ok {
+		return nil
+	}
+	return &limit
+}
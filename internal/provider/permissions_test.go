@@ -0,0 +1,157 @@
+// Copyright (c) German Arutyunov
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestCheckPermissionLimit(t *testing.T) {
+	limit := func(mode int64) *int64 { return &mode }
+
+	tests := []struct {
+		name        string
+		limit       *int64
+		permissions types.String
+		wantErr     bool
+	}{
+		{name: "no limit", permissions: types.StringValue("0777")},
+		{name: "equal to the limit", limit: limit(0o755), permissions: types.StringValue("0755")},
+		{name: "subset of the limit", limit: limit(0o755), permissions: types.StringValue("0750")},
+		{name: "subset with fewer owner bits", limit: limit(0o755), permissions: types.StringValue("0500")},
+		{name: "group write", limit: limit(0o755), permissions: types.StringValue("0775"), wantErr: true},
+		{name: "group read beyond owner-only limit", limit: limit(0o600), permissions: types.StringValue("0644"), wantErr: true},
+		{name: "numerically lower but not a subset", limit: limit(0o600), permissions: types.StringValue("0070"), wantErr: true},
+		{name: "setuid", limit: limit(0o755), permissions: types.StringValue("04755"), wantErr: true},
+		{name: "setgid", limit: limit(0o755), permissions: types.StringValue("02755"), wantErr: true},
+		{name: "sticky bit", limit: limit(0o777), permissions: types.StringValue("01777"), wantErr: true},
+		{name: "setuid allowed by the limit", limit: limit(0o4755), permissions: types.StringValue("04755")},
+		{name: "null permissions", limit: limit(0o600), permissions: types.StringNull()},
+		{name: "unknown permissions", limit: limit(0o600), permissions: types.StringUnknown()},
+		{name: "invalid permissions are left to validation", limit: limit(0o600), permissions: types.StringValue("0999")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			checkPermissionLimit(tt.limit, tt.permissions, "File", path.Root("permissions"), &diags)
+
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("Want error %t, got %v", tt.wantErr, diags)
+			}
+			if tt.wantErr && diags.Errors()[0].Summary() != "Permission Policy Violation" {
+				t.Errorf("Want a Permission Policy Violation, got %v", diags)
+			}
+		})
+	}
+}
+
+func TestParsePermissionLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   types.String
+		want    int64
+		wantNil bool
+		wantErr bool
+	}{
+		{name: "octal", value: types.StringValue("0755"), want: 0o755},
+		{name: "special bits", value: types.StringValue("01777"), want: 0o1777},
+		{name: "not set", value: types.StringNull(), wantNil: true},
+		{name: "unknown", value: types.StringUnknown(), wantNil: true},
+		{name: "missing leading zero", value: types.StringValue("755"), wantNil: true, wantErr: true},
+		{name: "not octal", value: types.StringValue("0999"), wantNil: true, wantErr: true},
+		{name: "symbolic", value: types.StringValue("rwxr-xr-x"), wantNil: true, wantErr: true},
+		{name: "too large", value: types.StringValue("017777"), wantNil: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			got := parsePermissionLimit(tt.value, path.Root("permission_policy").AtName("max_file_permissions"), &diags)
+
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("Want error %t, got %v", tt.wantErr, diags)
+			}
+			if tt.wantNil {
+				if got != nil {
+					t.Errorf("Want no limit, got %o", *got)
+				}
+				return
+			}
+			if got == nil || *got != tt.want {
+				t.Errorf("Want limit %o, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// planFile runs FileResource.ModifyPlan for a new file with the given
+// attribute values, leaving the others null.
+func planFile(t *testing.T, r *FileResource, values map[string]tftypes.Value) *resource.ModifyPlanResponse {
+	t.Helper()
+	ctx := context.Background()
+
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	attributes := make(map[string]tftypes.Value, len(objectType.AttributeTypes))
+	for name, attrType := range objectType.AttributeTypes {
+		attributes[name] = tftypes.NewValue(attrType, nil)
+	}
+	for name, value := range values {
+		attributes[name] = value
+	}
+
+	plan := tfsdk.Plan{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, attributes)}
+	req := resource.ModifyPlanRequest{
+		Plan:  plan,
+		State: tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, nil)},
+	}
+	resp := &resource.ModifyPlanResponse{Plan: plan}
+	r.ModifyPlan(ctx, req, resp)
+	return resp
+}
+
+func TestFileResourceModifyPlan_PermissionPolicyMode(t *testing.T) {
+	maxFile := int64(0o755)
+	r := &FileResource{permissionPolicy: PermissionPolicy{MaxFile: &maxFile}}
+
+	file := func(mode int64) map[string]tftypes.Value {
+		return map[string]tftypes.Value{
+			"hostname":    tftypes.NewValue(tftypes.String, "vm-1"),
+			"destination": tftypes.NewValue(tftypes.String, "/etc/app.conf"),
+			"content":     tftypes.NewValue(tftypes.String, "port: 8080\n"),
+			"mode":        tftypes.NewValue(tftypes.Number, mode),
+		}
+	}
+
+	// mode = 511 is 0777, which grants group and world write
+	resp := planFile(t, r, file(0o777))
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("Want mode 0777 to violate a 0755 limit, got no error")
+	}
+	if detail := resp.Diagnostics.Errors()[0].Detail(); !strings.Contains(detail, "0777") {
+		t.Errorf("Want the converted mode in the error, got %q", detail)
+	}
+
+	resp = planFile(t, r, file(0o750))
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected error: %v", resp.Diagnostics)
+	}
+	var permissions types.String
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(context.Background(), path.Root("permissions"), &permissions)...)
+	if permissions.ValueString() != "0750" {
+		t.Errorf("Want permissions 0750 planned from mode, got %s", permissions)
+	}
+}
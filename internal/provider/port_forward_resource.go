@@ -0,0 +1,298 @@
+// Copyright (c) German Arutyunov
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gaarutyunov/terraform-provider-slicer/internal/slicer"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &PortForwardResource{}
+var _ resource.ResourceWithImportState = &PortForwardResource{}
+var _ resource.ResourceWithValidateConfig = &PortForwardResource{}
+var _ resource.ResourceWithModifyPlan = &PortForwardResource{}
+
+func NewPortForwardResource() resource.Resource {
+	return &PortForwardResource{}
+}
+
+// PortForwardResource defines the resource implementation.
+type PortForwardResource struct {
+	client      *slicer.SlicerClient
+	serverInfo  *slicer.SlicerServerInfo
+	tokenScopes []string
+}
+
+// PortForwardResourceModel describes the resource data model.
+type PortForwardResourceModel struct {
+	ID              types.String `tfsdk:"id"`
+	Hostname        types.String `tfsdk:"hostname"`
+	VMPort          types.Int64  `tfsdk:"vm_port"`
+	ExternalPort    types.Int64  `tfsdk:"external_port"`
+	Protocol        types.String `tfsdk:"protocol"`
+	ExternalAddress types.String `tfsdk:"external_address"`
+}
+
+func (r *PortForwardResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_port_forward"
+}
+
+func (r *PortForwardResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Publishes a port of a Slicer VM on its host, so a service running in the VM can be reached from outside the VM network. Changing any attribute replaces the port forward.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The unique identifier of the port forward, assigned by the API.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"hostname": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The hostname of the VM to forward traffic to.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"vm_port": schema.Int64Attribute{
+				Required:            true,
+				MarkdownDescription: "The port of the service on the VM.",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"external_port": schema.Int64Attribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "The port published on the host. If not set, the API picks a free port.",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"protocol": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Protocol to forward: `tcp` or `udp`. Defaults to `tcp`.",
+				Default:             stringdefault.StaticString(firewallProtocolTCP),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"external_address": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The address of the host the port is published on. Null if the API does not report it.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *PortForwardResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*SlicerProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *SlicerProviderData, got: %T", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = providerData.Client
+	r.tokenScopes = providerData.TokenScopes
+	r.serverInfo = providerData.ServerInfo
+}
+
+func (r *PortForwardResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data PortForwardResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	validatePortAttribute(data.VMPort, path.Root("vm_port"), &resp.Diagnostics)
+	validatePortAttribute(data.ExternalPort, path.Root("external_port"), &resp.Diagnostics)
+
+	if !data.Protocol.IsNull() && !data.Protocol.IsUnknown() {
+		protocol := data.Protocol.ValueString()
+		if protocol != firewallProtocolTCP && protocol != firewallProtocolUDP {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("protocol"),
+				"Invalid Protocol",
+				fmt.Sprintf("protocol must be one of 'tcp' or 'udp', got: %s", protocol),
+			)
+		}
+	}
+}
+
+func (r *PortForwardResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	checkScope(req, r.tokenScopes, scopeNetworkWrite, "slicer_port_forward", &resp.Diagnostics)
+
+	// Only creation depends on the server version
+	if req.Plan.Raw.IsNull() || !req.State.Raw.IsNull() {
+		return
+	}
+
+	checkCapability(r.serverInfo, capabilityPortForwards, path.Root("vm_port"), &resp.Diagnostics)
+}
+
+func (r *PortForwardResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data PortForwardResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	forward := slicer.SlicerPortForward{
+		Hostname:     data.Hostname.ValueString(),
+		VMPort:       int(data.VMPort.ValueInt64()),
+		ExternalPort: int(data.ExternalPort.ValueInt64()),
+		Protocol:     data.Protocol.ValueString(),
+	}
+
+	tflog.Debug(ctx, "Creating port forward", map[string]interface{}{
+		"hostname":      forward.Hostname,
+		"vm_port":       forward.VMPort,
+		"external_port": forward.ExternalPort,
+		"protocol":      forward.Protocol,
+	})
+
+	created, err := r.client.CreatePortForward(ctx, forward)
+	if errors.Is(err, slicer.ErrNotSupported) {
+		resp.Diagnostics.AddError(
+			"Port Forwards Not Supported",
+			"The Slicer API does not support publishing VM ports.",
+		)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create port forward: %s", err))
+		return
+	}
+
+	setPortForward(&data, created)
+
+	tflog.Trace(ctx, "Created port forward", map[string]interface{}{
+		"id":            created.ID,
+		"external_port": created.ExternalPort,
+	})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *PortForwardResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data PortForwardResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	forwards, err := r.client.ListPortForwards(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list port forwards: %s", err))
+		return
+	}
+
+	for _, forward := range forwards {
+		if forward.ID == data.ID.ValueString() {
+			setPortForward(&data, &forward)
+			resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+			return
+		}
+	}
+
+	// Port forward was deleted outside of Terraform
+	resp.State.RemoveResource(ctx)
+}
+
+func (r *PortForwardResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data PortForwardResourceModel
+
+	// All configurable attributes require replacement, so there is nothing to
+	// send to the API.
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *PortForwardResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data PortForwardResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Deleting port forward", map[string]interface{}{
+		"id": data.ID.ValueString(),
+	})
+
+	err := r.client.DeletePortForward(ctx, data.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete port forward: %s", err))
+		return
+	}
+
+	tflog.Trace(ctx, "Deleted port forward", map[string]interface{}{
+		"id": data.ID.ValueString(),
+	})
+}
+
+func (r *PortForwardResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// setPortForward stores a port forward returned by the API in the model.
+func setPortForward(data *PortForwardResourceModel, forward *slicer.SlicerPortForward) {
+	data.ID = types.StringValue(forward.ID)
+	data.Hostname = types.StringValue(forward.Hostname)
+	data.VMPort = types.Int64Value(int64(forward.VMPort))
+	data.ExternalPort = types.Int64Value(int64(forward.ExternalPort))
+	data.Protocol = types.StringValue(forward.Protocol)
+	data.ExternalAddress = optionalString(forward.ExternalAddress)
+}
+
+// validatePortAttribute adds an error at attrPath when value is set but not a
+// port between 1 and 65535.
+func validatePortAttribute(value types.Int64, attrPath path.Path, diags *diag.Diagnostics) {
+	if value.IsNull() || value.IsUnknown() {
+		return
+	}
+
+	if port := value.ValueInt64(); port < 1 || port > 65535 {
+		diags.AddAttributeError(
+			attrPath,
+			"Invalid Port",
+			fmt.Sprintf("%s must be between 1 and 65535, got: %d", attrPath, port),
+		)
+	}
+}
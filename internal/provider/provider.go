@@ -4,24 +4,39 @@
 package provider
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
+	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
 	"os"
+	"os/exec"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/gaarutyunov/terraform-provider-slicer/internal/slicer"
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/net/http/httpproxy"
 )
 
 // Ensure SlicerProvider satisfies various provider interfaces.
 var _ provider.Provider = &SlicerProvider{}
+var _ provider.ProviderWithEphemeralResources = &SlicerProvider{}
+var _ provider.ProviderWithActions = &SlicerProvider{}
 
 // SlicerProvider defines the provider implementation.
 type SlicerProvider struct {
@@ -37,11 +52,71 @@ type SlicerProviderModel struct {
 	Token    types.String `tfsdk:"token"`
 	Timeout  types.String `tfsdk:"timeout"`
 	Insecure types.Bool   `tfsdk:"insecure"`
+
+	TokenFile    types.String `tfsdk:"token_file"`
+	TokenCommand types.List   `tfsdk:"token_command"`
+
+	ClientCert     types.String `tfsdk:"client_cert"`
+	ClientKey      types.String `tfsdk:"client_key"`
+	ClientCertFile types.String `tfsdk:"client_cert_file"`
+	ClientKeyFile  types.String `tfsdk:"client_key_file"`
+
+	MaxConcurrentCreates  types.Int64 `tfsdk:"max_concurrent_creates"`
+	MaxConcurrentRequests types.Int64 `tfsdk:"max_concurrent_requests"`
+
+	RequestsPerSecond types.Float64 `tfsdk:"requests_per_second"`
+	MaxRetries        types.Int64   `tfsdk:"max_retries"`
+
+	MaxIdleConns    types.Int64  `tfsdk:"max_idle_conns"`
+	IdleConnTimeout types.String `tfsdk:"idle_conn_timeout"`
+	ForceHTTP2      types.Bool   `tfsdk:"force_http2"`
+
+	ProxyURL types.String `tfsdk:"proxy_url"`
+	NoProxy  types.List   `tfsdk:"no_proxy"`
+
+	PollInterval types.String `tfsdk:"poll_interval"`
+	PollJitter   types.String `tfsdk:"poll_jitter"`
+
+	IgnoredTagPrefixes types.List `tfsdk:"ignored_tag_prefixes"`
+	DefaultTags        types.Map  `tfsdk:"default_tags"`
+
+	PermissionPolicy types.Object `tfsdk:"permission_policy"`
+}
+
+// PermissionPolicyModel describes the permission_policy attribute.
+type PermissionPolicyModel struct {
+	MaxFilePermissions      types.String `tfsdk:"max_file_permissions"`
+	MaxDirectoryPermissions types.String `tfsdk:"max_directory_permissions"`
+	MaxSecretPermissions    types.String `tfsdk:"max_secret_permissions"`
 }
 
 // SlicerProviderData holds the configured client for resources and data sources.
 type SlicerProviderData struct {
 	Client *slicer.SlicerClient
+
+	// IgnoredTagPrefixes lists tag key prefixes managed by Slicer itself,
+	// which are dropped from VM tags on read.
+	IgnoredTagPrefixes []string
+
+	// DefaultTags are added to the tags of every VM created by the provider.
+	// Tags set on a resource take precedence.
+	DefaultTags map[string]string
+
+	// ServerInfo is the control plane version probed at Configure, used to
+	// reject unsupported attributes at plan time. Nil when it is unknown.
+	ServerInfo *slicer.SlicerServerInfo
+
+	// TokenScopes are the scopes granted to the API token, used to reject
+	// plans the token cannot apply. Nil when they are unknown.
+	TokenScopes []string
+
+	// PermissionPolicy caps the permissions of files, directories and
+	// secrets, enforced at plan time.
+	PermissionPolicy PermissionPolicy
+
+	// VMs memoizes the VM list, so that VM lookups in one operation share
+	// a single list request.
+	VMs *vmListCache
 }
 
 func (p *SlicerProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -58,10 +133,19 @@ func (p *SlicerProvider) Schema(ctx context.Context, req provider.SchemaRequest,
 				Optional:            true,
 			},
 			"token": schema.StringAttribute{
-				MarkdownDescription: "The bearer token for Slicer API authentication. Can also be set via the `SLICER_TOKEN` environment variable.",
+				MarkdownDescription: "The bearer token for Slicer API authentication. Can also be set via the `SLICER_TOKEN` environment variable. Conflicts with `token_file` and `token_command`.",
 				Optional:            true,
 				Sensitive:           true,
 			},
+			"token_file": schema.StringAttribute{
+				MarkdownDescription: "Path to a file holding the bearer token. Surrounding whitespace is ignored. Conflicts with `token` and `token_command`.",
+				Optional:            true,
+			},
+			"token_command": schema.ListAttribute{
+				MarkdownDescription: "A credential helper that prints the bearer token to stdout, given as the program and its arguments, e.g. `[\"sso-token\", \"--audience\", \"slicer\"]`. It runs once when the provider is configured and is stopped after `timeout`. Conflicts with `token` and `token_file`.",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
 			"timeout": schema.StringAttribute{
 				MarkdownDescription: "HTTP client timeout (e.g., '30s', '1m'). Defaults to '30s'.",
 				Optional:            true,
@@ -70,6 +154,96 @@ func (p *SlicerProvider) Schema(ctx context.Context, req provider.SchemaRequest,
 				MarkdownDescription: "Skip TLS certificate verification. Defaults to false.",
 				Optional:            true,
 			},
+			"client_cert": schema.StringAttribute{
+				MarkdownDescription: "PEM encoded client certificate presented to the Slicer endpoint, e.g. when it sits behind an mTLS gateway. Requires `client_key` or `client_key_file`. Conflicts with `client_cert_file`.",
+				Optional:            true,
+			},
+			"client_key": schema.StringAttribute{
+				MarkdownDescription: "PEM encoded private key of the client certificate. Conflicts with `client_key_file`.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"client_cert_file": schema.StringAttribute{
+				MarkdownDescription: "Path to a PEM encoded client certificate. Conflicts with `client_cert`.",
+				Optional:            true,
+			},
+			"client_key_file": schema.StringAttribute{
+				MarkdownDescription: "Path to the PEM encoded private key of the client certificate. Conflicts with `client_key`.",
+				Optional:            true,
+			},
+			"max_concurrent_creates": schema.Int64Attribute{
+				MarkdownDescription: "Maximum number of VMs created in parallel. Set to `1` to serialize VM creation. Defaults to unlimited, i.e. bounded only by Terraform's parallelism.",
+				Optional:            true,
+			},
+			"max_concurrent_requests": schema.Int64Attribute{
+				MarkdownDescription: "Maximum number of requests sent to the Slicer API in parallel, across all resources and data sources. Requests beyond the limit wait for a free slot, so large applies do not overwhelm the control plane while Terraform keeps working on resources that do not need the API. Defaults to unlimited.",
+				Optional:            true,
+			},
+			"requests_per_second": schema.Float64Attribute{
+				MarkdownDescription: "Maximum rate of requests sent to the Slicer API, across all resources and data sources, e.g. `0.5` for one request every two seconds. Requests beyond the rate wait for their turn. Defaults to unlimited.",
+				Optional:            true,
+			},
+			"max_retries": schema.Int64Attribute{
+				MarkdownDescription: "How often a request the Slicer API rejects with 429 Too Many Requests is retried. The provider waits as long as the `Retry-After` header asks, up to 30 seconds, or backs off exponentially without one, and holds back its other requests in the meantime. A longer `Retry-After` fails the request. Set to `0` to fail on the first 429. Defaults to 5.",
+				Optional:            true,
+			},
+			"max_idle_conns": schema.Int64Attribute{
+				MarkdownDescription: "Maximum number of idle (keep-alive) connections kept open to the Slicer API. Set to `0` to disable keep-alives, opening a new connection for every request. Defaults to Go's per-host default of 2.",
+				Optional:            true,
+			},
+			"idle_conn_timeout": schema.StringAttribute{
+				MarkdownDescription: "How long an idle keep-alive connection is kept open (e.g., '90s'). Defaults to no limit.",
+				Optional:            true,
+			},
+			"force_http2": schema.BoolAttribute{
+				MarkdownDescription: "Attempt HTTP/2 even when custom TLS settings are used. Defaults to false.",
+				Optional:            true,
+			},
+			"proxy_url": schema.StringAttribute{
+				MarkdownDescription: "URL of an HTTP, HTTPS or SOCKS5 proxy to reach the Slicer API through, e.g. `http://proxy.corp.example.com:3128`. Credentials may be given in the URL. The `HTTP_PROXY`, `HTTPS_PROXY` and `NO_PROXY` environment variables are never used. Defaults to connecting directly.",
+				Optional:            true,
+			},
+			"no_proxy": schema.ListAttribute{
+				MarkdownDescription: "Hosts, domains (e.g. `.corp.example.com`) and CIDR ranges reached directly instead of through `proxy_url`. Requires `proxy_url`. Loopback addresses are always reached directly.",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+			"poll_interval": schema.StringAttribute{
+				MarkdownDescription: "How often wait operations, such as waiting for a `slicer_job`, poll the Slicer API (e.g., '5s'). Defaults to '1s'.",
+				Optional:            true,
+			},
+			"poll_jitter": schema.StringAttribute{
+				MarkdownDescription: "Maximum random delay added to every poll so many resources waiting at once do not poll in lockstep (e.g., '500ms'). Defaults to no jitter.",
+				Optional:            true,
+			},
+			"ignored_tag_prefixes": schema.ListAttribute{
+				MarkdownDescription: "Tag key prefixes that Slicer manages itself (e.g. scheduling hints). Matching tags are ignored when reading `slicer_vm` resources so they do not show up as drift.",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+			"default_tags": schema.MapAttribute{
+				MarkdownDescription: "Tags added to every VM created by `slicer_vm` and `slicer_vm_pool`, e.g. team or environment. Tags set on a resource override these.",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+			"permission_policy": schema.SingleNestedAttribute{
+				MarkdownDescription: "Maximum permissions resources may set, enforced at plan time. Each limit is an octal string such as '0755'; a plan fails if it grants any bit the limit does not.",
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"max_file_permissions": schema.StringAttribute{
+						MarkdownDescription: "Maximum permissions for files created by `slicer_file`.",
+						Optional:            true,
+					},
+					"max_directory_permissions": schema.StringAttribute{
+						MarkdownDescription: "Maximum permissions for parent directories created by `slicer_file`.",
+						Optional:            true,
+					},
+					"max_secret_permissions": schema.StringAttribute{
+						MarkdownDescription: "Maximum permissions for `slicer_secret` resources.",
+						Optional:            true,
+					},
+				},
+			},
 		},
 	}
 }
@@ -83,6 +257,37 @@ func (p *SlicerProvider) Configure(ctx context.Context, req provider.ConfigureRe
 		return
 	}
 
+	// The endpoint or token may come from resources that are not applied
+	// yet, e.g. the Slicer server itself. Terraform can then defer the
+	// resources of this provider to a later plan instead of failing.
+	if !req.Config.Raw.IsFullyKnown() {
+		if req.ClientCapabilities.DeferralAllowed {
+			tflog.Debug(ctx, "Deferring Slicer provider configuration with unknown values")
+			resp.Deferred = &provider.Deferred{
+				Reason: provider.DeferredReasonProviderConfigUnknown,
+			}
+			return
+		}
+
+		// Without deferral, only the attributes needed to reach the API
+		// must be known; the others are treated as unset until they are
+		if unknownConnectionAttributes(ctx, data) {
+			resp.Diagnostics.AddError(
+				"Unknown Slicer Provider Configuration",
+				"The provider configuration depends on values that are only known after apply, e.g. the endpoint of a Slicer server created in the same configuration. "+
+					"Apply the resources it depends on first, e.g. with -target, or use a Terraform version that supports deferred actions.",
+			)
+			return
+		}
+
+		resp.Diagnostics.AddWarning(
+			"Unknown Slicer Provider Configuration",
+			"Some provider settings depend on values that are only known after apply and are treated as unset until then. "+
+				"Apply the resources they depend on first, e.g. with -target, or use a Terraform version that supports deferred actions.",
+		)
+		data = withoutUnknownSettings(ctx, data)
+	}
+
 	// Get endpoint from config or environment
 	endpoint := os.Getenv("SLICER_ENDPOINT")
 	if !data.Endpoint.IsNull() {
@@ -98,18 +303,29 @@ func (p *SlicerProvider) Configure(ctx context.Context, req provider.ConfigureRe
 		)
 	}
 
-	// Get token from config or environment
-	token := os.Getenv("SLICER_TOKEN")
-	if !data.Token.IsNull() {
-		token = data.Token.ValueString()
+	// Parse timeout, which also bounds the token command
+	timeout := 30 * time.Second
+	if !data.Timeout.IsNull() {
+		parsed, err := time.ParseDuration(data.Timeout.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("timeout"),
+				"Invalid Timeout Value",
+				"Could not parse timeout value: "+err.Error(),
+			)
+			return
+		}
+		timeout = parsed
 	}
 
-	if token == "" {
+	// Get token from config, a file, a credential helper or environment
+	token := resolveToken(ctx, data, timeout, &resp.Diagnostics)
+	if token == "" && !resp.Diagnostics.HasError() {
 		resp.Diagnostics.AddAttributeError(
 			path.Root("token"),
 			"Missing Slicer API Token",
 			"The provider cannot create the Slicer API client without a token. "+
-				"Either set the token in the provider configuration or use the SLICER_TOKEN environment variable.",
+				"Either set token, token_file or token_command in the provider configuration or use the SLICER_TOKEN environment variable.",
 		)
 	}
 
@@ -117,47 +333,192 @@ func (p *SlicerProvider) Configure(ctx context.Context, req provider.ConfigureRe
 		return
 	}
 
-	// Parse timeout
-	timeout := 30 * time.Second
-	if !data.Timeout.IsNull() {
-		parsed, err := time.ParseDuration(data.Timeout.ValueString())
-		if err != nil {
+	var ignoredTagPrefixes []string
+	if !data.IgnoredTagPrefixes.IsNull() {
+		resp.Diagnostics.Append(data.IgnoredTagPrefixes.ElementsAs(ctx, &ignoredTagPrefixes, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	var defaultTags map[string]string
+	if !data.DefaultTags.IsNull() {
+		resp.Diagnostics.Append(data.DefaultTags.ElementsAs(ctx, &defaultTags, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	var permissionPolicy PermissionPolicy
+	if !data.PermissionPolicy.IsNull() {
+		var policy PermissionPolicyModel
+		resp.Diagnostics.Append(data.PermissionPolicy.As(ctx, &policy, basetypes.ObjectAsOptions{})...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		policyPath := path.Root("permission_policy")
+		permissionPolicy.MaxFile = parsePermissionLimit(policy.MaxFilePermissions, policyPath.AtName("max_file_permissions"), &resp.Diagnostics)
+		permissionPolicy.MaxDirectory = parsePermissionLimit(policy.MaxDirectoryPermissions, policyPath.AtName("max_directory_permissions"), &resp.Diagnostics)
+		permissionPolicy.MaxSecret = parsePermissionLimit(policy.MaxSecretPermissions, policyPath.AtName("max_secret_permissions"), &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	var clientOpts []slicer.ClientOption
+	if !data.MaxConcurrentCreates.IsNull() {
+		maxCreates := data.MaxConcurrentCreates.ValueInt64()
+		if maxCreates < 1 {
 			resp.Diagnostics.AddAttributeError(
-				path.Root("timeout"),
-				"Invalid Timeout Value",
-				"Could not parse timeout value: "+err.Error(),
+				path.Root("max_concurrent_creates"),
+				"Invalid Max Concurrent Creates Value",
+				"max_concurrent_creates must be at least 1.",
 			)
 			return
 		}
-		timeout = parsed
+		clientOpts = append(clientOpts, slicer.WithMaxConcurrentCreates(int(maxCreates)))
 	}
 
+	if !data.MaxConcurrentRequests.IsNull() {
+		maxRequests := data.MaxConcurrentRequests.ValueInt64()
+		if maxRequests < 1 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("max_concurrent_requests"),
+				"Invalid Max Concurrent Requests Value",
+				"max_concurrent_requests must be at least 1.",
+			)
+			return
+		}
+		clientOpts = append(clientOpts, slicer.WithMaxConcurrentRequests(int(maxRequests)))
+	}
+
+	if !data.RequestsPerSecond.IsNull() {
+		requestsPerSecond := data.RequestsPerSecond.ValueFloat64()
+		if requestsPerSecond <= 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("requests_per_second"),
+				"Invalid Requests Per Second Value",
+				"requests_per_second must be greater than 0.",
+			)
+			return
+		}
+		clientOpts = append(clientOpts, slicer.WithRateLimit(requestsPerSecond))
+	}
+
+	if !data.MaxRetries.IsNull() {
+		maxRetries := data.MaxRetries.ValueInt64()
+		if maxRetries < 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("max_retries"),
+				"Invalid Max Retries Value",
+				"max_retries must not be negative.",
+			)
+			return
+		}
+		clientOpts = append(clientOpts, slicer.WithRetries(int(maxRetries)))
+	}
+
+	pollInterval := parsePollDuration(data.PollInterval, path.Root("poll_interval"), &resp.Diagnostics)
+	pollJitter := parsePollDuration(data.PollJitter, path.Root("poll_jitter"), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	clientOpts = append(clientOpts, slicer.WithPolling(pollInterval, pollJitter))
+
 	// Configure HTTP client
-	transport := &http.Transport{}
-	if !data.Insecure.IsNull() && data.Insecure.ValueBool() {
-		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	transport := newTransport(ctx, data, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var roundTripper http.RoundTripper = transport
+
+	// Record or replay API interactions when requested via environment
+	if mode := os.Getenv("SLICER_VCR_MODE"); mode != "" {
+		cassette := os.Getenv("SLICER_VCR_CASSETTE")
+		if cassette == "" {
+			resp.Diagnostics.AddError(
+				"Missing VCR Cassette",
+				"SLICER_VCR_CASSETTE must be set to the fixture path when SLICER_VCR_MODE is set.",
+			)
+			return
+		}
+
+		vcr, err := slicer.NewVCRTransport(slicer.VCRMode(mode), cassette, transport)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Invalid VCR Configuration",
+				"Could not set up API recording: "+err.Error(),
+			)
+			return
+		}
+		roundTripper = vcr
+
+		tflog.Debug(ctx, "Using VCR transport", map[string]interface{}{
+			"mode":     mode,
+			"cassette": cassette,
+		})
 	}
 
 	httpClient := &http.Client{
 		Timeout:   timeout,
-		Transport: transport,
+		Transport: roundTripper,
 	}
 
 	// Create Slicer client
 	userAgent := "terraform-provider-slicer/" + p.version
-	client := slicer.NewSlicerClient(endpoint, token, userAgent, httpClient)
+	client := slicer.NewSlicerClient(endpoint, token, userAgent, httpClient, clientOpts...)
 
 	tflog.Debug(ctx, "Configured Slicer client", map[string]interface{}{
 		"endpoint": endpoint,
 		"timeout":  timeout.String(),
 	})
 
+	// Probe the control plane version so plans fail early on attributes it
+	// does not support. Older servers have no info endpoint; resources then
+	// fall back to runtime errors from the API.
+	serverInfo, err := client.GetServerInfo(ctx)
+	if err != nil {
+		tflog.Debug(ctx, "Unable to determine Slicer server version, skipping capability checks", map[string]interface{}{
+			"error": err.Error(),
+		})
+		serverInfo = nil
+	} else {
+		tflog.Debug(ctx, "Detected Slicer server version", map[string]interface{}{
+			"version": serverInfo.Version,
+		})
+	}
+
+	// Look up what the token may do so plans fail with a targeted error
+	// instead of a 403 midway through an apply.
+	var tokenScopes []string
+	tokenInfo, err := client.GetTokenInfo(ctx)
+	if err != nil {
+		tflog.Debug(ctx, "Unable to determine token scopes, skipping permission checks", map[string]interface{}{
+			"error": err.Error(),
+		})
+	} else {
+		tokenScopes = tokenInfo.Scopes
+		tflog.Debug(ctx, "Detected token scopes", map[string]interface{}{
+			"scopes": tokenScopes,
+		})
+	}
+
 	providerData := &SlicerProviderData{
-		Client: client,
+		Client:             client,
+		IgnoredTagPrefixes: ignoredTagPrefixes,
+		DefaultTags:        defaultTags,
+		ServerInfo:         serverInfo,
+		TokenScopes:        tokenScopes,
+		PermissionPolicy:   permissionPolicy,
+		VMs:                newVMListCache(client, vmListCacheTTL),
 	}
 
 	resp.DataSourceData = providerData
 	resp.ResourceData = providerData
+	resp.EphemeralResourceData = providerData
+	resp.ActionData = providerData
 }
 
 func (p *SlicerProvider) Resources(ctx context.Context) []func() resource.Resource {
@@ -166,6 +527,17 @@ func (p *SlicerProvider) Resources(ctx context.Context) []func() resource.Resour
 		NewExecResource,
 		NewFileResource,
 		NewSecretResource,
+		NewHostgroupResource,
+		NewMaintenanceResource,
+		NewVMTemplateResource,
+		NewIPReservationResource,
+		NewJobResource,
+		NewVMPoolResource,
+		NewSnapshotResource,
+		NewVolumeResource,
+		NewFirewallRuleResource,
+		NewPortForwardResource,
+		NewSSHKeyResource,
 	}
 }
 
@@ -174,7 +546,28 @@ func (p *SlicerProvider) DataSources(ctx context.Context) []func() datasource.Da
 		NewVMDataSource,
 		NewVMsDataSource,
 		NewHostgroupsDataSource,
+		NewHostgroupDataSource,
 		NewSecretDataSource,
+		NewSecretsDataSource,
+		NewServerInfoDataSource,
+		NewHealthDataSource,
+		NewSubnetsDataSource,
+		NewSnapshotDataSource,
+		NewRemoteFileDataSource,
+		NewSSHKeyDataSource,
+		NewExecDataSource,
+	}
+}
+
+func (p *SlicerProvider) EphemeralResources(ctx context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		NewSecretValueEphemeralResource,
+	}
+}
+
+func (p *SlicerProvider) Actions(ctx context.Context) []func() action.Action {
+	return []func() action.Action{
+		NewVMRebootAction,
 	}
 }
 
@@ -185,3 +578,333 @@ func New(version string) func() provider.Provider {
 		}
 	}
 }
+
+// newTransport returns the HTTP transport configured by the TLS, connection
+// pool and proxy attributes. Invalid values are reported in diags.
+func newTransport(ctx context.Context, data SlicerProviderModel, diags *diag.Diagnostics) *http.Transport {
+	transport := &http.Transport{}
+	if !data.Insecure.IsNull() && data.Insecure.ValueBool() {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+
+	clientCert := loadClientCertificate(data, diags)
+	if diags.HasError() {
+		return nil
+	}
+	if clientCert != nil {
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{}
+		}
+		transport.TLSClientConfig.Certificates = []tls.Certificate{*clientCert}
+	}
+
+	if !data.MaxIdleConns.IsNull() {
+		maxIdleConns := data.MaxIdleConns.ValueInt64()
+		if maxIdleConns < 0 {
+			diags.AddAttributeError(
+				path.Root("max_idle_conns"),
+				"Invalid Max Idle Connections Value",
+				"max_idle_conns must not be negative.",
+			)
+			return nil
+		}
+		if maxIdleConns == 0 {
+			// Go reads 0 as its defaults, not as "no idle connections"
+			transport.DisableKeepAlives = true
+		} else {
+			// All requests go to a single host, so the per-host limit is the one that matters
+			transport.MaxIdleConns = int(maxIdleConns)
+			transport.MaxIdleConnsPerHost = int(maxIdleConns)
+		}
+	}
+
+	if !data.IdleConnTimeout.IsNull() {
+		parsed, err := time.ParseDuration(data.IdleConnTimeout.ValueString())
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("idle_conn_timeout"),
+				"Invalid Idle Connection Timeout Value",
+				"Could not parse idle_conn_timeout value: "+err.Error(),
+			)
+			return nil
+		}
+		transport.IdleConnTimeout = parsed
+	}
+
+	if !data.ForceHTTP2.IsNull() {
+		transport.ForceAttemptHTTP2 = data.ForceHTTP2.ValueBool()
+	}
+
+	transport.Proxy = proxyFunc(ctx, data, diags)
+	if diags.HasError() {
+		return nil
+	}
+
+	return transport
+}
+
+// unknownConnectionAttributes reports whether any attribute needed to reach
+// the API, such as the endpoint, token or client certificate, is not known.
+func unknownConnectionAttributes(ctx context.Context, data SlicerProviderModel) bool {
+	for _, value := range []attr.Value{
+		data.Endpoint, data.Token, data.TokenFile, data.TokenCommand, data.Insecure,
+		data.ClientCert, data.ClientKey, data.ClientCertFile, data.ClientKeyFile,
+		data.ProxyURL, data.NoProxy,
+	} {
+		if !fullyKnown(ctx, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// withoutUnknownSettings returns data with the settings that are not fully
+// known set to null, so they fall back to their defaults.
+func withoutUnknownSettings(ctx context.Context, data SlicerProviderModel) SlicerProviderModel {
+	if !fullyKnown(ctx, data.Timeout) {
+		data.Timeout = types.StringNull()
+	}
+	if !fullyKnown(ctx, data.MaxConcurrentCreates) {
+		data.MaxConcurrentCreates = types.Int64Null()
+	}
+	if !fullyKnown(ctx, data.MaxConcurrentRequests) {
+		data.MaxConcurrentRequests = types.Int64Null()
+	}
+	if !fullyKnown(ctx, data.RequestsPerSecond) {
+		data.RequestsPerSecond = types.Float64Null()
+	}
+	if !fullyKnown(ctx, data.MaxRetries) {
+		data.MaxRetries = types.Int64Null()
+	}
+	if !fullyKnown(ctx, data.MaxIdleConns) {
+		data.MaxIdleConns = types.Int64Null()
+	}
+	if !fullyKnown(ctx, data.IdleConnTimeout) {
+		data.IdleConnTimeout = types.StringNull()
+	}
+	if !fullyKnown(ctx, data.ForceHTTP2) {
+		data.ForceHTTP2 = types.BoolNull()
+	}
+	if !fullyKnown(ctx, data.PollInterval) {
+		data.PollInterval = types.StringNull()
+	}
+	if !fullyKnown(ctx, data.PollJitter) {
+		data.PollJitter = types.StringNull()
+	}
+	if !fullyKnown(ctx, data.IgnoredTagPrefixes) {
+		data.IgnoredTagPrefixes = types.ListNull(types.StringType)
+	}
+	if !fullyKnown(ctx, data.DefaultTags) {
+		data.DefaultTags = types.MapNull(types.StringType)
+	}
+	if !fullyKnown(ctx, data.PermissionPolicy) {
+		data.PermissionPolicy = types.ObjectNull(data.PermissionPolicy.AttributeTypes(ctx))
+	}
+	return data
+}
+
+// fullyKnown reports whether value and all values nested in it are known.
+func fullyKnown(ctx context.Context, value attr.Value) bool {
+	tfValue, err := value.ToTerraformValue(ctx)
+	return err == nil && tfValue.IsFullyKnown()
+}
+
+// resolveToken returns the bearer token given inline, read from token_file or
+// printed by token_command, falling back to SLICER_TOKEN when none is set.
+// token_command is stopped if it runs longer than timeout.
+func resolveToken(ctx context.Context, data SlicerProviderModel, timeout time.Duration, diags *diag.Diagnostics) string {
+	sources := 0
+	for _, set := range []bool{!data.Token.IsNull(), !data.TokenFile.IsNull(), !data.TokenCommand.IsNull()} {
+		if set {
+			sources++
+		}
+	}
+	if sources > 1 {
+		diags.AddError(
+			"Conflicting Attributes",
+			"Only one of 'token', 'token_file' or 'token_command' can be specified.",
+		)
+		return ""
+	}
+
+	switch {
+	case !data.Token.IsNull():
+		return data.Token.ValueString()
+
+	case !data.TokenFile.IsNull():
+		token, err := os.ReadFile(data.TokenFile.ValueString())
+		if err != nil {
+			diags.AddAttributeError(path.Root("token_file"), "Unable to Read File", err.Error())
+			return ""
+		}
+		return strings.TrimSpace(string(token))
+
+	case !data.TokenCommand.IsNull():
+		var command []string
+		diags.Append(data.TokenCommand.ElementsAs(ctx, &command, false)...)
+		if diags.HasError() {
+			return ""
+		}
+		if len(command) == 0 {
+			diags.AddAttributeError(path.Root("token_command"), "Invalid Token Command", "token_command must name a program to run.")
+			return ""
+		}
+
+		tflog.Debug(ctx, "Running token command", map[string]interface{}{
+			"command": command[0],
+		})
+
+		// A helper waiting for input, e.g. an SSO prompt, must not block the plan
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		var stdout, stderr bytes.Buffer
+		cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		// Children of the helper may keep its output open after it is killed
+		cmd.WaitDelay = time.Second
+		if err := cmd.Run(); err != nil {
+			if ctx.Err() != nil {
+				err = fmt.Errorf("timed out after %s", timeout)
+			}
+			diags.AddAttributeError(
+				path.Root("token_command"),
+				"Token Command Failed",
+				fmt.Sprintf("Unable to run %s: %s\n\n%s", command[0], err, strings.TrimSpace(stderr.String())),
+			)
+			return ""
+		}
+		return strings.TrimSpace(stdout.String())
+	}
+
+	return os.Getenv("SLICER_TOKEN")
+}
+
+// loadClientCertificate loads the mTLS client certificate from the inline or
+// file attributes. It returns nil when no certificate is configured.
+func loadClientCertificate(data SlicerProviderModel, diags *diag.Diagnostics) *tls.Certificate {
+	certPEM := readPEMAttribute(data.ClientCert, data.ClientCertFile, path.Root("client_cert"), path.Root("client_cert_file"), diags)
+	keyPEM := readPEMAttribute(data.ClientKey, data.ClientKeyFile, path.Root("client_key"), path.Root("client_key_file"), diags)
+	if diags.HasError() || (certPEM == nil && keyPEM == nil) {
+		return nil
+	}
+
+	if certPEM == nil || keyPEM == nil {
+		diags.AddError(
+			"Incomplete Client Certificate",
+			"A client certificate needs both a certificate (client_cert or client_cert_file) and a key (client_key or client_key_file).",
+		)
+		return nil
+	}
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		diags.AddError(
+			"Invalid Client Certificate",
+			"Could not load the client certificate: "+err.Error(),
+		)
+		return nil
+	}
+
+	return &cert
+}
+
+// readPEMAttribute returns the PEM data given inline or read from a file, nil
+// if neither is set.
+func readPEMAttribute(inline, file types.String, inlinePath, filePath path.Path, diags *diag.Diagnostics) []byte {
+	if !inline.IsNull() && !file.IsNull() {
+		diags.AddAttributeError(
+			filePath,
+			"Conflicting Attributes",
+			fmt.Sprintf("Only one of '%s' or '%s' can be specified.", inlinePath, filePath),
+		)
+		return nil
+	}
+
+	if !inline.IsNull() {
+		return []byte(inline.ValueString())
+	}
+
+	if !file.IsNull() {
+		pemData, err := os.ReadFile(file.ValueString())
+		if err != nil {
+			diags.AddAttributeError(filePath, "Unable to Read File", err.Error())
+			return nil
+		}
+		return pemData
+	}
+
+	return nil
+}
+
+// proxyFunc returns the proxy selection of the transport. The environment
+// is deliberately ignored, so only the provider's own settings apply. It
+// returns nil, i.e. direct connections, when no proxy is configured.
+func proxyFunc(ctx context.Context, data SlicerProviderModel, diags *diag.Diagnostics) func(*http.Request) (*url.URL, error) {
+	var noProxy []string
+	if !data.NoProxy.IsNull() {
+		diags.Append(data.NoProxy.ElementsAs(ctx, &noProxy, false)...)
+		if diags.HasError() {
+			return nil
+		}
+	}
+
+	if data.ProxyURL.IsNull() {
+		if len(noProxy) > 0 {
+			diags.AddAttributeError(
+				path.Root("no_proxy"),
+				"Missing Attribute",
+				"'no_proxy' requires 'proxy_url'.",
+			)
+		}
+		return nil
+	}
+
+	proxyURL, err := url.Parse(data.ProxyURL.ValueString())
+	if err == nil && !slices.Contains([]string{"http", "https", "socks5"}, proxyURL.Scheme) {
+		err = fmt.Errorf("unsupported scheme %q, expected http, https or socks5", proxyURL.Scheme)
+	}
+	if err == nil && proxyURL.Host == "" {
+		err = errors.New("missing host")
+	}
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("proxy_url"),
+			"Invalid Proxy URL",
+			"Could not parse proxy_url value: "+err.Error(),
+		)
+		return nil
+	}
+
+	config := &httpproxy.Config{
+		HTTPProxy:  proxyURL.String(),
+		HTTPSProxy: proxyURL.String(),
+		NoProxy:    strings.Join(noProxy, ","),
+	}
+	proxy := config.ProxyFunc()
+
+	return func(req *http.Request) (*url.URL, error) {
+		return proxy(req.URL)
+	}
+}
+
+// parsePollDuration parses a polling setting. Null values return 0, which
+// keeps the client default.
+func parsePollDuration(value types.String, attrPath path.Path, diags *diag.Diagnostics) time.Duration {
+	if value.IsNull() {
+		return 0
+	}
+
+	parsed, err := time.ParseDuration(value.ValueString())
+	if err != nil {
+		diags.AddAttributeError(attrPath, "Invalid Polling Value", "Could not parse polling value: "+err.Error())
+		return 0
+	}
+	if parsed < 0 {
+		diags.AddAttributeError(attrPath, "Invalid Polling Value", "Polling durations must not be negative.")
+		return 0
+	}
+
+	return parsed
+}
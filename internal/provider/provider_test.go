@@ -4,10 +4,22 @@
 package provider
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-testing/echoprovider"
 )
 
@@ -39,3 +51,278 @@ var (
 	_ = testAccProtoV6ProviderFactoriesWithEcho
 	_ = testAccPreCheck
 )
+
+// configureProvider configures the provider with the given attribute values,
+// leaving the others null.
+func configureProvider(t *testing.T, values map[string]tftypes.Value, deferralAllowed bool) *provider.ConfigureResponse {
+	t.Helper()
+	ctx := context.Background()
+
+	p := New("test")()
+	var schemaResp provider.SchemaResponse
+	p.Schema(ctx, provider.SchemaRequest{}, &schemaResp)
+
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	attributes := make(map[string]tftypes.Value, len(objectType.AttributeTypes))
+	for name, attrType := range objectType.AttributeTypes {
+		attributes[name] = tftypes.NewValue(attrType, nil)
+	}
+	for name, value := range values {
+		attributes[name] = value
+	}
+
+	req := provider.ConfigureRequest{
+		Config:             tfsdk.Config{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, attributes)},
+		ClientCapabilities: provider.ConfigureProviderClientCapabilities{DeferralAllowed: deferralAllowed},
+	}
+	resp := &provider.ConfigureResponse{}
+	p.Configure(ctx, req, resp)
+	return resp
+}
+
+// newConfigureTestServer returns an API without the optional info endpoints.
+func newConfigureTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestConfigure_UnknownEndpoint(t *testing.T) {
+	values := map[string]tftypes.Value{
+		"endpoint": tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+		"token":    tftypes.NewValue(tftypes.String, "token"),
+	}
+
+	resp := configureProvider(t, values, true)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected error: %v", resp.Diagnostics)
+	}
+	if resp.Deferred == nil || resp.Deferred.Reason != provider.DeferredReasonProviderConfigUnknown {
+		t.Errorf("Want configuration deferred, got %v", resp.Deferred)
+	}
+
+	resp = configureProvider(t, values, false)
+	if !resp.Diagnostics.HasError() {
+		t.Error("Want an error without deferral, got none")
+	}
+	if resp.ResourceData != nil {
+		t.Error("Want no client for an unknown endpoint")
+	}
+}
+
+func TestConfigure_UnknownSettings(t *testing.T) {
+	server := newConfigureTestServer(t)
+
+	policyType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"max_file_permissions":      tftypes.String,
+		"max_directory_permissions": tftypes.String,
+		"max_secret_permissions":    tftypes.String,
+	}}
+	values := map[string]tftypes.Value{
+		"endpoint":          tftypes.NewValue(tftypes.String, server.URL),
+		"token":             tftypes.NewValue(tftypes.String, "token"),
+		"default_tags":      tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, tftypes.UnknownValue),
+		"permission_policy": tftypes.NewValue(policyType, tftypes.UnknownValue),
+		"timeout":           tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+	}
+
+	resp := configureProvider(t, values, true)
+	if resp.Deferred == nil {
+		t.Error("Want configuration deferred when deferral is allowed, got none")
+	}
+
+	// Without deferral, the provider still works with the unknown settings unset
+	resp = configureProvider(t, values, false)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected error: %v", resp.Diagnostics)
+	}
+	if resp.Deferred != nil {
+		t.Errorf("Want no deferral, got %v", resp.Deferred)
+	}
+	if resp.Diagnostics.WarningsCount() != 1 {
+		t.Errorf("Want 1 warning about the unknown settings, got %v", resp.Diagnostics)
+	}
+
+	data, ok := resp.ResourceData.(*SlicerProviderData)
+	if !ok {
+		t.Fatalf("Want *SlicerProviderData, got %T", resp.ResourceData)
+	}
+	if data.DefaultTags != nil {
+		t.Errorf("Want no default tags, got %v", data.DefaultTags)
+	}
+}
+
+// tokenSources returns a provider model with the given token attributes, any
+// of which may be null.
+func tokenSources(token, tokenFile types.String, tokenCommand ...string) SlicerProviderModel {
+	command := types.ListNull(types.StringType)
+	if len(tokenCommand) > 0 {
+		command, _ = types.ListValueFrom(context.Background(), types.StringType, tokenCommand)
+	}
+	return SlicerProviderModel{Token: token, TokenFile: tokenFile, TokenCommand: command}
+}
+
+func TestResolveToken(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("  file-token\n"), 0o600); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	t.Setenv("SLICER_TOKEN", "env-token")
+
+	tests := []struct {
+		name    string
+		data    SlicerProviderModel
+		want    string
+		wantErr string
+	}{
+		{
+			name: "inline",
+			data: tokenSources(types.StringValue("inline-token"), types.StringNull()),
+			want: "inline-token",
+		},
+		{
+			name: "file is trimmed",
+			data: tokenSources(types.StringNull(), types.StringValue(tokenFile)),
+			want: "file-token",
+		},
+		{
+			name:    "missing file",
+			data:    tokenSources(types.StringNull(), types.StringValue(filepath.Join(t.TempDir(), "missing"))),
+			wantErr: "Unable to Read File",
+		},
+		{
+			name: "command output is trimmed",
+			data: tokenSources(types.StringNull(), types.StringNull(), "sh", "-c", "echo command-token"),
+			want: "command-token",
+		},
+		{
+			name:    "command fails",
+			data:    tokenSources(types.StringNull(), types.StringNull(), "sh", "-c", "echo expired >&2; exit 1"),
+			wantErr: "Token Command Failed",
+		},
+		{
+			name:    "conflicting sources",
+			data:    tokenSources(types.StringValue("inline-token"), types.StringValue(tokenFile)),
+			wantErr: "Conflicting Attributes",
+		},
+		{
+			name: "environment fallback",
+			data: tokenSources(types.StringNull(), types.StringNull()),
+			want: "env-token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			got := resolveToken(context.Background(), tt.data, time.Minute, &diags)
+
+			if tt.wantErr != "" {
+				if !diags.HasError() || diags.Errors()[0].Summary() != tt.wantErr {
+					t.Errorf("Want error %q, got %v", tt.wantErr, diags)
+				}
+				return
+			}
+			if diags.HasError() {
+				t.Fatalf("Unexpected error: %v", diags)
+			}
+			if got != tt.want {
+				t.Errorf("Want token %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestResolveToken_CommandTimeout(t *testing.T) {
+	var diags diag.Diagnostics
+	data := tokenSources(types.StringNull(), types.StringNull(), "sleep", "60")
+
+	start := time.Now()
+	resolveToken(context.Background(), data, 100*time.Millisecond, &diags)
+
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("Want the command stopped after the timeout, took %s", elapsed)
+	}
+	if !diags.HasError() || !strings.Contains(diags.Errors()[0].Detail(), "timed out after 100ms") {
+		t.Errorf("Want a timeout error, got %v", diags)
+	}
+}
+
+func TestNewTransport(t *testing.T) {
+	tests := []struct {
+		name                string
+		data                SlicerProviderModel
+		wantMaxIdlePerHost  int
+		wantKeepAlivesOff   bool
+		wantIdleConnTimeout time.Duration
+		wantInsecure        bool
+		wantErr             bool
+	}{
+		{
+			name: "defaults",
+		},
+		{
+			name:               "max_idle_conns",
+			data:               SlicerProviderModel{MaxIdleConns: types.Int64Value(16)},
+			wantMaxIdlePerHost: 16,
+		},
+		{
+			name:              "max_idle_conns 0 disables keep-alives",
+			data:              SlicerProviderModel{MaxIdleConns: types.Int64Value(0)},
+			wantKeepAlivesOff: true,
+		},
+		{
+			name:    "negative max_idle_conns",
+			data:    SlicerProviderModel{MaxIdleConns: types.Int64Value(-1)},
+			wantErr: true,
+		},
+		{
+			name:                "idle_conn_timeout",
+			data:                SlicerProviderModel{IdleConnTimeout: types.StringValue("90s")},
+			wantIdleConnTimeout: 90 * time.Second,
+		},
+		{
+			name:    "invalid idle_conn_timeout",
+			data:    SlicerProviderModel{IdleConnTimeout: types.StringValue("soon")},
+			wantErr: true,
+		},
+		{
+			name:         "insecure",
+			data:         SlicerProviderModel{Insecure: types.BoolValue(true)},
+			wantInsecure: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			transport := newTransport(context.Background(), tt.data, &diags)
+
+			if tt.wantErr {
+				if !diags.HasError() {
+					t.Error("Want error, got none")
+				}
+				return
+			}
+			if diags.HasError() {
+				t.Fatalf("Unexpected error: %v", diags)
+			}
+
+			if transport.MaxIdleConnsPerHost != tt.wantMaxIdlePerHost || transport.MaxIdleConns != tt.wantMaxIdlePerHost {
+				t.Errorf("Want %d idle connections, got %d (%d per host)", tt.wantMaxIdlePerHost, transport.MaxIdleConns, transport.MaxIdleConnsPerHost)
+			}
+			if transport.DisableKeepAlives != tt.wantKeepAlivesOff {
+				t.Errorf("Want DisableKeepAlives %t, got %t", tt.wantKeepAlivesOff, transport.DisableKeepAlives)
+			}
+			if transport.IdleConnTimeout != tt.wantIdleConnTimeout {
+				t.Errorf("Want idle connection timeout %s, got %s", tt.wantIdleConnTimeout, transport.IdleConnTimeout)
+			}
+			if insecure := transport.TLSClientConfig != nil && transport.TLSClientConfig.InsecureSkipVerify; insecure != tt.wantInsecure {
+				t.Errorf("Want InsecureSkipVerify %t, got %t", tt.wantInsecure, insecure)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright (c) German Arutyunov
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gaarutyunov/terraform-provider-slicer/internal/slicer"
+)
+
+// runRemote runs command as root on a VM and returns its stdout.
+// An error including stderr is returned if the command fails or exits
+// non-zero.
+func runRemote(ctx context.Context, client *slicer.SlicerClient, hostname, command string, args ...string) (string, error) {
+	resultChan, err := client.Exec(ctx, hostname, slicer.SlicerExecRequest{
+		Command: command,
+		Args:    args,
+		Stdout:  true,
+		Stderr:  true,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var stdout, stderr strings.Builder
+	var execErr string
+	exitCode := 0
+	for result := range resultChan {
+		stdout.WriteString(result.Stdout)
+		stderr.WriteString(result.Stderr)
+		if result.Error != "" {
+			execErr = result.Error
+		}
+		exitCode = result.ExitCode
+	}
+
+	if execErr == "" && exitCode != 0 {
+		execErr = fmt.Sprintf("%s exited with code %d", command, exitCode)
+	}
+
+	if execErr != "" {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return stdout.String(), fmt.Errorf("%s: %s", execErr, msg)
+		}
+		return stdout.String(), fmt.Errorf("%s", execErr)
+	}
+
+	return stdout.String(), nil
+}
+
+// runRemoteScript runs a POSIX shell script as root on a VM. Values are
+// passed as positional parameters ($1, $2, ...) so they never need quoting.
+func runRemoteScript(ctx context.Context, client *slicer.SlicerClient, hostname, script string, params ...string) (string, error) {
+	args := append([]string{"-c", script, "sh"}, params...)
+	return runRemote(ctx, client, hostname, "/bin/sh", args...)
+}
+
+// lookupID resolves a user or group name to its numeric ID on a VM using
+// getent. database is "passwd" for users and "group" for groups.
+func lookupID(ctx context.Context, client *slicer.SlicerClient, hostname, database, name string) (int64, error) {
+	ids, err := lookupIDs(ctx, client, hostname, database, name, 1)
+	if err != nil {
+		return 0, err
+	}
+	return ids[0], nil
+}
+
+// lookupUser resolves a user name to its UID and primary GID on a VM using
+// getent.
+func lookupUser(ctx context.Context, client *slicer.SlicerClient, hostname, name string) (uint32, uint32, error) {
+	ids, err := lookupIDs(ctx, client, hostname, "passwd", name, 2)
+	if err != nil {
+		return 0, 0, err
+	}
+	return uint32(ids[0]), uint32(ids[1]), nil
+}
+
+// lookupIDs returns the first n numeric fields of the getent entry of name,
+// starting at the third field: the ID of a user or group, followed by the
+// primary GID for users.
+func lookupIDs(ctx context.Context, client *slicer.SlicerClient, hostname, database, name string, n int) ([]int64, error) {
+	out, err := runRemote(ctx, client, hostname, "getent", database, name)
+	if err != nil {
+		return nil, fmt.Errorf("unable to look up %s %q: %w", database, name, err)
+	}
+
+	// name:password:id[:gid:...]
+	fields := strings.Split(strings.TrimSpace(out), ":")
+	if len(fields) < 2+n {
+		return nil, fmt.Errorf("%s %q not found", database, name)
+	}
+
+	ids := make([]int64, n)
+	for i := range ids {
+		id, err := strconv.ParseUint(fields[2+i], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected getent %s output for %q: %s", database, name, out)
+		}
+		ids[i] = int64(id)
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,181 @@
+// Copyright (c) German Arutyunov
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/gaarutyunov/terraform-provider-slicer/internal/slicer"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &RemoteFileDataSource{}
+
+func NewRemoteFileDataSource() datasource.DataSource {
+	return &RemoteFileDataSource{}
+}
+
+// defaultRemoteFileMaxBytes limits the size of files read when max_bytes is
+// not set, so a wrong path does not pull a large file into state.
+const defaultRemoteFileMaxBytes = 1 << 20
+
+// RemoteFileDataSource defines the data source implementation.
+type RemoteFileDataSource struct {
+	client *slicer.SlicerClient
+}
+
+// RemoteFileDataSourceModel describes the data source data model.
+type RemoteFileDataSourceModel struct {
+	Hostname      types.String `tfsdk:"hostname"`
+	Path          types.String `tfsdk:"path"`
+	MaxBytes      types.Int64  `tfsdk:"max_bytes"`
+	Base64        types.Bool   `tfsdk:"base64"`
+	Content       types.String `tfsdk:"content"`
+	ContentBase64 types.String `tfsdk:"content_base64"`
+	ContentHash   types.String `tfsdk:"content_hash"`
+	Size          types.Int64  `tfsdk:"size"`
+}
+
+func (d *RemoteFileDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_remote_file"
+}
+
+func (d *RemoteFileDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Reads a file from a Slicer VM, e.g. a join token or certificate generated on the VM. The content is stored in state and hidden in plan output.",
+
+		Attributes: map[string]schema.Attribute{
+			"hostname": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The hostname of the VM to read the file from.",
+			},
+			"path": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The absolute path of the file on the VM.",
+			},
+			"max_bytes": schema.Int64Attribute{
+				Optional:            true,
+				MarkdownDescription: "Largest file that may be read, in bytes. Larger files fail the read. Defaults to 1 MiB.",
+			},
+			"base64": schema.BoolAttribute{
+				Optional:            true,
+				MarkdownDescription: "Return the content base64 encoded in `content_base64` instead of in `content`, for files that are not UTF-8 text. Defaults to false.",
+			},
+			"content": schema.StringAttribute{
+				Computed:            true,
+				Sensitive:           true,
+				MarkdownDescription: "The content of the file. Null when `base64` is true.",
+			},
+			"content_base64": schema.StringAttribute{
+				Computed:            true,
+				Sensitive:           true,
+				MarkdownDescription: "The content of the file, base64 encoded. Only set when `base64` is true.",
+			},
+			"content_hash": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The hex encoded SHA256 of the content, e.g. for triggers.",
+			},
+			"size": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "The size of the file in bytes.",
+			},
+		},
+	}
+}
+
+func (d *RemoteFileDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*SlicerProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *SlicerProviderData, got: %T", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = providerData.Client
+}
+
+func (d *RemoteFileDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data RemoteFileDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	maxBytes := int64(defaultRemoteFileMaxBytes)
+	if !data.MaxBytes.IsNull() {
+		maxBytes = data.MaxBytes.ValueInt64()
+		if maxBytes < 1 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("max_bytes"),
+				"Invalid Max Bytes",
+				fmt.Sprintf("max_bytes must be at least 1, got: %d", maxBytes),
+			)
+			return
+		}
+	}
+
+	tflog.Debug(ctx, "Reading remote file", map[string]interface{}{
+		"hostname":  data.Hostname.ValueString(),
+		"path":      data.Path.ValueString(),
+		"max_bytes": maxBytes,
+	})
+
+	content, err := d.client.ReadFileFromVM(ctx, data.Hostname.ValueString(), data.Path.ValueString(), maxBytes)
+	if errors.Is(err, slicer.ErrFileTooLarge) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("max_bytes"),
+			"File Too Large",
+			fmt.Sprintf("%s on %s is larger than %d bytes. Raise max_bytes to read it.", data.Path.ValueString(), data.Hostname.ValueString(), maxBytes),
+		)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read file: %s", err))
+		return
+	}
+
+	if data.Base64.ValueBool() {
+		data.Content = types.StringNull()
+		data.ContentBase64 = types.StringValue(base64.StdEncoding.EncodeToString(content))
+	} else {
+		if !utf8.Valid(content) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("base64"),
+				"File Is Not UTF-8 Text",
+				fmt.Sprintf("%s on %s is not valid UTF-8. Set base64 = true to read it.", data.Path.ValueString(), data.Hostname.ValueString()),
+			)
+			return
+		}
+		data.Content = types.StringValue(string(content))
+		data.ContentBase64 = types.StringNull()
+	}
+
+	data.ContentHash = types.StringValue(fmt.Sprintf("%x", sha256.Sum256(content)))
+	data.Size = types.Int64Value(int64(len(content)))
+
+	tflog.Trace(ctx, "Read remote file", map[string]interface{}{
+		"hostname": data.Hostname.ValueString(),
+		"path":     data.Path.ValueString(),
+		"size":     len(content),
+	})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,59 @@
+// Copyright (c) German Arutyunov
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+)
+
+// Token scopes needed to manage each kind of resource.
+const (
+	scopeVMsWrite        = "vms:write"
+	scopeVMsExec         = "vms:exec"
+	scopeSecretsWrite    = "secrets:write"
+	scopeHostGroupsWrite = "hostgroups:write"
+	scopeJobsWrite       = "jobs:write"
+	scopeNetworkWrite    = "network:write"
+	scopeSSHKeysWrite    = "sshkeys:write"
+)
+
+// checkScope adds an error when the plan changes a resource of type
+// resourceType and the token is known to lack scope. Plans without changes
+// are not checked, so read-only tokens can still refresh. When the token's
+// scopes could not be determined at Configure the check is skipped.
+func checkScope(req resource.ModifyPlanRequest, scopes []string, scope, resourceType string, diags *diag.Diagnostics) {
+	if req.Plan.Raw.Equal(req.State.Raw) || hasScope(scopes, scope) {
+		return
+	}
+
+	granted := strings.Join(scopes, ", ")
+	if granted == "" {
+		granted = "none"
+	}
+
+	diags.AddError(
+		"Insufficient Token Scope",
+		fmt.Sprintf("The Slicer API token lacks %s; %s resources will fail. Scopes granted to the token: %s.", scope, resourceType, granted),
+	)
+}
+
+// hasScope reports whether scopes grant scope, either directly, through a
+// wildcard such as "vms:*", or through "*". Nil scopes grant everything.
+func hasScope(scopes []string, scope string) bool {
+	if scopes == nil {
+		return true
+	}
+
+	prefix, _, _ := strings.Cut(scope, ":")
+	for _, s := range scopes {
+		if s == scope || s == "*" || s == prefix+":*" {
+			return true
+		}
+	}
+	return false
+}
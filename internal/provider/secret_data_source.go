@@ -24,6 +24,7 @@ func NewSecretDataSource() datasource.DataSource {
 // SecretDataSource defines the data source implementation.
 type SecretDataSource struct {
 	client *slicer.SlicerClient
+	vms    *vmListCache
 }
 
 // SecretDataSourceModel describes the data source data model.
@@ -33,6 +34,7 @@ type SecretDataSourceModel struct {
 	Permissions types.String `tfsdk:"permissions"`
 	UID         types.Int64  `tfsdk:"uid"`
 	GID         types.Int64  `tfsdk:"gid"`
+	UsedBy      types.List   `tfsdk:"used_by"`
 }
 
 func (d *SecretDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
@@ -64,6 +66,11 @@ func (d *SecretDataSource) Schema(ctx context.Context, req datasource.SchemaRequ
 				Computed:            true,
 				MarkdownDescription: "Group GID of the secret file.",
 			},
+			"used_by": schema.ListAttribute{
+				Computed:            true,
+				MarkdownDescription: "Hostnames of the VMs that currently mount the secret.",
+				ElementType:         types.StringType,
+			},
 		},
 	}
 }
@@ -83,6 +90,7 @@ func (d *SecretDataSource) Configure(ctx context.Context, req datasource.Configu
 	}
 
 	d.client = providerData.Client
+	d.vms = providerData.VMs
 }
 
 func (d *SecretDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
@@ -122,6 +130,10 @@ func (d *SecretDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 	data.UID = types.Int64Value(int64(found.UID))
 	data.GID = types.Int64Value(int64(found.GID))
 
+	usedBy, diags := secretUsedBy(ctx, d.vms, found.Name)
+	resp.Diagnostics.Append(diags...)
+	data.UsedBy = usedBy
+
 	tflog.Trace(ctx, "Read secret", map[string]interface{}{
 		"name": data.Name.ValueString(),
 		"size": found.Size,
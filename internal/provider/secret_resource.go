@@ -4,17 +4,31 @@
 package provider
 
 import (
+	"bytes"
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math/big"
+	"slices"
+	"time"
 
 	"github.com/gaarutyunov/terraform-provider-slicer/internal/slicer"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
@@ -22,6 +36,8 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &SecretResource{}
 var _ resource.ResourceWithImportState = &SecretResource{}
+var _ resource.ResourceWithValidateConfig = &SecretResource{}
+var _ resource.ResourceWithModifyPlan = &SecretResource{}
 
 func NewSecretResource() resource.Resource {
 	return &SecretResource{}
@@ -29,17 +45,40 @@ func NewSecretResource() resource.Resource {
 
 // SecretResource defines the resource implementation.
 type SecretResource struct {
-	client *slicer.SlicerClient
+	client           *slicer.SlicerClient
+	permissionPolicy PermissionPolicy
+	tokenScopes      []string
+	vms              *vmListCache
 }
 
 // SecretResourceModel describes the resource data model.
 type SecretResourceModel struct {
-	ID          types.String `tfsdk:"id"`
-	Name        types.String `tfsdk:"name"`
-	Value       types.String `tfsdk:"value"`
-	Permissions types.String `tfsdk:"permissions"`
-	UID         types.Int64  `tfsdk:"uid"`
-	GID         types.Int64  `tfsdk:"gid"`
+	ID                       types.String `tfsdk:"id"`
+	Name                     types.String `tfsdk:"name"`
+	Value                    types.String `tfsdk:"value"`
+	ValueBase64              types.String `tfsdk:"value_base64"`
+	ValueWO                  types.String `tfsdk:"value_wo"`
+	ValueWOVersion           types.Int64  `tfsdk:"value_wo_version"`
+	Permissions              types.String `tfsdk:"permissions"`
+	Mode                     types.Int64  `tfsdk:"mode"`
+	AllowInsecurePermissions types.Bool   `tfsdk:"allow_insecure_permissions"`
+	UID                      types.Int64  `tfsdk:"uid"`
+	GID                      types.Int64  `tfsdk:"gid"`
+	OwnerName                types.String `tfsdk:"owner_name"`
+	GroupName                types.String `tfsdk:"group_name"`
+	Hostname                 types.String `tfsdk:"hostname"`
+	UsedBy                   types.List   `tfsdk:"used_by"`
+	ValueSHA256              types.String `tfsdk:"value_sha256"`
+
+	Generate *SecretGenerateModel `tfsdk:"generate"`
+	Timeouts *TimeoutsModel       `tfsdk:"timeouts"`
+}
+
+// SecretGenerateModel describes the generate block.
+type SecretGenerateModel struct {
+	Length  types.Int64  `tfsdk:"length"`
+	Charset types.String `tfsdk:"charset"`
+	Special types.Bool   `tfsdk:"special"`
 }
 
 func (r *SecretResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -66,28 +105,100 @@ func (r *SecretResource) Schema(ctx context.Context, req resource.SchemaRequest,
 				},
 			},
 			"value": schema.StringAttribute{
-				Required:            true,
+				Optional:            true,
 				Sensitive:           true,
-				MarkdownDescription: "The secret value.",
+				MarkdownDescription: "The secret value. Exactly one of `value`, `value_base64`, `value_wo` or a `generate` block must be set.",
+			},
+			"value_base64": schema.StringAttribute{
+				Optional:            true,
+				Sensitive:           true,
+				MarkdownDescription: "The secret value as standard base64, e.g. from `filebase64()`, for binary values such as keystores or PKCS#12 bundles. The decoded bytes are stored unchanged.",
+			},
+			"value_wo": schema.StringAttribute{
+				Optional:            true,
+				Sensitive:           true,
+				WriteOnly:           true,
+				MarkdownDescription: "The secret value as a write-only argument, which is never stored in the plan or state. Requires Terraform 1.11 or later and `value_wo_version`.",
+			},
+			"value_wo_version": schema.Int64Attribute{
+				Optional:            true,
+				MarkdownDescription: "Version of `value_wo`. Terraform cannot see changes to write-only arguments, so change this to write a new value.",
+			},
+			"value_sha256": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The hex encoded SHA256 of a generated value, e.g. to restart services when it is regenerated. Null when `value` is set.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"permissions": schema.StringAttribute{
 				Optional:            true,
 				Computed:            true,
-				MarkdownDescription: "File permissions for the secret (e.g., '0600').",
+				MarkdownDescription: "File permissions for the secret as an octal string with a leading zero (e.g., '0600'). Conflicts with `mode`. Defaults to '0600'.",
 				Default:             stringdefault.StaticString("0600"),
 			},
+			"mode": schema.Int64Attribute{
+				Optional:            true,
+				MarkdownDescription: "File permissions for the secret as a number, e.g. `parseint(\"0600\", 8)`. Sets `permissions`. Conflicts with `permissions`.",
+			},
 			"uid": schema.Int64Attribute{
 				Optional:            true,
 				Computed:            true,
-				MarkdownDescription: "Owner UID for the secret file. Defaults to 0 (root).",
+				MarkdownDescription: "Owner UID for the secret file. Conflicts with `owner_name`. Defaults to 0 (root).",
 				Default:             int64default.StaticInt64(0),
 			},
 			"gid": schema.Int64Attribute{
 				Optional:            true,
 				Computed:            true,
-				MarkdownDescription: "Group GID for the secret file. Defaults to 0 (root).",
+				MarkdownDescription: "Group GID for the secret file. Conflicts with `group_name`. Defaults to 0 (root).",
 				Default:             int64default.StaticInt64(0),
 			},
+			"owner_name": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Owner user name for the secret file, resolved to `uid` on `hostname`. Conflicts with `uid`.",
+			},
+			"group_name": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Group name for the secret file, resolved to `gid` on `hostname`. Conflicts with `gid`.",
+			},
+			"hostname": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "The VM used to resolve `owner_name` and `group_name` to numeric IDs. Required when either is set. Secrets are not tied to this VM.",
+			},
+			"allow_insecure_permissions": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Allow permissions that make the secret readable or writable by all users. Defaults to false.",
+				Default:             booldefault.StaticBool(false),
+			},
+			"used_by": schema.ListAttribute{
+				Computed:            true,
+				MarkdownDescription: "Hostnames of the VMs that currently mount the secret, e.g. for a `precondition` that blocks deleting a secret still in use.",
+				ElementType:         types.StringType,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"generate": schema.SingleNestedBlock{
+				MarkdownDescription: "Generates a random value when the secret is created instead of taking it from `value`. The value is never stored in state; read it with `ephemeral.slicer_secret_value`. Changing the block generates a new value.",
+				Attributes: map[string]schema.Attribute{
+					"length": schema.Int64Attribute{
+						Optional:            true,
+						MarkdownDescription: fmt.Sprintf("Number of characters to generate, between 1 and %d. Defaults to %d.", maxGeneratedSecretLength, defaultGeneratedSecretLength),
+					},
+					"charset": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "Characters to pick from. Defaults to upper and lower case letters and digits.",
+					},
+					"special": schema.BoolAttribute{
+						Optional:            true,
+						MarkdownDescription: fmt.Sprintf("Also pick from the special characters `%s`. Defaults to false.", generatedSecretSpecial),
+					},
+				},
+			},
+			"timeouts": timeoutsBlock(),
 		},
 	}
 }
@@ -107,6 +218,199 @@ func (r *SecretResource) Configure(ctx context.Context, req resource.ConfigureRe
 	}
 
 	r.client = providerData.Client
+	r.tokenScopes = providerData.TokenScopes
+	r.permissionPolicy = providerData.PermissionPolicy
+	r.vms = providerData.VMs
+}
+
+func (r *SecretResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data SecretResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	validatePermissions(data.Permissions, path.Root("permissions"), &resp.Diagnostics)
+	validateMode(data.Mode, path.Root("mode"), &resp.Diagnostics)
+	validateTimeouts(data.Timeouts, &resp.Diagnostics)
+	validateSecretGenerate(data.Generate, &resp.Diagnostics)
+
+	sources := 0
+	for _, set := range []bool{!data.Value.IsNull(), !data.ValueBase64.IsNull(), !data.ValueWO.IsNull(), data.Generate != nil} {
+		if set {
+			sources++
+		}
+	}
+
+	if sources == 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("value"),
+			"Missing Attribute",
+			"One of 'value', 'value_base64', 'value_wo' or a 'generate' block must be specified.",
+		)
+	}
+
+	if sources > 1 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("value"),
+			"Conflicting Attributes",
+			"Only one of 'value', 'value_base64', 'value_wo' or a 'generate' block can be specified.",
+		)
+	}
+
+	if !data.ValueBase64.IsNull() && !data.ValueBase64.IsUnknown() {
+		if _, err := base64.StdEncoding.DecodeString(data.ValueBase64.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("value_base64"),
+				"Invalid Base64 Value",
+				fmt.Sprintf("'value_base64' must be standard base64 encoded: %s", err),
+			)
+		}
+	}
+
+	if !data.ValueWO.IsNull() && data.ValueWOVersion.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("value_wo_version"),
+			"Missing Attribute",
+			"'value_wo_version' is required with 'value_wo', since changes to write-only arguments are only applied when the version changes.",
+		)
+	}
+
+	if data.ValueWO.IsNull() && !data.ValueWOVersion.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("value_wo_version"),
+			"Invalid Attribute Combination",
+			"'value_wo_version' can only be specified together with 'value_wo'.",
+		)
+	}
+
+	if !data.Permissions.IsNull() && !data.Mode.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("mode"),
+			"Conflicting Attributes",
+			"Only one of 'permissions' or 'mode' can be specified.",
+		)
+	}
+
+	if mode, ok := effectiveMode(data.Permissions, data.Mode); ok && worldAccessible(mode) && !data.AllowInsecurePermissions.ValueBool() {
+		attrPath := path.Root("permissions")
+		if !data.Mode.IsNull() {
+			attrPath = path.Root("mode")
+		}
+		resp.Diagnostics.AddAttributeError(
+			attrPath,
+			"Insecure Secret Permissions",
+			fmt.Sprintf("Permissions %s make the secret readable or writable by all users. Set 'allow_insecure_permissions = true' to allow this.", modeToPermissions(mode)),
+		)
+	}
+
+	if !data.UID.IsNull() && !data.OwnerName.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("owner_name"),
+			"Conflicting Attributes",
+			"Only one of 'uid' or 'owner_name' can be specified.",
+		)
+	}
+
+	if !data.GID.IsNull() && !data.GroupName.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("group_name"),
+			"Conflicting Attributes",
+			"Only one of 'gid' or 'group_name' can be specified.",
+		)
+	}
+
+	if (!data.OwnerName.IsNull() || !data.GroupName.IsNull()) && data.Hostname.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("hostname"),
+			"Missing Attribute",
+			"'hostname' is required to resolve 'owner_name' and 'group_name'.",
+		)
+	}
+}
+
+func (r *SecretResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	checkScope(req, r.tokenScopes, scopeSecretsWrite, "slicer_secret", &resp.Diagnostics)
+
+	// Nothing to do on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var mode types.Int64
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("mode"), &mode)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !mode.IsNull() && !mode.IsUnknown() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("permissions"), modeToPermissions(mode.ValueInt64()))...)
+	}
+
+	var plan SecretResourceModel
+	resp.Diagnostics.Append(resp.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	checkPermissionLimit(r.permissionPolicy.MaxSecret, plan.Permissions, "Secret", path.Root("permissions"), &resp.Diagnostics)
+
+	var state *SecretResourceModel
+	if !req.State.Raw.IsNull() {
+		state = &SecretResourceModel{}
+		resp.Diagnostics.Append(req.State.Get(ctx, state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	switch {
+	case plan.Generate == nil:
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("value_sha256"), types.StringNull())...)
+	case regeneratesSecret(plan.Generate, state):
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("value_sha256"), types.StringUnknown())...)
+	}
+
+	// IDs resolved from names are only known after the lookup, so keep the
+	// previous ID unless the name or the VM used to resolve it changed.
+	if !plan.OwnerName.IsNull() {
+		uid := types.Int64Unknown()
+		if state != nil && state.OwnerName.Equal(plan.OwnerName) && state.Hostname.Equal(plan.Hostname) {
+			uid = state.UID
+		}
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("uid"), uid)...)
+	}
+
+	if !plan.GroupName.IsNull() {
+		gid := types.Int64Unknown()
+		if state != nil && state.GroupName.Equal(plan.GroupName) && state.Hostname.Equal(plan.Hostname) {
+			gid = state.GID
+		}
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("gid"), gid)...)
+	}
+}
+
+// resolveOwnership looks up owner_name and group_name on the configured VM
+// and stores the numeric IDs in data. IDs already known from state are kept.
+func (r *SecretResource) resolveOwnership(ctx context.Context, data *SecretResourceModel) error {
+	if !data.OwnerName.IsNull() && data.UID.IsUnknown() {
+		uid, err := lookupID(ctx, r.client, data.Hostname.ValueString(), "passwd", data.OwnerName.ValueString())
+		if err != nil {
+			return err
+		}
+		data.UID = types.Int64Value(uid)
+	}
+
+	if !data.GroupName.IsNull() && data.GID.IsUnknown() {
+		gid, err := lookupID(ctx, r.client, data.Hostname.ValueString(), "group", data.GroupName.ValueString())
+		if err != nil {
+			return err
+		}
+		data.GID = types.Int64Value(gid)
+	}
+
+	return nil
 }
 
 func (r *SecretResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -117,13 +421,41 @@ func (r *SecretResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, data.Timeouts, timeoutCreate)
+	defer cancel()
+
+	if err := r.resolveOwnership(ctx, &data); err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to resolve secret owner: %s", err))
+		return
+	}
+
+	value, diags := r.configuredValue(ctx, req.Config, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Generate != nil {
+		var err error
+		value, err = generateSecretValue(data.Generate)
+		if err != nil {
+			resp.Diagnostics.AddError("Internal Error", fmt.Sprintf("Unable to generate secret value: %s", err))
+			return
+		}
+		data.ValueSHA256 = types.StringValue(fmt.Sprintf("%x", sha256.Sum256([]byte(value))))
+	}
+
 	createReq := slicer.CreateSecretRequest{
 		Name:        data.Name.ValueString(),
-		Data:        data.Value.ValueString(),
+		Data:        value,
 		Permissions: data.Permissions.ValueString(),
 		UID:         uint32(data.UID.ValueInt64()),
 		GID:         uint32(data.GID.ValueInt64()),
 	}
+	if !data.ValueBase64.IsNull() {
+		createReq.Data = base64.StdEncoding.EncodeToString([]byte(value))
+		createReq.Encoding = slicer.SecretEncodingBase64
+	}
 
 	tflog.Debug(ctx, "Creating secret", map[string]interface{}{
 		"name": data.Name.ValueString(),
@@ -136,6 +468,8 @@ func (r *SecretResource) Create(ctx context.Context, req resource.CreateRequest,
 	}
 
 	data.ID = data.Name
+	data.UsedBy = r.usedByAfterWrite(ctx, data.Name.ValueString(), &resp.Diagnostics)
+	resp.Diagnostics.Append(r.recordFingerprint(ctx, data.Name.ValueString(), value, resp.Private)...)
 
 	tflog.Trace(ctx, "Created secret", map[string]interface{}{
 		"name": data.Name.ValueString(),
@@ -152,49 +486,127 @@ func (r *SecretResource) Read(ctx context.Context, req resource.ReadRequest, res
 		return
 	}
 
-	// List secrets and check if ours exists
-	secrets, err := r.client.ListSecrets(ctx)
+	ctx, cancel := withTimeout(ctx, data.Timeouts, timeoutRead)
+	defer cancel()
+
+	found, err := r.findSecret(ctx, data.Name.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list secrets: %s", err))
 		return
 	}
 
-	var found *slicer.Secret
-	for _, secret := range secrets {
-		if secret.Name == data.Name.ValueString() {
-			found = &secret
-			break
-		}
-	}
-
 	if found == nil {
 		// Secret was deleted outside of Terraform
 		resp.State.RemoveResource(ctx)
 		return
 	}
 
+	// The API never returns the value, so changes to it are detected by
+	// comparing the secret's metadata with what was recorded when it was
+	// last written. Clearing the value makes the next apply write it again.
+	fingerprintBytes, diags := req.Private.GetKey(ctx, secretFingerprintPrivateKey)
+	resp.Diagnostics.Append(diags...)
+	if fingerprintBytes != nil {
+		var fingerprint secretFingerprint
+		if err := json.Unmarshal(fingerprintBytes, &fingerprint); err != nil {
+			tflog.Warn(ctx, "Ignoring unreadable secret fingerprint", map[string]interface{}{
+				"name":  data.Name.ValueString(),
+				"error": err.Error(),
+			})
+		} else if data.Generate != nil && fingerprint.drifted(found) {
+			// Generated values are not in state, so dropping the block
+			// makes the next apply generate a new value.
+			tflog.Debug(ctx, "Generated secret value changed outside of Terraform", map[string]interface{}{
+				"name": data.Name.ValueString(),
+			})
+			data.Generate = nil
+		} else if !data.ValueWOVersion.IsNull() && fingerprint.drifted(found) {
+			// Write-only values are not in state either, so dropping the
+			// version makes the next apply write the configured value again.
+			tflog.Debug(ctx, "Write-only secret value changed outside of Terraform", map[string]interface{}{
+				"name": data.Name.ValueString(),
+			})
+			data.ValueWOVersion = types.Int64Null()
+		} else if fingerprint.matches(secretStateValue(&data)) && fingerprint.drifted(found) {
+			tflog.Debug(ctx, "Secret value changed outside of Terraform", map[string]interface{}{
+				"name": data.Name.ValueString(),
+			})
+			data.Value = types.StringNull()
+			data.ValueBase64 = types.StringNull()
+		}
+	}
+
 	// Update state with current values (note: value is not returned by API)
-	data.Permissions = types.StringValue(found.Permissions)
+	data.Permissions = types.StringValue(normalizePermissions(found.Permissions))
 	data.UID = types.Int64Value(int64(found.UID))
 	data.GID = types.Int64Value(int64(found.GID))
 
+	usedBy, diags := secretUsedBy(ctx, r.vms, found.Name)
+	resp.Diagnostics.Append(diags...)
+	data.UsedBy = usedBy
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *SecretResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var data SecretResourceModel
+	var data, state SecretResourceModel
 
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := withTimeout(ctx, data.Timeouts, timeoutUpdate)
+	defer cancel()
+
+	if err := r.resolveOwnership(ctx, &data); err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to resolve secret owner: %s", err))
+		return
+	}
+
+	value, diags := r.configuredValue(ctx, req.Config, &data)
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	switch {
+	case regeneratesSecret(data.Generate, &state):
+		var err error
+		value, err = generateSecretValue(data.Generate)
+		if err != nil {
+			resp.Diagnostics.AddError("Internal Error", fmt.Sprintf("Unable to generate secret value: %s", err))
+			return
+		}
+		data.ValueSHA256 = types.StringValue(fmt.Sprintf("%x", sha256.Sum256([]byte(value))))
+	case data.Generate != nil:
+		// Updates replace the data, so resend the value generated earlier
+		var err error
+		value, err = r.client.GetSecretValue(ctx, data.Name.ValueString())
+		if errors.Is(err, slicer.ErrNotSupported) {
+			resp.Diagnostics.AddError(
+				"Secret Values Not Supported",
+				"The Slicer API does not return secret values, so the permissions and owner of a generated secret cannot change without generating a new value. Change the generate block to generate a new value.",
+			)
+			return
+		}
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read generated secret value: %s", err))
+			return
+		}
+	}
+
 	updateReq := slicer.UpdateSecretRequest{
-		Data:        data.Value.ValueString(),
+		Data:        value,
 		Permissions: data.Permissions.ValueString(),
 		UID:         uint32(data.UID.ValueInt64()),
 		GID:         uint32(data.GID.ValueInt64()),
 	}
+	if !data.ValueBase64.IsNull() {
+		updateReq.Data = base64.StdEncoding.EncodeToString([]byte(value))
+		updateReq.Encoding = slicer.SecretEncodingBase64
+	}
 
 	tflog.Debug(ctx, "Updating secret", map[string]interface{}{
 		"name": data.Name.ValueString(),
@@ -206,6 +618,9 @@ func (r *SecretResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
+	data.UsedBy = r.usedByAfterWrite(ctx, data.Name.ValueString(), &resp.Diagnostics)
+	resp.Diagnostics.Append(r.recordFingerprint(ctx, data.Name.ValueString(), value, resp.Private)...)
+
 	tflog.Trace(ctx, "Updated secret", map[string]interface{}{
 		"name": data.Name.ValueString(),
 	})
@@ -221,6 +636,9 @@ func (r *SecretResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		return
 	}
 
+	ctx, cancel := withTimeout(ctx, data.Timeouts, timeoutDelete)
+	defer cancel()
+
 	tflog.Debug(ctx, "Deleting secret", map[string]interface{}{
 		"name": data.Name.ValueString(),
 	})
@@ -240,3 +658,233 @@ func (r *SecretResource) ImportState(ctx context.Context, req resource.ImportSta
 	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp)
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
 }
+
+// configuredValue returns the secret value from value, value_base64 or
+// value_wo, which is only available in the configuration.
+func (r *SecretResource) configuredValue(ctx context.Context, config tfsdk.Config, data *SecretResourceModel) (string, diag.Diagnostics) {
+	if data.ValueWOVersion.IsNull() {
+		return secretStateValue(data), nil
+	}
+
+	var valueWO types.String
+	diags := config.GetAttribute(ctx, path.Root("value_wo"), &valueWO)
+	return valueWO.ValueString(), diags
+}
+
+// secretStateValue returns the secret value held in state, decoding
+// value_base64.
+// Values that fail to decode were rejected by ValidateConfig.
+func secretStateValue(data *SecretResourceModel) string {
+	if data.ValueBase64.IsNull() {
+		return data.Value.ValueString()
+	}
+
+	decoded, _ := base64.StdEncoding.DecodeString(data.ValueBase64.ValueString())
+	return string(decoded)
+}
+
+// usedByAfterWrite returns used_by for a secret that was just written. The
+// secret exists at this point, so failing to list VMs only warns and leaves
+// the list empty until the next refresh.
+func (r *SecretResource) usedByAfterWrite(ctx context.Context, name string, respDiags *diag.Diagnostics) types.List {
+	usedBy, diags := secretUsedBy(ctx, r.vms, name)
+	if diags.HasError() {
+		respDiags.AddWarning(
+			"Unable to Determine Secret Usage",
+			fmt.Sprintf("used_by will be refreshed on the next plan: %s", diags.Errors()[0].Detail()),
+		)
+		return types.ListValueMust(types.StringType, []attr.Value{})
+	}
+	return usedBy
+}
+
+// secretUsedBy returns the sorted hostnames of the VMs that mount the secret.
+func secretUsedBy(ctx context.Context, cache *vmListCache, name string) (types.List, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	vms, err := cache.list(ctx)
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to list VMs: %s", err))
+		return types.ListNull(types.StringType), diags
+	}
+
+	hostnames := []string{}
+	for _, vm := range vms {
+		if slices.Contains(vm.Secrets, name) {
+			hostnames = append(hostnames, vm.Hostname)
+		}
+	}
+	slices.Sort(hostnames)
+
+	return types.ListValueFrom(ctx, types.StringType, hostnames)
+}
+
+// findSecret returns the secret with the given name, or nil if it does not exist.
+func (r *SecretResource) findSecret(ctx context.Context, name string) (*slicer.Secret, error) {
+	secrets, err := r.client.ListSecrets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, secret := range secrets {
+		if secret.Name == name {
+			return &secret, nil
+		}
+	}
+	return nil, nil
+}
+
+// secretFingerprintPrivateKey holds the secretFingerprint of the value last
+// written by Terraform.
+const secretFingerprintPrivateKey = "secret_fingerprint"
+
+// secretFingerprint describes a secret value as last written by Terraform
+// without storing the value itself.
+type secretFingerprint struct {
+	Salt       []byte     `json:"salt"`
+	Hash       []byte     `json:"hash"`
+	Size       int64      `json:"size"`
+	ModifiedAt *time.Time `json:"modified_at,omitempty"`
+}
+
+// hashSecretValue returns the salted SHA-256 hash of a secret value.
+func hashSecretValue(salt []byte, value string) []byte {
+	h := sha256.New()
+	h.Write(salt)
+	h.Write([]byte(value))
+	return h.Sum(nil)
+}
+
+// matches reports whether the fingerprint was taken of value, i.e. whether it
+// still describes the value in state.
+func (f secretFingerprint) matches(value string) bool {
+	return bytes.Equal(f.Hash, hashSecretValue(f.Salt, value))
+}
+
+// drifted reports whether the secret was rewritten since the fingerprint was
+// taken. Older APIs do not report modified_at, so only the size is compared.
+func (f secretFingerprint) drifted(secret *slicer.Secret) bool {
+	if secret.Size != f.Size {
+		return true
+	}
+	return f.ModifiedAt != nil && secret.ModifiedAt != nil && !secret.ModifiedAt.Equal(*f.ModifiedAt)
+}
+
+// privateSetter is implemented by the private state of apply responses.
+type privateSetter interface {
+	SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics
+}
+
+// recordFingerprint stores the fingerprint of a secret value that was just
+// written. The modification time is read back from the API; if that fails,
+// drift is detected by size only until the next write.
+func (r *SecretResource) recordFingerprint(ctx context.Context, name, value string, private privateSetter) diag.Diagnostics {
+	fingerprint := secretFingerprint{
+		Salt: make([]byte, 16),
+		Size: int64(len(value)),
+	}
+	if _, err := rand.Read(fingerprint.Salt); err != nil {
+		var diags diag.Diagnostics
+		diags.AddError("Internal Error", fmt.Sprintf("Unable to generate salt: %s", err))
+		return diags
+	}
+	fingerprint.Hash = hashSecretValue(fingerprint.Salt, value)
+
+	secret, err := r.findSecret(ctx, name)
+	if err != nil {
+		tflog.Warn(ctx, "Unable to read secret metadata after write", map[string]interface{}{
+			"name":  name,
+			"error": err.Error(),
+		})
+	} else if secret != nil {
+		fingerprint.ModifiedAt = secret.ModifiedAt
+	}
+
+	encoded, err := json.Marshal(fingerprint)
+	if err != nil {
+		var diags diag.Diagnostics
+		diags.AddError("Internal Error", fmt.Sprintf("Unable to encode secret fingerprint: %s", err))
+		return diags
+	}
+	return private.SetKey(ctx, secretFingerprintPrivateKey, encoded)
+}
+
+const (
+	defaultGeneratedSecretLength = 32
+	maxGeneratedSecretLength     = 4096
+
+	generatedSecretCharset = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+	generatedSecretSpecial = "!#$%&*()-_=+[]{}<>:?"
+)
+
+// validateSecretGenerate reports generate blocks that cannot produce a value.
+func validateSecretGenerate(generate *SecretGenerateModel, diags *diag.Diagnostics) {
+	if generate == nil {
+		return
+	}
+
+	if !generate.Length.IsNull() && !generate.Length.IsUnknown() {
+		if length := generate.Length.ValueInt64(); length < 1 || length > maxGeneratedSecretLength {
+			diags.AddAttributeError(
+				path.Root("generate").AtName("length"),
+				"Invalid Length",
+				fmt.Sprintf("length must be between 1 and %d, got: %d", maxGeneratedSecretLength, length),
+			)
+		}
+	}
+
+	if !generate.Charset.IsNull() && !generate.Charset.IsUnknown() && generate.Charset.ValueString() == "" && !generate.Special.ValueBool() {
+		diags.AddAttributeError(
+			path.Root("generate").AtName("charset"),
+			"Invalid Charset",
+			"charset must not be empty unless special is true.",
+		)
+	}
+}
+
+// regeneratesSecret reports whether applying generate to the secret in state
+// generates a new value, i.e. the secret is new, was not generated before or
+// its generate block changed.
+func regeneratesSecret(generate *SecretGenerateModel, state *SecretResourceModel) bool {
+	if generate == nil {
+		return false
+	}
+	if state == nil || state.Generate == nil {
+		return true
+	}
+	return !generate.Length.Equal(state.Generate.Length) ||
+		!generate.Charset.Equal(state.Generate.Charset) ||
+		!generate.Special.Equal(state.Generate.Special)
+}
+
+// generateSecretValue returns a random value drawn uniformly from the
+// characters allowed by generate.
+func generateSecretValue(generate *SecretGenerateModel) (string, error) {
+	length := int64(defaultGeneratedSecretLength)
+	if !generate.Length.IsNull() {
+		length = generate.Length.ValueInt64()
+	}
+
+	charset := generatedSecretCharset
+	if !generate.Charset.IsNull() {
+		charset = generate.Charset.ValueString()
+	}
+	if generate.Special.ValueBool() {
+		charset += generatedSecretSpecial
+	}
+
+	chars := []rune(charset)
+	if len(chars) == 0 {
+		return "", errors.New("no characters to pick from")
+	}
+
+	value := make([]rune, length)
+	for i := range value {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(chars))))
+		if err != nil {
+			return "", err
+		}
+		value[i] = chars[n.Int64()]
+	}
+	return string(value), nil
+}
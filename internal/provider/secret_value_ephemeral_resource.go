@@ -0,0 +1,136 @@
+// Copyright (c) German Arutyunov
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gaarutyunov/terraform-provider-slicer/internal/slicer"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ ephemeral.EphemeralResource = &SecretValueEphemeralResource{}
+var _ ephemeral.EphemeralResourceWithConfigure = &SecretValueEphemeralResource{}
+
+func NewSecretValueEphemeralResource() ephemeral.EphemeralResource {
+	return &SecretValueEphemeralResource{}
+}
+
+// SecretValueEphemeralResource defines the ephemeral resource implementation.
+type SecretValueEphemeralResource struct {
+	client     *slicer.SlicerClient
+	serverInfo *slicer.SlicerServerInfo
+}
+
+// SecretValueEphemeralResourceModel describes the ephemeral resource data model.
+type SecretValueEphemeralResourceModel struct {
+	Name  types.String `tfsdk:"name"`
+	Value types.String `tfsdk:"value"`
+}
+
+func (e *SecretValueEphemeralResource) Metadata(ctx context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_secret_value"
+}
+
+func (e *SecretValueEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Fetches the value of a Slicer secret at apply time without storing it in state or plan files. Requires Terraform 1.10 or later.",
+
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The name of the secret to fetch.",
+			},
+			"value": schema.StringAttribute{
+				Computed:            true,
+				Sensitive:           true,
+				MarkdownDescription: "The secret value.",
+			},
+		},
+	}
+}
+
+func (e *SecretValueEphemeralResource) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*SlicerProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Ephemeral Resource Configure Type",
+			fmt.Sprintf("Expected *SlicerProviderData, got: %T", req.ProviderData),
+		)
+		return
+	}
+
+	e.client = providerData.Client
+	e.serverInfo = providerData.ServerInfo
+}
+
+func (e *SecretValueEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data SecretValueEphemeralResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	checkCapability(e.serverInfo, capabilitySecretValues, path.Root("name"), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Fetching secret value", map[string]interface{}{
+		"name": data.Name.ValueString(),
+	})
+
+	value, err := e.client.GetSecretValue(ctx, data.Name.ValueString())
+	if errors.Is(err, slicer.ErrNotSupported) {
+		e.addNotSupportedError(ctx, data.Name.ValueString(), &resp.Diagnostics)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to fetch secret value: %s", err))
+		return
+	}
+
+	data.Value = types.StringValue(value)
+
+	tflog.Trace(ctx, "Fetched secret value", map[string]interface{}{
+		"name": data.Name.ValueString(),
+	})
+
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
+
+// addNotSupportedError tells a missing secret apart from an API that cannot
+// return secret values, since both answer 404.
+func (e *SecretValueEphemeralResource) addNotSupportedError(ctx context.Context, name string, diags *diag.Diagnostics) {
+	secrets, err := e.client.ListSecrets(ctx)
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to list secrets: %s", err))
+		return
+	}
+
+	for _, secret := range secrets {
+		if secret.Name == name {
+			diags.AddError(
+				"Secret Values Not Supported",
+				"The Slicer API does not support reading secret values.",
+			)
+			return
+		}
+	}
+
+	diags.AddError("Not Found", fmt.Sprintf("Secret with name '%s' not found", name))
+}
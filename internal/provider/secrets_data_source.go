@@ -0,0 +1,191 @@
+// Copyright (c) German Arutyunov
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gaarutyunov/terraform-provider-slicer/internal/slicer"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &SecretsDataSource{}
+
+func NewSecretsDataSource() datasource.DataSource {
+	return &SecretsDataSource{}
+}
+
+// SecretsDataSource defines the data source implementation.
+type SecretsDataSource struct {
+	client *slicer.SlicerClient
+}
+
+// SecretsDataSourceModel describes the data source data model.
+type SecretsDataSourceModel struct {
+	NamePrefix types.String `tfsdk:"name_prefix"`
+	Names      types.List   `tfsdk:"names"`
+	Secrets    types.List   `tfsdk:"secrets"`
+}
+
+// SecretModel describes a secret in the list.
+type SecretModel struct {
+	Name        types.String `tfsdk:"name"`
+	Size        types.Int64  `tfsdk:"size"`
+	Permissions types.String `tfsdk:"permissions"`
+	UID         types.Int64  `tfsdk:"uid"`
+	GID         types.Int64  `tfsdk:"gid"`
+	ModifiedAt  types.String `tfsdk:"modified_at"`
+}
+
+func (d *SecretsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_secrets"
+}
+
+func (d *SecretsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists Slicer secrets and their metadata, e.g. to mount every secret of an application into its VMs. Secret values are not returned.",
+
+		Attributes: map[string]schema.Attribute{
+			"name_prefix": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only list secrets whose name starts with this prefix, e.g. `app-`.",
+			},
+			"names": schema.ListAttribute{
+				Computed:            true,
+				MarkdownDescription: "Sorted list of secret names.",
+				ElementType:         types.StringType,
+			},
+			"secrets": schema.ListNestedAttribute{
+				Computed:            true,
+				MarkdownDescription: "Detailed list of secrets, sorted by name.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "The name of the secret.",
+						},
+						"size": schema.Int64Attribute{
+							Computed:            true,
+							MarkdownDescription: "The size of the secret data in bytes.",
+						},
+						"permissions": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "File permissions of the secret.",
+						},
+						"uid": schema.Int64Attribute{
+							Computed:            true,
+							MarkdownDescription: "Owner UID of the secret file.",
+						},
+						"gid": schema.Int64Attribute{
+							Computed:            true,
+							MarkdownDescription: "Group GID of the secret file.",
+						},
+						"modified_at": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "The time the secret was last modified (RFC3339). Null if not reported by the API.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *SecretsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*SlicerProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *SlicerProviderData, got: %T", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = providerData.Client
+}
+
+func (d *SecretsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data SecretsDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	prefix := data.NamePrefix.ValueString()
+
+	tflog.Debug(ctx, "Listing secrets", map[string]interface{}{
+		"name_prefix": prefix,
+	})
+
+	secrets, err := d.client.ListSecrets(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list secrets: %s", err))
+		return
+	}
+
+	// Sorted, so the list does not change with the order the API returns
+	slices.SortFunc(secrets, func(a, b slicer.Secret) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	names := []string{}
+	secretModels := []SecretModel{}
+	for _, secret := range secrets {
+		if !strings.HasPrefix(secret.Name, prefix) {
+			continue
+		}
+
+		secretModel := SecretModel{
+			Name:        types.StringValue(secret.Name),
+			Size:        types.Int64Value(secret.Size),
+			Permissions: types.StringValue(secret.Permissions),
+			UID:         types.Int64Value(int64(secret.UID)),
+			GID:         types.Int64Value(int64(secret.GID)),
+			ModifiedAt:  types.StringNull(),
+		}
+		if secret.ModifiedAt != nil {
+			secretModel.ModifiedAt = types.StringValue(secret.ModifiedAt.Format(time.RFC3339))
+		}
+
+		names = append(names, secret.Name)
+		secretModels = append(secretModels, secretModel)
+	}
+
+	namesValue, diags := types.ListValueFrom(ctx, types.StringType, names)
+	resp.Diagnostics.Append(diags...)
+	data.Names = namesValue
+
+	secretsValue, diags := types.ListValueFrom(ctx, types.ObjectType{
+		AttrTypes: map[string]attr.Type{
+			"name":        types.StringType,
+			"size":        types.Int64Type,
+			"permissions": types.StringType,
+			"uid":         types.Int64Type,
+			"gid":         types.Int64Type,
+			"modified_at": types.StringType,
+		},
+	}, secretModels)
+	resp.Diagnostics.Append(diags...)
+	data.Secrets = secretsValue
+
+	tflog.Trace(ctx, "Listed secrets", map[string]interface{}{
+		"count": len(names),
+	})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
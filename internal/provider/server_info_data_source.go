@@ -0,0 +1,142 @@
+// Copyright (c) German Arutyunov
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gaarutyunov/terraform-provider-slicer/internal/slicer"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &ServerInfoDataSource{}
+
+func NewServerInfoDataSource() datasource.DataSource {
+	return &ServerInfoDataSource{}
+}
+
+// ServerInfoDataSource defines the data source implementation.
+type ServerInfoDataSource struct {
+	client *slicer.SlicerClient
+}
+
+// ServerInfoDataSourceModel describes the data source data model.
+type ServerInfoDataSourceModel struct {
+	Version       types.String `tfsdk:"version"`
+	GitCommit     types.String `tfsdk:"git_commit"`
+	Features      types.List   `tfsdk:"features"`
+	AgentVersions types.Map    `tfsdk:"agent_versions"`
+}
+
+func (d *ServerInfoDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_server_info"
+}
+
+func (d *ServerInfoDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Fetches the Slicer control plane version and the optional API features it supports.",
+
+		Attributes: map[string]schema.Attribute{
+			"version": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Version of the Slicer control plane. Null if the API does not report it.",
+			},
+			"git_commit": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Commit the Slicer control plane was built from.",
+			},
+			"features": schema.ListAttribute{
+				Computed:            true,
+				MarkdownDescription: "Optional API features supported by the control plane (e.g., `hostgroup_management`).",
+				ElementType:         types.StringType,
+			},
+			"agent_versions": schema.MapAttribute{
+				Computed:            true,
+				MarkdownDescription: "Guest agent version of each VM, keyed by hostname.",
+				ElementType:         types.StringType,
+			},
+		},
+	}
+}
+
+func (d *ServerInfoDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*SlicerProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *SlicerProviderData, got: %T", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = providerData.Client
+}
+
+func (d *ServerInfoDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data ServerInfoDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Reading server info")
+
+	info, err := d.client.GetServerInfo(ctx)
+	if errors.Is(err, slicer.ErrNotSupported) {
+		// Older control planes have no info endpoint; report no optional features
+		resp.Diagnostics.AddWarning(
+			"Server Info Not Available",
+			"The Slicer API does not expose server info. The control plane is assumed to support no optional features.",
+		)
+		info = &slicer.SlicerServerInfo{}
+	} else if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read server info: %s", err))
+		return
+	}
+
+	if info.Version != "" {
+		data.Version = types.StringValue(info.Version)
+	} else {
+		data.Version = types.StringNull()
+	}
+	if info.GitCommit != "" {
+		data.GitCommit = types.StringValue(info.GitCommit)
+	} else {
+		data.GitCommit = types.StringNull()
+	}
+
+	features := info.Features
+	if features == nil {
+		features = []string{}
+	}
+	featuresValue, diags := types.ListValueFrom(ctx, types.StringType, features)
+	resp.Diagnostics.Append(diags...)
+	data.Features = featuresValue
+
+	agentVersions := info.AgentVersions
+	if agentVersions == nil {
+		agentVersions = map[string]string{}
+	}
+	agentVersionsValue, diags := types.MapValueFrom(ctx, types.StringType, agentVersions)
+	resp.Diagnostics.Append(diags...)
+	data.AgentVersions = agentVersionsValue
+
+	tflog.Trace(ctx, "Read server info", map[string]interface{}{
+		"version":  info.Version,
+		"features": len(features),
+	})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
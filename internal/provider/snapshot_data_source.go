@@ -0,0 +1,146 @@
+// Copyright (c) German Arutyunov
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gaarutyunov/terraform-provider-slicer/internal/slicer"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &SnapshotDataSource{}
+
+func NewSnapshotDataSource() datasource.DataSource {
+	return &SnapshotDataSource{}
+}
+
+// SnapshotDataSource defines the data source implementation.
+type SnapshotDataSource struct {
+	client *slicer.SlicerClient
+}
+
+// SnapshotDataSourceModel describes the data source data model.
+type SnapshotDataSourceModel struct {
+	Name      types.String `tfsdk:"name"`
+	Hostname  types.String `tfsdk:"hostname"`
+	SizeBytes types.Int64  `tfsdk:"size_bytes"`
+	CreatedAt types.String `tfsdk:"created_at"`
+}
+
+func (d *SnapshotDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_snapshot"
+}
+
+func (d *SnapshotDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Looks up a VM disk snapshot by name, or the most recent snapshot of a VM.",
+
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "The name of the snapshot. If not set, the most recent snapshot of `hostname` is returned.",
+			},
+			"hostname": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "The VM the snapshot was taken of. Required when `name` is not set.",
+			},
+			"size_bytes": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "Disk space used by the snapshot, in bytes.",
+			},
+			"created_at": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The time the snapshot was taken (RFC3339).",
+			},
+		},
+	}
+}
+
+func (d *SnapshotDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*SlicerProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *SlicerProviderData, got: %T", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = providerData.Client
+}
+
+func (d *SnapshotDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data SnapshotDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Name.IsNull() && data.Hostname.IsNull() {
+		resp.Diagnostics.AddError("Missing Attribute", "One of 'name' or 'hostname' must be specified.")
+		return
+	}
+
+	tflog.Debug(ctx, "Reading snapshot", map[string]interface{}{
+		"name":     data.Name.ValueString(),
+		"hostname": data.Hostname.ValueString(),
+	})
+
+	snapshots, err := d.client.ListSnapshots(ctx)
+	if errors.Is(err, slicer.ErrNotSupported) {
+		resp.Diagnostics.AddError(
+			"Snapshots Not Supported",
+			"The Slicer API does not support VM snapshots.",
+		)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list snapshots: %s", err))
+		return
+	}
+
+	var found *slicer.SlicerDiskSnapshot
+	for _, snapshot := range snapshots {
+		if !data.Name.IsNull() && snapshot.Name != data.Name.ValueString() {
+			continue
+		}
+		if !data.Hostname.IsNull() && snapshot.Hostname != data.Hostname.ValueString() {
+			continue
+		}
+		if found == nil || snapshot.CreatedAt.After(found.CreatedAt) {
+			found = &snapshot
+		}
+	}
+
+	if found == nil {
+		resp.Diagnostics.AddError("Not Found", "No snapshot matches the given name and hostname")
+		return
+	}
+
+	data.Name = types.StringValue(found.Name)
+	data.Hostname = types.StringValue(found.Hostname)
+	data.SizeBytes = types.Int64Value(found.SizeBytes)
+	data.CreatedAt = types.StringValue(found.CreatedAt.Format(time.RFC3339))
+
+	tflog.Trace(ctx, "Read snapshot", map[string]interface{}{
+		"name": found.Name,
+	})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,240 @@
+// Copyright (c) German Arutyunov
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gaarutyunov/terraform-provider-slicer/internal/slicer"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &SnapshotResource{}
+var _ resource.ResourceWithImportState = &SnapshotResource{}
+var _ resource.ResourceWithModifyPlan = &SnapshotResource{}
+
+func NewSnapshotResource() resource.Resource {
+	return &SnapshotResource{}
+}
+
+// SnapshotResource defines the resource implementation.
+type SnapshotResource struct {
+	client      *slicer.SlicerClient
+	serverInfo  *slicer.SlicerServerInfo
+	tokenScopes []string
+}
+
+// SnapshotResourceModel describes the resource data model.
+type SnapshotResourceModel struct {
+	ID        types.String `tfsdk:"id"`
+	Name      types.String `tfsdk:"name"`
+	Hostname  types.String `tfsdk:"hostname"`
+	SizeBytes types.Int64  `tfsdk:"size_bytes"`
+	CreatedAt types.String `tfsdk:"created_at"`
+}
+
+func (r *SnapshotResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_snapshot"
+}
+
+func (r *SnapshotResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Takes a snapshot of a persistent Slicer VM's disk, e.g. before a risky deploy. Destroying the resource deletes the snapshot.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The unique identifier of the snapshot (name).",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The name of the snapshot.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"hostname": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The persistent VM to snapshot.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"size_bytes": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "Disk space used by the snapshot, in bytes.",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"created_at": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The time the snapshot was taken (RFC3339).",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *SnapshotResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*SlicerProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *SlicerProviderData, got: %T", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = providerData.Client
+	r.tokenScopes = providerData.TokenScopes
+	r.serverInfo = providerData.ServerInfo
+}
+
+func (r *SnapshotResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	checkScope(req, r.tokenScopes, scopeVMsWrite, "slicer_snapshot", &resp.Diagnostics)
+
+	// Only creation depends on the server version
+	if req.Plan.Raw.IsNull() || !req.State.Raw.IsNull() {
+		return
+	}
+
+	checkCapability(r.serverInfo, capabilitySnapshots, path.Root("hostname"), &resp.Diagnostics)
+}
+
+func (r *SnapshotResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data SnapshotResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Creating snapshot", map[string]interface{}{
+		"name":     data.Name.ValueString(),
+		"hostname": data.Hostname.ValueString(),
+	})
+
+	snapshot, err := r.client.CreateSnapshot(ctx, slicer.SlicerCreateDiskSnapshotRequest{
+		Name:     data.Name.ValueString(),
+		Hostname: data.Hostname.ValueString(),
+	})
+	if errors.Is(err, slicer.ErrNotSupported) {
+		resp.Diagnostics.AddError(
+			"Snapshots Not Supported",
+			"The Slicer API does not support VM snapshots.",
+		)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create snapshot: %s", err))
+		return
+	}
+
+	data.ID = data.Name
+	setSnapshot(&data, snapshot)
+
+	tflog.Trace(ctx, "Created snapshot", map[string]interface{}{
+		"name":       snapshot.Name,
+		"size_bytes": snapshot.SizeBytes,
+	})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SnapshotResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data SnapshotResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	snapshots, err := r.client.ListSnapshots(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list snapshots: %s", err))
+		return
+	}
+
+	for _, snapshot := range snapshots {
+		if snapshot.Name == data.Name.ValueString() {
+			setSnapshot(&data, &snapshot)
+			resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+			return
+		}
+	}
+
+	// Snapshot was deleted outside of Terraform
+	resp.State.RemoveResource(ctx)
+}
+
+func (r *SnapshotResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data SnapshotResourceModel
+
+	// All configurable attributes require replacement, so there is nothing to
+	// send to the API.
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SnapshotResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data SnapshotResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Deleting snapshot", map[string]interface{}{
+		"name": data.Name.ValueString(),
+	})
+
+	err := r.client.DeleteSnapshot(ctx, data.Name.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete snapshot: %s", err))
+		return
+	}
+
+	tflog.Trace(ctx, "Deleted snapshot", map[string]interface{}{
+		"name": data.Name.ValueString(),
+	})
+}
+
+func (r *SnapshotResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+}
+
+// setSnapshot stores a snapshot returned by the API in the model.
+func setSnapshot(data *SnapshotResourceModel, snapshot *slicer.SlicerDiskSnapshot) {
+	if snapshot.Hostname != "" {
+		data.Hostname = types.StringValue(snapshot.Hostname)
+	}
+	data.SizeBytes = types.Int64Value(snapshot.SizeBytes)
+	data.CreatedAt = types.StringValue(snapshot.CreatedAt.Format(time.RFC3339))
+}
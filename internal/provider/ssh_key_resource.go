@@ -0,0 +1,282 @@
+// Copyright (c) German Arutyunov
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gaarutyunov/terraform-provider-slicer/internal/slicer"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &SSHKeyResource{}
+var _ resource.ResourceWithImportState = &SSHKeyResource{}
+var _ resource.ResourceWithModifyPlan = &SSHKeyResource{}
+var _ resource.ResourceWithValidateConfig = &SSHKeyResource{}
+
+func NewSSHKeyResource() resource.Resource {
+	return &SSHKeyResource{}
+}
+
+// SSHKeyResource defines the resource implementation.
+type SSHKeyResource struct {
+	client      *slicer.SlicerClient
+	serverInfo  *slicer.SlicerServerInfo
+	tokenScopes []string
+}
+
+// SSHKeyResourceModel describes the resource data model.
+type SSHKeyResourceModel struct {
+	ID          types.String `tfsdk:"id"`
+	Name        types.String `tfsdk:"name"`
+	PublicKey   types.String `tfsdk:"public_key"`
+	Fingerprint types.String `tfsdk:"fingerprint"`
+	CreatedAt   types.String `tfsdk:"created_at"`
+}
+
+func (r *SSHKeyResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_ssh_key"
+}
+
+func (r *SSHKeyResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Stores an SSH public key in Slicer under a name, so VMs can reference it in `ssh_key_names` instead of repeating the key. Deleting the key does not remove it from VMs it was injected into.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The unique identifier of the SSH key (name).",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The name of the SSH key.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"public_key": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The public key in `authorized_keys` format, e.g. `ssh-ed25519 AAAA... alice@example.com`. Changing it replaces the stored key.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"fingerprint": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The SHA256 fingerprint of the key. Null if not reported by the API.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"created_at": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The time the key was added (RFC3339).",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *SSHKeyResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*SlicerProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *SlicerProviderData, got: %T", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = providerData.Client
+	r.tokenScopes = providerData.TokenScopes
+	r.serverInfo = providerData.ServerInfo
+}
+
+func (r *SSHKeyResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data SSHKeyResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.PublicKey.IsNull() || data.PublicKey.IsUnknown() {
+		return
+	}
+
+	if !validSSHPublicKey(data.PublicKey.ValueString()) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("public_key"),
+			"Invalid SSH Public Key",
+			"public_key must be a single key in authorized_keys format, e.g. 'ssh-ed25519 AAAA... alice@example.com'.",
+		)
+	}
+}
+
+func (r *SSHKeyResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	checkScope(req, r.tokenScopes, scopeSSHKeysWrite, "slicer_ssh_key", &resp.Diagnostics)
+
+	// Only creation depends on the server version
+	if req.Plan.Raw.IsNull() || !req.State.Raw.IsNull() {
+		return
+	}
+
+	checkCapability(r.serverInfo, capabilitySSHKeys, path.Root("name"), &resp.Diagnostics)
+}
+
+func (r *SSHKeyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data SSHKeyResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Creating SSH key", map[string]interface{}{
+		"name": data.Name.ValueString(),
+	})
+
+	key, err := r.client.CreateSSHKey(ctx, slicer.SlicerCreateSSHKeyRequest{
+		Name:      data.Name.ValueString(),
+		PublicKey: strings.TrimSpace(data.PublicKey.ValueString()),
+	})
+	if errors.Is(err, slicer.ErrNotSupported) {
+		resp.Diagnostics.AddError(
+			"SSH Keys Not Supported",
+			"The Slicer API does not support storing SSH keys. Set the keys in ssh_keys on slicer_vm instead.",
+		)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create SSH key: %s", err))
+		return
+	}
+
+	data.ID = data.Name
+	setSSHKey(&data, key)
+
+	tflog.Trace(ctx, "Created SSH key", map[string]interface{}{
+		"name":        key.Name,
+		"fingerprint": key.Fingerprint,
+	})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SSHKeyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data SSHKeyResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	keys, err := r.client.ListSSHKeys(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list SSH keys: %s", err))
+		return
+	}
+
+	for _, key := range keys {
+		if key.Name != data.Name.ValueString() {
+			continue
+		}
+
+		// The API may drop surrounding whitespace, which is not a change
+		if strings.TrimSpace(data.PublicKey.ValueString()) != key.PublicKey {
+			data.PublicKey = types.StringValue(key.PublicKey)
+		}
+		setSSHKey(&data, &key)
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		return
+	}
+
+	// Key was deleted outside of Terraform
+	resp.State.RemoveResource(ctx)
+}
+
+func (r *SSHKeyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data SSHKeyResourceModel
+
+	// All configurable attributes require replacement
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SSHKeyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data SSHKeyResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Deleting SSH key", map[string]interface{}{
+		"name": data.Name.ValueString(),
+	})
+
+	err := r.client.DeleteSSHKey(ctx, data.Name.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete SSH key: %s", err))
+		return
+	}
+
+	tflog.Trace(ctx, "Deleted SSH key", map[string]interface{}{
+		"name": data.Name.ValueString(),
+	})
+}
+
+func (r *SSHKeyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+}
+
+// setSSHKey stores the computed attributes of an SSH key returned by the API
+// in the model.
+func setSSHKey(data *SSHKeyResourceModel, key *slicer.SlicerSSHKey) {
+	data.Fingerprint = optionalString(key.Fingerprint)
+	data.CreatedAt = types.StringValue(key.CreatedAt.Format(time.RFC3339))
+}
+
+// validSSHPublicKey reports whether key looks like a single authorized_keys
+// entry: a key type followed by base64 key data and an optional comment.
+func validSSHPublicKey(key string) bool {
+	key = strings.TrimSpace(key)
+	if strings.Contains(key, "\n") {
+		return false
+	}
+
+	fields := strings.Fields(key)
+	if len(fields) < 2 {
+		return false
+	}
+
+	_, err := base64.StdEncoding.DecodeString(fields[1])
+	return err == nil
+}
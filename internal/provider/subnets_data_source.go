@@ -0,0 +1,163 @@
+// Copyright (c) German Arutyunov
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gaarutyunov/terraform-provider-slicer/internal/slicer"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &SubnetsDataSource{}
+
+func NewSubnetsDataSource() datasource.DataSource {
+	return &SubnetsDataSource{}
+}
+
+// SubnetsDataSource defines the data source implementation.
+type SubnetsDataSource struct {
+	client *slicer.SlicerClient
+}
+
+// SubnetsDataSourceModel describes the data source data model.
+type SubnetsDataSourceModel struct {
+	HostGroup types.String `tfsdk:"host_group"`
+	Subnets   types.List   `tfsdk:"subnets"`
+}
+
+// SubnetModel describes a subnet in the list.
+type SubnetModel struct {
+	Name      types.String `tfsdk:"name"`
+	CIDR      types.String `tfsdk:"cidr"`
+	Gateway   types.String `tfsdk:"gateway"`
+	HostGroup types.String `tfsdk:"host_group"`
+}
+
+func (d *SubnetsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_subnets"
+}
+
+func (d *SubnetsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Fetches the networks known to Slicer.",
+
+		Attributes: map[string]schema.Attribute{
+			"host_group": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only return subnets used by this host group.",
+			},
+			"subnets": schema.ListNestedAttribute{
+				Computed:            true,
+				MarkdownDescription: "List of subnets.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "The name of the subnet.",
+						},
+						"cidr": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "The address range of the subnet in CIDR notation.",
+						},
+						"gateway": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "The gateway address of the subnet. Null if not reported by the API.",
+						},
+						"host_group": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "The host group whose VMs use the subnet. Null if the subnet is not tied to a host group.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *SubnetsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*SlicerProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *SlicerProviderData, got: %T", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = providerData.Client
+}
+
+func (d *SubnetsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data SubnetsDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Listing subnets")
+
+	subnets, err := d.client.ListSubnets(ctx)
+	if errors.Is(err, slicer.ErrNotSupported) {
+		resp.Diagnostics.AddError(
+			"Subnets Not Supported",
+			"The Slicer API does not expose its networks.",
+		)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list subnets: %s", err))
+		return
+	}
+
+	subnetModels := make([]SubnetModel, 0, len(subnets))
+	for _, subnet := range subnets {
+		if !data.HostGroup.IsNull() && subnet.HostGroup != data.HostGroup.ValueString() {
+			continue
+		}
+
+		subnetModel := SubnetModel{
+			Name:      types.StringValue(subnet.Name),
+			CIDR:      types.StringValue(subnet.CIDR),
+			Gateway:   types.StringNull(),
+			HostGroup: types.StringNull(),
+		}
+		if subnet.Gateway != "" {
+			subnetModel.Gateway = types.StringValue(subnet.Gateway)
+		}
+		if subnet.HostGroup != "" {
+			subnetModel.HostGroup = types.StringValue(subnet.HostGroup)
+		}
+		subnetModels = append(subnetModels, subnetModel)
+	}
+
+	subnetsValue, diags := types.ListValueFrom(ctx, types.ObjectType{
+		AttrTypes: map[string]attr.Type{
+			"name":       types.StringType,
+			"cidr":       types.StringType,
+			"gateway":    types.StringType,
+			"host_group": types.StringType,
+		},
+	}, subnetModels)
+	resp.Diagnostics.Append(diags...)
+	data.Subnets = subnetsValue
+
+	tflog.Trace(ctx, "Listed subnets", map[string]interface{}{
+		"count": len(subnetModels),
+	})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,107 @@
+// Copyright (c) German Arutyunov
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Operations that can be bounded in a timeouts block.
+const (
+	timeoutCreate = "create"
+	timeoutRead   = "read"
+	timeoutUpdate = "update"
+	timeoutDelete = "delete"
+)
+
+// TimeoutsModel describes the timeouts block.
+type TimeoutsModel struct {
+	Create types.String `tfsdk:"create"`
+	Read   types.String `tfsdk:"read"`
+	Update types.String `tfsdk:"update"`
+	Delete types.String `tfsdk:"delete"`
+}
+
+// timeoutsBlock returns the schema of the timeouts block shared by resources.
+func timeoutsBlock() schema.SingleNestedBlock {
+	attribute := func(operation string) schema.StringAttribute {
+		return schema.StringAttribute{
+			Optional:            true,
+			MarkdownDescription: fmt.Sprintf("How long %s may take (e.g., '10m'). Defaults to no limit other than the provider's per-request `timeout`.", operation),
+		}
+	}
+
+	return schema.SingleNestedBlock{
+		MarkdownDescription: "Per-operation time limits. An operation with a limit may make API calls that run longer than the provider's `timeout`, e.g. large file uploads.",
+		Attributes: map[string]schema.Attribute{
+			timeoutCreate: attribute("creating the resource"),
+			timeoutRead:   attribute("refreshing the resource"),
+			timeoutUpdate: attribute("updating the resource"),
+			timeoutDelete: attribute("destroying the resource"),
+		},
+	}
+}
+
+// value returns the configured timeout of operation.
+func (t *TimeoutsModel) value(operation string) types.String {
+	switch operation {
+	case timeoutCreate:
+		return t.Create
+	case timeoutRead:
+		return t.Read
+	case timeoutUpdate:
+		return t.Update
+	default:
+		return t.Delete
+	}
+}
+
+// validateTimeouts reports timeouts that are not positive durations.
+func validateTimeouts(timeouts *TimeoutsModel, diags *diag.Diagnostics) {
+	if timeouts == nil {
+		return
+	}
+
+	for _, operation := range []string{timeoutCreate, timeoutRead, timeoutUpdate, timeoutDelete} {
+		value := timeouts.value(operation)
+		if value.IsNull() || value.IsUnknown() {
+			continue
+		}
+		if d, err := time.ParseDuration(value.ValueString()); err != nil || d <= 0 {
+			diags.AddAttributeError(
+				path.Root("timeouts").AtName(operation),
+				"Invalid Duration",
+				fmt.Sprintf("%s must be a positive duration such as '30s' or '10m', got: %s", operation, value.ValueString()),
+			)
+		}
+	}
+}
+
+// withTimeout bounds ctx by the timeout configured for operation. ctx is
+// returned unchanged when none is set.
+func withTimeout(ctx context.Context, timeouts *TimeoutsModel, operation string) (context.Context, context.CancelFunc) {
+	if timeouts == nil {
+		return ctx, func() {}
+	}
+
+	value := timeouts.value(operation)
+	if value.IsNull() || value.IsUnknown() {
+		return ctx, func() {}
+	}
+
+	// Checked in ValidateConfig
+	timeout, err := time.ParseDuration(value.ValueString())
+	if err != nil {
+		return ctx, func() {}
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
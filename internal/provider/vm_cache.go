@@ -0,0 +1,93 @@
+// Copyright (c) German Arutyunov
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gaarutyunov/terraform-provider-slicer/internal/slicer"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// vmListCacheTTL is how long a VM list is reused. Terraform refreshes
+// resources and data sources of one operation concurrently, so a few seconds
+// are enough to share a single list between them.
+const vmListCacheTTL = 10 * time.Second
+
+// vmListCache memoizes the VM list for the resources and data sources of one
+// provider instance, so that many VM lookups in the same plan share a single
+// list request. Writes made through the provider invalidate it.
+type vmListCache struct {
+	client *slicer.SlicerClient
+	ttl    time.Duration
+
+	// mu is held while the list is fetched, so concurrent callers wait for
+	// the request in flight instead of issuing their own.
+	mu        sync.Mutex
+	vms       []slicer.SlicerNode
+	fetchedAt time.Time
+}
+
+func newVMListCache(client *slicer.SlicerClient, ttl time.Duration) *vmListCache {
+	return &vmListCache{
+		client: client,
+		ttl:    ttl,
+	}
+}
+
+// list returns all VMs, fetching them unless a list younger than the TTL is
+// cached. Errors are not cached.
+func (c *vmListCache) list(ctx context.Context) ([]slicer.SlicerNode, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.vms != nil && time.Since(c.fetchedAt) < c.ttl {
+		tflog.Trace(ctx, "Using cached VM list", map[string]interface{}{
+			"count": len(c.vms),
+		})
+		return c.vms, nil
+	}
+
+	vms, err := c.client.ListVMs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if vms == nil {
+		vms = []slicer.SlicerNode{}
+	}
+
+	c.vms = vms
+	c.fetchedAt = time.Now()
+	return vms, nil
+}
+
+// getVM returns the VM with the given hostname from the cached list. VMs
+// missing from it, e.g. because they were created after it was fetched, are
+// looked up directly, so ErrVMNotFound is only returned for VMs the API does
+// not know.
+func (c *vmListCache) getVM(ctx context.Context, hostname string) (*slicer.SlicerNode, error) {
+	vms, err := c.list(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, vm := range vms {
+		if vm.Hostname == hostname {
+			return &vm, nil
+		}
+	}
+
+	return c.client.GetVM(ctx, hostname)
+}
+
+// invalidate drops the cached list, e.g. after a VM was created, changed or
+// deleted.
+func (c *vmListCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.vms = nil
+}
@@ -0,0 +1,353 @@
+// Copyright (c) German Arutyunov
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/textproto"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// VMCloudInitModel describes the cloud-config the provider builds the VM's
+// userdata from.
+type VMCloudInitModel struct {
+	Packages   types.List             `tfsdk:"packages"`
+	Runcmd     types.List             `tfsdk:"runcmd"`
+	WriteFiles []VMCloudInitFileModel `tfsdk:"write_files"`
+	Users      []VMCloudInitUserModel `tfsdk:"users"`
+}
+
+// VMCloudInitFileModel describes a file written by cloud-init.
+type VMCloudInitFileModel struct {
+	Path        types.String `tfsdk:"path"`
+	Content     types.String `tfsdk:"content"`
+	Permissions types.String `tfsdk:"permissions"`
+	Owner       types.String `tfsdk:"owner"`
+	Defer       types.Bool   `tfsdk:"defer"`
+}
+
+// VMCloudInitUserModel describes a user created by cloud-init.
+type VMCloudInitUserModel struct {
+	Name              types.String `tfsdk:"name"`
+	Groups            types.List   `tfsdk:"groups"`
+	Sudo              types.String `tfsdk:"sudo"`
+	Shell             types.String `tfsdk:"shell"`
+	SSHAuthorizedKeys types.List   `tfsdk:"ssh_authorized_keys"`
+}
+
+// cloudInitDefaultUser is the user name that keeps the image's default user
+// when users are added.
+const cloudInitDefaultUser = "default"
+
+// userdataBoundary separates the parts of the multi-part userdata. It is
+// fixed, so the same configuration always gives the same userdata and
+// userdata_sha256 does not change between plans.
+const userdataBoundary = "==SLICER-USERDATA-BOUNDARY=="
+
+// cloudConfigMergeType makes cloud-init append the generated lists to those
+// of a cloud-config given in userdata instead of replacing them.
+const cloudConfigMergeType = "list(append)+dict(no_replace,recurse_list)+str()"
+
+func vmCloudInitBlock() schema.SingleNestedBlock {
+	return schema.SingleNestedBlock{
+		MarkdownDescription: "Builds the VM's userdata from structured cloud-config, so packages, commands, files and users do not have to be written as YAML in a heredoc. The provider compiles the block into multi-part MIME userdata, together with `userdata` when that is set too. Like `userdata`, changing the block replaces the VM.",
+		Attributes: map[string]schema.Attribute{
+			"packages": schema.ListAttribute{
+				Optional:            true,
+				MarkdownDescription: "Packages to install at first boot.",
+				ElementType:         types.StringType,
+			},
+			"runcmd": schema.ListAttribute{
+				Optional:            true,
+				MarkdownDescription: "Shell commands run as root at the end of the first boot, after packages are installed and files written.",
+				ElementType:         types.StringType,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"write_files": schema.ListNestedBlock{
+				MarkdownDescription: "Files written at first boot.",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"path": schema.StringAttribute{
+							Required:            true,
+							MarkdownDescription: "Absolute path of the file on the VM.",
+						},
+						"content": schema.StringAttribute{
+							Required:            true,
+							MarkdownDescription: "Content of the file.",
+						},
+						"permissions": schema.StringAttribute{
+							Optional:            true,
+							MarkdownDescription: "File permissions as an octal string with a leading zero (e.g., '0644'). Defaults to cloud-init's default, '0644'.",
+						},
+						"owner": schema.StringAttribute{
+							Optional:            true,
+							MarkdownDescription: "Owner of the file as `user:group`. Defaults to `root:root`.",
+						},
+						"defer": schema.BoolAttribute{
+							Optional:            true,
+							MarkdownDescription: "Write the file after users are created and packages installed, e.g. when `owner` is a user from `users`. Defaults to false.",
+						},
+					},
+				},
+			},
+			"users": schema.ListNestedBlock{
+				MarkdownDescription: "Users created at first boot. Setting users replaces the image's default user unless a user named `default` is listed.",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Required:            true,
+							MarkdownDescription: "Name of the user. `default` keeps the image's default user; no other attributes may be set for it.",
+						},
+						"groups": schema.ListAttribute{
+							Optional:            true,
+							MarkdownDescription: "Supplementary groups of the user (e.g., `[\"docker\"]`).",
+							ElementType:         types.StringType,
+						},
+						"sudo": schema.StringAttribute{
+							Optional:            true,
+							MarkdownDescription: "sudoers rule for the user (e.g., 'ALL=(ALL) NOPASSWD:ALL').",
+						},
+						"shell": schema.StringAttribute{
+							Optional:            true,
+							MarkdownDescription: "Login shell of the user (e.g., '/bin/bash').",
+						},
+						"ssh_authorized_keys": schema.ListAttribute{
+							Optional:            true,
+							MarkdownDescription: "SSH public keys that may log in as the user.",
+							ElementType:         types.StringType,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// validateCloudInit checks the parts of a cloud_init block that can be
+// checked before the VM is created.
+func validateCloudInit(cloudInit *VMCloudInitModel, diags *diag.Diagnostics) {
+	if cloudInit == nil {
+		return
+	}
+
+	blockPath := path.Root("cloud_init")
+	for i, file := range cloudInit.WriteFiles {
+		filePath := blockPath.AtName("write_files").AtListIndex(i)
+		validatePermissions(file.Permissions, filePath.AtName("permissions"), diags)
+
+		if !file.Path.IsUnknown() && !strings.HasPrefix(file.Path.ValueString(), "/") {
+			diags.AddAttributeError(
+				filePath.AtName("path"),
+				"Invalid File Path",
+				fmt.Sprintf("path must be absolute, got: %s", file.Path.ValueString()),
+			)
+		}
+	}
+
+	for i, user := range cloudInit.Users {
+		if user.Name.ValueString() != cloudInitDefaultUser {
+			continue
+		}
+		if !user.Groups.IsNull() || !user.Sudo.IsNull() || !user.Shell.IsNull() || !user.SSHAuthorizedKeys.IsNull() {
+			diags.AddAttributeError(
+				blockPath.AtName("users").AtListIndex(i),
+				"Invalid Default User",
+				"The default user keeps the image's settings; only 'name' can be set for it.",
+			)
+		}
+	}
+}
+
+// cloudConfig is the cloud-config built from a cloud_init block.
+type cloudConfig struct {
+	Packages   []string          `json:"packages,omitempty"`
+	WriteFiles []cloudConfigFile `json:"write_files,omitempty"`
+	Users      []interface{}     `json:"users,omitempty"`
+	Runcmd     []string          `json:"runcmd,omitempty"`
+}
+
+// cloudConfigFile is an entry of write_files.
+type cloudConfigFile struct {
+	Path        string `json:"path"`
+	Content     string `json:"content"`
+	Permissions string `json:"permissions,omitempty"`
+	Owner       string `json:"owner,omitempty"`
+	Defer       bool   `json:"defer,omitempty"`
+}
+
+// cloudConfigUser is an entry of users.
+type cloudConfigUser struct {
+	Name              string   `json:"name"`
+	Groups            string   `json:"groups,omitempty"`
+	Sudo              string   `json:"sudo,omitempty"`
+	Shell             string   `json:"shell,omitempty"`
+	SSHAuthorizedKeys []string `json:"ssh_authorized_keys,omitempty"`
+}
+
+// vmUserdata returns the userdata the VM is created with: userdata as is,
+// or a multi-part MIME document combining the cloud-config compiled from
+// cloudInit with userdata. known is false while any input is unknown.
+func vmUserdata(ctx context.Context, cloudInit *VMCloudInitModel, userdata types.String) (result string, known bool, diags diag.Diagnostics) {
+	if userdata.IsUnknown() {
+		return "", false, nil
+	}
+	if cloudInit == nil {
+		return userdata.ValueString(), true, nil
+	}
+
+	config, known, diags := compileCloudConfig(ctx, cloudInit)
+	if !known || diags.HasError() {
+		return "", known, diags
+	}
+
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	if err := w.SetBoundary(userdataBoundary); err != nil {
+		diags.AddError("Invalid Userdata", fmt.Sprintf("Unable to build multi-part userdata: %s", err))
+		return "", true, diags
+	}
+
+	fmt.Fprintf(&buf, "Content-Type: multipart/mixed; boundary=%q\r\nMIME-Version: 1.0\r\n\r\n", userdataBoundary)
+
+	// The userdata goes first, so the generated cloud-config is merged into it
+	if !userdata.IsNull() && userdata.ValueString() != "" {
+		contentType, ok := userdataContentType(userdata.ValueString())
+		if !ok {
+			diags.AddAttributeError(
+				path.Root("userdata"),
+				"Unsupported Userdata",
+				"userdata must start with '#cloud-config', '#!', '#cloud-boothook' or '#include' to be combined with a cloud_init block.",
+			)
+			return "", true, diags
+		}
+		writeUserdataPart(w, textproto.MIMEHeader{
+			"Content-Type": {contentType + `; charset="utf-8"`},
+		}, userdata.ValueString(), &diags)
+	}
+
+	writeUserdataPart(w, textproto.MIMEHeader{
+		"Content-Type": {`text/cloud-config; charset="utf-8"`},
+		"Merge-Type":   {cloudConfigMergeType},
+	}, config, &diags)
+
+	if err := w.Close(); err != nil {
+		diags.AddError("Invalid Userdata", fmt.Sprintf("Unable to build multi-part userdata: %s", err))
+	}
+
+	return buf.String(), true, diags
+}
+
+// compileCloudConfig renders cloudInit as a cloud-config document. JSON is
+// valid YAML, so encoding/json gives correct quoting for any content.
+func compileCloudConfig(ctx context.Context, cloudInit *VMCloudInitModel) (string, bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	var config cloudConfig
+
+	if !listKnown(cloudInit.Packages) || !listKnown(cloudInit.Runcmd) {
+		return "", false, diags
+	}
+	diags.Append(cloudInit.Packages.ElementsAs(ctx, &config.Packages, false)...)
+	diags.Append(cloudInit.Runcmd.ElementsAs(ctx, &config.Runcmd, false)...)
+
+	for _, file := range cloudInit.WriteFiles {
+		if file.Path.IsUnknown() || file.Content.IsUnknown() || file.Permissions.IsUnknown() || file.Owner.IsUnknown() || file.Defer.IsUnknown() {
+			return "", false, diags
+		}
+		config.WriteFiles = append(config.WriteFiles, cloudConfigFile{
+			Path:        file.Path.ValueString(),
+			Content:     file.Content.ValueString(),
+			Permissions: file.Permissions.ValueString(),
+			Owner:       file.Owner.ValueString(),
+			Defer:       file.Defer.ValueBool(),
+		})
+	}
+
+	for _, user := range cloudInit.Users {
+		if user.Name.IsUnknown() || !listKnown(user.Groups) || user.Sudo.IsUnknown() || user.Shell.IsUnknown() || !listKnown(user.SSHAuthorizedKeys) {
+			return "", false, diags
+		}
+
+		// cloud-init expects the default user as a plain string
+		if user.Name.ValueString() == cloudInitDefaultUser {
+			config.Users = append(config.Users, cloudInitDefaultUser)
+			continue
+		}
+
+		var groups []string
+		diags.Append(user.Groups.ElementsAs(ctx, &groups, false)...)
+
+		configUser := cloudConfigUser{
+			Name:   user.Name.ValueString(),
+			Groups: strings.Join(groups, ","),
+			Sudo:   user.Sudo.ValueString(),
+			Shell:  user.Shell.ValueString(),
+		}
+		diags.Append(user.SSHAuthorizedKeys.ElementsAs(ctx, &configUser.SSHAuthorizedKeys, false)...)
+		config.Users = append(config.Users, configUser)
+	}
+
+	if diags.HasError() {
+		return "", true, diags
+	}
+
+	out, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		diags.AddError("Invalid Userdata", fmt.Sprintf("Unable to build cloud-config: %s", err))
+		return "", true, diags
+	}
+
+	return "#cloud-config\n" + string(out) + "\n", true, diags
+}
+
+// listKnown reports whether l and all of its elements are known.
+func listKnown(l types.List) bool {
+	if l.IsUnknown() {
+		return false
+	}
+	for _, element := range l.Elements() {
+		if element.IsUnknown() {
+			return false
+		}
+	}
+	return true
+}
+
+// userdataContentType returns the MIME type cloud-init uses for userdata
+// starting with the given marker.
+func userdataContentType(userdata string) (string, bool) {
+	switch {
+	case strings.HasPrefix(userdata, "#cloud-config"):
+		return "text/cloud-config", true
+	case strings.HasPrefix(userdata, "#cloud-boothook"):
+		return "text/cloud-boothook", true
+	case strings.HasPrefix(userdata, "#include"):
+		return "text/x-include-url", true
+	case strings.HasPrefix(userdata, "#!"):
+		return "text/x-shellscript", true
+	}
+	return "", false
+}
+
+// writeUserdataPart adds a part with the given header and content to w.
+func writeUserdataPart(w *multipart.Writer, header textproto.MIMEHeader, content string, diags *diag.Diagnostics) {
+	header.Set("MIME-Version", "1.0")
+
+	part, err := w.CreatePart(header)
+	if err == nil {
+		_, err = part.Write([]byte(content))
+	}
+	if err != nil {
+		diags.AddError("Invalid Userdata", fmt.Sprintf("Unable to build multi-part userdata: %s", err))
+	}
+}
@@ -0,0 +1,256 @@
+// Copyright (c) German Arutyunov
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// userdataPart is a part of multi-part userdata.
+type userdataPart struct {
+	ContentType string
+	MergeType   string
+	Content     string
+}
+
+// parseUserdata splits multi-part userdata into its parts.
+func parseUserdata(t *testing.T, userdata string) []userdataPart {
+	t.Helper()
+
+	msg, err := mail.ReadMessage(strings.NewReader(userdata))
+	if err != nil {
+		t.Fatalf("Unable to read userdata: %v", err)
+	}
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/mixed" {
+		t.Fatalf("Want multipart/mixed userdata, got %q (%v)", msg.Header.Get("Content-Type"), err)
+	}
+
+	var parts []userdataPart
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return parts
+		}
+		if err != nil {
+			t.Fatalf("Unable to read userdata part: %v", err)
+		}
+		content, err := io.ReadAll(part)
+		if err != nil {
+			t.Fatalf("Unable to read userdata part: %v", err)
+		}
+		parts = append(parts, userdataPart{
+			ContentType: part.Header.Get("Content-Type"),
+			MergeType:   part.Header.Get("Merge-Type"),
+			Content:     string(content),
+		})
+	}
+}
+
+// stringList returns a list of strings for a cloud_init attribute.
+func stringList(values ...string) types.List {
+	list, _ := types.ListValueFrom(context.Background(), types.StringType, values)
+	return list
+}
+
+// testCloudInit returns a cloud_init block using every kind of setting.
+func testCloudInit() *VMCloudInitModel {
+	return &VMCloudInitModel{
+		Packages: stringList("nginx"),
+		Runcmd:   stringList("systemctl enable --now nginx"),
+		WriteFiles: []VMCloudInitFileModel{{
+			Path:        types.StringValue("/etc/app.conf"),
+			Content:     types.StringValue("port: 8080\nname: \"app\"\n"),
+			Permissions: types.StringValue("0640"),
+			Owner:       types.StringValue("app:app"),
+			Defer:       types.BoolValue(true),
+		}},
+		Users: []VMCloudInitUserModel{
+			{Name: types.StringValue(cloudInitDefaultUser)},
+			{
+				Name:              types.StringValue("app"),
+				Groups:            stringList("docker", "wheel"),
+				Sudo:              types.StringValue("ALL=(ALL) NOPASSWD:ALL"),
+				Shell:             types.StringValue("/bin/bash"),
+				SSHAuthorizedKeys: stringList("ssh-ed25519 AAAA app@example.com"),
+			},
+		},
+	}
+}
+
+func TestVMUserdata_CloudInitOnly(t *testing.T) {
+	userdata, known, diags := vmUserdata(context.Background(), testCloudInit(), types.StringNull())
+	if diags.HasError() {
+		t.Fatalf("Unexpected error: %v", diags)
+	}
+	if !known {
+		t.Fatal("Want known userdata")
+	}
+
+	parts := parseUserdata(t, userdata)
+	if len(parts) != 1 {
+		t.Fatalf("Want 1 part, got %d", len(parts))
+	}
+	if parts[0].ContentType != `text/cloud-config; charset="utf-8"` {
+		t.Errorf("Want a cloud-config part, got %q", parts[0].ContentType)
+	}
+	if parts[0].MergeType != cloudConfigMergeType {
+		t.Errorf("Want Merge-Type %q, got %q", cloudConfigMergeType, parts[0].MergeType)
+	}
+
+	// The cloud-config is JSON, which YAML parsers read as is
+	config, ok := strings.CutPrefix(parts[0].Content, "#cloud-config\n")
+	if !ok {
+		t.Fatalf("Want the part to start with #cloud-config, got %q", parts[0].Content)
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal([]byte(config), &got); err != nil {
+		t.Fatalf("Want a JSON cloud-config, got %q: %v", config, err)
+	}
+
+	want := map[string]interface{}{
+		"packages": []interface{}{"nginx"},
+		"runcmd":   []interface{}{"systemctl enable --now nginx"},
+		"write_files": []interface{}{map[string]interface{}{
+			"path":        "/etc/app.conf",
+			"content":     "port: 8080\nname: \"app\"\n",
+			"permissions": "0640",
+			"owner":       "app:app",
+			"defer":       true,
+		}},
+		"users": []interface{}{
+			"default",
+			map[string]interface{}{
+				"name":                "app",
+				"groups":              "docker,wheel",
+				"sudo":                "ALL=(ALL) NOPASSWD:ALL",
+				"shell":               "/bin/bash",
+				"ssh_authorized_keys": []interface{}{"ssh-ed25519 AAAA app@example.com"},
+			},
+		},
+	}
+	gotJSON, _ := json.Marshal(got)
+	wantJSON, _ := json.Marshal(want)
+	if string(gotJSON) != string(wantJSON) {
+		t.Errorf("Want cloud-config %s, got %s", wantJSON, gotJSON)
+	}
+}
+
+func TestVMUserdata_CombinedWithUserdata(t *testing.T) {
+	tests := []struct {
+		userdata    string
+		contentType string
+	}{
+		{userdata: "#cloud-config\nhostname: app\n", contentType: "text/cloud-config"},
+		{userdata: "#!/bin/sh\necho hello\n", contentType: "text/x-shellscript"},
+		{userdata: "#cloud-boothook\necho early\n", contentType: "text/cloud-boothook"},
+		{userdata: "#include\nhttps://example.com/userdata\n", contentType: "text/x-include-url"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.contentType, func(t *testing.T) {
+			userdata, known, diags := vmUserdata(context.Background(), testCloudInit(), types.StringValue(tt.userdata))
+			if diags.HasError() {
+				t.Fatalf("Unexpected error: %v", diags)
+			}
+			if !known {
+				t.Fatal("Want known userdata")
+			}
+
+			// The userdata goes first, so the generated cloud-config merges into it
+			parts := parseUserdata(t, userdata)
+			if len(parts) != 2 {
+				t.Fatalf("Want 2 parts, got %d", len(parts))
+			}
+			if want := tt.contentType + `; charset="utf-8"`; parts[0].ContentType != want {
+				t.Errorf("Want content type %q, got %q", want, parts[0].ContentType)
+			}
+			if parts[0].Content != tt.userdata {
+				t.Errorf("Want userdata %q unchanged, got %q", tt.userdata, parts[0].Content)
+			}
+			if !strings.HasPrefix(parts[1].Content, "#cloud-config\n") || parts[1].MergeType != cloudConfigMergeType {
+				t.Errorf("Want the generated cloud-config second, got %+v", parts[1])
+			}
+		})
+	}
+}
+
+func TestVMUserdata_UnsupportedUserdata(t *testing.T) {
+	_, _, diags := vmUserdata(context.Background(), testCloudInit(), types.StringValue("hostname: app\n"))
+	if !diags.HasError() || diags.Errors()[0].Summary() != "Unsupported Userdata" {
+		t.Errorf("Want an Unsupported Userdata error, got %v", diags)
+	}
+}
+
+func TestVMUserdata_WithoutCloudInit(t *testing.T) {
+	userdata, known, diags := vmUserdata(context.Background(), nil, types.StringValue("hostname: app\n"))
+	if diags.HasError() || !known {
+		t.Fatalf("Want known userdata without errors, got %v", diags)
+	}
+	if userdata != "hostname: app\n" {
+		t.Errorf("Want userdata as is, got %q", userdata)
+	}
+
+	_, known, _ = vmUserdata(context.Background(), testCloudInit(), types.StringUnknown())
+	if known {
+		t.Error("Want unknown userdata to be unknown")
+	}
+
+	cloudInit := testCloudInit()
+	cloudInit.Packages = types.ListValueMust(types.StringType, []attr.Value{types.StringUnknown()})
+	_, known, _ = vmUserdata(context.Background(), cloudInit, types.StringNull())
+	if known {
+		t.Error("Want userdata with an unknown package to be unknown")
+	}
+}
+
+// TestVMUserdata_Stable pins the rendered userdata. userdata_sha256 is
+// computed from it, so any change here replaces existing VMs.
+func TestVMUserdata_Stable(t *testing.T) {
+	cloudInit := &VMCloudInitModel{Packages: stringList("nginx"), Runcmd: types.ListNull(types.StringType)}
+
+	want := "Content-Type: multipart/mixed; boundary=\"==SLICER-USERDATA-BOUNDARY==\"\r\n" +
+		"MIME-Version: 1.0\r\n" +
+		"\r\n" +
+		"--==SLICER-USERDATA-BOUNDARY==\r\n" +
+		"Content-Type: text/cloud-config; charset=\"utf-8\"\r\n" +
+		"Merge-Type: list(append)+dict(no_replace,recurse_list)+str()\r\n" +
+		"Mime-Version: 1.0\r\n" +
+		"\r\n" +
+		"#cloud-config\n" +
+		"{\n" +
+		"  \"packages\": [\n" +
+		"    \"nginx\"\n" +
+		"  ]\n" +
+		"}\n" +
+		"\r\n" +
+		"--==SLICER-USERDATA-BOUNDARY==--\r\n"
+
+	for range 3 {
+		userdata, _, diags := vmUserdata(context.Background(), cloudInit, types.StringNull())
+		if diags.HasError() {
+			t.Fatalf("Unexpected error: %v", diags)
+		}
+		if userdata != want {
+			t.Fatalf("Want stable userdata %q, got %q", want, userdata)
+		}
+	}
+
+	first, _, _ := vmUserdata(context.Background(), testCloudInit(), types.StringValue("#!/bin/sh\n"))
+	second, _, _ := vmUserdata(context.Background(), testCloudInit(), types.StringValue("#!/bin/sh\n"))
+	if first != second {
+		t.Errorf("Want the same userdata across calls, got %q and %q", first, second)
+	}
+}
@@ -5,6 +5,7 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -25,7 +26,7 @@ func NewVMDataSource() datasource.DataSource {
 
 // VMDataSource defines the data source implementation.
 type VMDataSource struct {
-	client *slicer.SlicerClient
+	vms *vmListCache
 }
 
 // VMDataSourceModel describes the data source data model.
@@ -36,6 +37,7 @@ type VMDataSourceModel struct {
 	RamGB     types.Int64  `tfsdk:"ram_gb"`
 	Arch      types.String `tfsdk:"arch"`
 	Tags      types.Map    `tfsdk:"tags"`
+	Secrets   types.List   `tfsdk:"secrets"`
 	CreatedAt types.String `tfsdk:"created_at"`
 }
 
@@ -73,6 +75,11 @@ func (d *VMDataSource) Schema(ctx context.Context, req datasource.SchemaRequest,
 				MarkdownDescription: "Tags applied to the VM.",
 				ElementType:         types.StringType,
 			},
+			"secrets": schema.ListAttribute{
+				Computed:            true,
+				MarkdownDescription: "Names of the secrets mounted on the VM.",
+				ElementType:         types.StringType,
+			},
 			"created_at": schema.StringAttribute{
 				Computed:            true,
 				MarkdownDescription: "The creation timestamp of the VM.",
@@ -95,7 +102,7 @@ func (d *VMDataSource) Configure(ctx context.Context, req datasource.ConfigureRe
 		return
 	}
 
-	d.client = providerData.Client
+	d.vms = providerData.VMs
 }
 
 func (d *VMDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
@@ -110,23 +117,13 @@ func (d *VMDataSource) Read(ctx context.Context, req datasource.ReadRequest, res
 		"hostname": data.Hostname.ValueString(),
 	})
 
-	// List all VMs and find the one we're looking for
-	vms, err := d.client.ListVMs(ctx)
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list VMs: %s", err))
+	found, err := d.vms.getVM(ctx, data.Hostname.ValueString())
+	if errors.Is(err, slicer.ErrVMNotFound) {
+		resp.Diagnostics.AddError("Not Found", fmt.Sprintf("VM with hostname '%s' not found", data.Hostname.ValueString()))
 		return
 	}
-
-	var found *slicer.SlicerNode
-	for _, vm := range vms {
-		if vm.Hostname == data.Hostname.ValueString() {
-			found = &vm
-			break
-		}
-	}
-
-	if found == nil {
-		resp.Diagnostics.AddError("Not Found", fmt.Sprintf("VM with hostname '%s' not found", data.Hostname.ValueString()))
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read VM: %s", err))
 		return
 	}
 
@@ -170,6 +167,10 @@ func (d *VMDataSource) Read(ctx context.Context, req datasource.ReadRequest, res
 		data.Tags = types.MapNull(types.StringType)
 	}
 
+	secretsValue, diags := types.ListValueFrom(ctx, types.StringType, found.Secrets)
+	resp.Diagnostics.Append(diags...)
+	data.Secrets = secretsValue
+
 	tflog.Trace(ctx, "Read VM", map[string]interface{}{
 		"hostname": data.Hostname.ValueString(),
 		"ip":       ip,
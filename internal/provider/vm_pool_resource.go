@@ -0,0 +1,481 @@
+// Copyright (c) German Arutyunov
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/gaarutyunov/terraform-provider-slicer/internal/slicer"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &VMPoolResource{}
+var _ resource.ResourceWithValidateConfig = &VMPoolResource{}
+var _ resource.ResourceWithModifyPlan = &VMPoolResource{}
+
+func NewVMPoolResource() resource.Resource {
+	return &VMPoolResource{}
+}
+
+// VMPoolResource defines the resource implementation.
+type VMPoolResource struct {
+	client      *slicer.SlicerClient
+	tokenScopes []string
+	defaultTags map[string]string
+	vms         *vmListCache
+}
+
+// VMPoolResourceModel describes the resource data model.
+type VMPoolResourceModel struct {
+	ID         types.String `tfsdk:"id"`
+	Name       types.String `tfsdk:"name"`
+	HostGroup  types.String `tfsdk:"host_group"`
+	Size       types.Int64  `tfsdk:"size"`
+	TemplateID types.String `tfsdk:"template_id"`
+	CPUs       types.Int64  `tfsdk:"cpus"`
+	RamGB      types.Int64  `tfsdk:"ram_gb"`
+	DiskImage  types.String `tfsdk:"disk_image"`
+	ImportUser types.String `tfsdk:"import_user"`
+	SSHKeys    types.List   `tfsdk:"ssh_keys"`
+	Userdata   types.String `tfsdk:"userdata"`
+	Tags       types.Map    `tfsdk:"tags"`
+	Secrets    types.List   `tfsdk:"secrets"`
+	Hostnames  types.List   `tfsdk:"hostnames"`
+	IPs        types.List   `tfsdk:"ips"`
+}
+
+// vmPoolTag is the tag key that marks the VMs belonging to a pool. Its value
+// is the pool name.
+const vmPoolTag = "slicer-pool"
+
+func (r *VMPoolResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_vm_pool"
+}
+
+func (r *VMPoolResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages a number of identical Slicer VMs in a host group as one resource. " +
+			"Changing `size` adds or removes VMs without touching the others; changing the VM spec replaces the whole pool.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The unique identifier of the pool (`host_group/name`).",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The name of the pool. Member VMs are tagged `slicer-pool=<name>`, so it must be unique within the host group.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"host_group": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The host group to create the VMs in.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"size": schema.Int64Attribute{
+				Required:            true,
+				MarkdownDescription: "Number of VMs in the pool. Scaling down deletes the most recently created VMs first.",
+			},
+			"template_id": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Name of a `slicer_vm_template` providing defaults for the VMs.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"cpus": schema.Int64Attribute{
+				Optional:            true,
+				MarkdownDescription: "Number of CPUs per VM. Defaults to host group setting.",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"ram_gb": schema.Int64Attribute{
+				Optional:            true,
+				MarkdownDescription: "RAM per VM in GB. Defaults to host group setting.",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"disk_image": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Custom disk image to use.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"import_user": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Import SSH keys from GitHub user.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"ssh_keys": schema.ListAttribute{
+				Optional:            true,
+				MarkdownDescription: "List of SSH public keys to inject.",
+				ElementType:         types.StringType,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+			},
+			"userdata": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Cloud-init userdata script.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"tags": schema.MapAttribute{
+				Optional:            true,
+				MarkdownDescription: "Tags to apply to the VMs (key=value format). The `slicer-pool` key is reserved.",
+				ElementType:         types.StringType,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			"secrets": schema.ListAttribute{
+				Optional:            true,
+				MarkdownDescription: "List of secret names to inject into the VMs.",
+				ElementType:         types.StringType,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+			},
+			"hostnames": schema.ListAttribute{
+				Computed:            true,
+				MarkdownDescription: "Hostnames of the VMs in the pool, oldest first.",
+				ElementType:         types.StringType,
+			},
+			"ips": schema.ListAttribute{
+				Computed:            true,
+				MarkdownDescription: "IP addresses of the VMs in the pool, in the same order as `hostnames`.",
+				ElementType:         types.StringType,
+			},
+		},
+	}
+}
+
+func (r *VMPoolResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*SlicerProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *SlicerProviderData, got: %T", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = providerData.Client
+	r.tokenScopes = providerData.TokenScopes
+	r.defaultTags = providerData.DefaultTags
+	r.vms = providerData.VMs
+}
+
+func (r *VMPoolResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data VMPoolResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.Size.IsNull() && !data.Size.IsUnknown() && data.Size.ValueInt64() < 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("size"),
+			"Invalid Pool Size",
+			fmt.Sprintf("size must not be negative, got: %d", data.Size.ValueInt64()),
+		)
+	}
+
+	if !data.Tags.IsNull() && !data.Tags.IsUnknown() {
+		if _, ok := data.Tags.Elements()[vmPoolTag]; ok {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("tags"),
+				"Reserved Tag",
+				fmt.Sprintf("The '%s' tag is set by the provider to track pool members.", vmPoolTag),
+			)
+		}
+	}
+}
+
+func (r *VMPoolResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	checkScope(req, r.tokenScopes, scopeVMsWrite, "slicer_vm_pool", &resp.Diagnostics)
+}
+
+func (r *VMPoolResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data VMPoolResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	defer r.vms.invalidate()
+
+	data.ID = types.StringValue(data.HostGroup.ValueString() + "/" + data.Name.ValueString())
+
+	members, createErr := r.scaleUp(ctx, &data, nil, int(data.Size.ValueInt64()), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// VMs created before a failure are kept in state so they are not leaked;
+	// the pool is tainted and replaced on the next apply
+	resp.Diagnostics.Append(setVMPoolMembers(ctx, &data, members)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+
+	if createErr != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create pool VM: %s", createErr))
+	}
+}
+
+func (r *VMPoolResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data VMPoolResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	members, err := r.members(ctx, &data)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list pool VMs: %s", err))
+		return
+	}
+
+	// VMs deleted outside of Terraform show up as a smaller size, so the next
+	// apply creates replacements
+	resp.Diagnostics.Append(setVMPoolMembers(ctx, &data, members)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *VMPoolResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data VMPoolResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	defer r.vms.invalidate()
+
+	// Only size can change in place
+	members, err := r.members(ctx, &data)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list pool VMs: %s", err))
+		return
+	}
+
+	var resizeErr error
+	want := int(data.Size.ValueInt64())
+	switch {
+	case len(members) < want:
+		members, resizeErr = r.scaleUp(ctx, &data, members, want-len(members), &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	case len(members) > want:
+		members, resizeErr = r.scaleDown(ctx, &data, members, len(members)-want)
+	}
+
+	// On failure the state records the VMs that were created or deleted, so
+	// the next plan resizes the rest
+	resp.Diagnostics.Append(setVMPoolMembers(ctx, &data, members)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+
+	if resizeErr != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to resize VM pool: %s", resizeErr))
+	}
+}
+
+func (r *VMPoolResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data VMPoolResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	defer r.vms.invalidate()
+
+	members, err := r.members(ctx, &data)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list pool VMs: %s", err))
+		return
+	}
+
+	if _, err := r.scaleDown(ctx, &data, members, len(members)); err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete pool VMs: %s", err))
+	}
+}
+
+// members returns the VMs of the pool, oldest first.
+func (r *VMPoolResource) members(ctx context.Context, data *VMPoolResourceModel) ([]slicer.SlicerNode, error) {
+	nodes, err := r.client.GetHostGroupNodes(ctx, data.HostGroup.ValueString())
+	if err != nil {
+		return nil, err
+	}
+
+	tag := vmPoolTag + "=" + data.Name.ValueString()
+	var members []slicer.SlicerNode
+	for _, node := range nodes {
+		for _, t := range node.Tags {
+			if t == tag {
+				members = append(members, node)
+				break
+			}
+		}
+	}
+
+	sort.Slice(members, func(i, j int) bool {
+		if !members[i].CreatedAt.Equal(members[j].CreatedAt) {
+			return members[i].CreatedAt.Before(members[j].CreatedAt)
+		}
+		return members[i].Hostname < members[j].Hostname
+	})
+
+	return members, nil
+}
+
+// scaleUp creates count VMs in the pool and returns members with the new VMs
+// appended. Configuration problems are added to diags; the returned error is
+// the first API failure, after which no further VMs are created.
+func (r *VMPoolResource) scaleUp(ctx context.Context, data *VMPoolResourceModel, members []slicer.SlicerNode, count int, diags *diag.Diagnostics) ([]slicer.SlicerNode, error) {
+	createReq := slicer.SlicerCreateNodeRequest{
+		Template:   data.TemplateID.ValueString(),
+		CPUs:       int(data.CPUs.ValueInt64()),
+		DiskImage:  data.DiskImage.ValueString(),
+		ImportUser: data.ImportUser.ValueString(),
+		Userdata:   data.Userdata.ValueString(),
+	}
+
+	if !data.RamGB.IsNull() {
+		createReq.RamBytes = slicer.GiB(data.RamGB.ValueInt64())
+	}
+
+	if !data.SSHKeys.IsNull() {
+		diags.Append(data.SSHKeys.ElementsAs(ctx, &createReq.SSHKeys, false)...)
+	}
+
+	if !data.Secrets.IsNull() {
+		diags.Append(data.Secrets.ElementsAs(ctx, &createReq.Secrets, false)...)
+	}
+
+	tags := map[string]string{}
+	if !data.Tags.IsNull() {
+		diags.Append(data.Tags.ElementsAs(ctx, &tags, false)...)
+	}
+	if diags.HasError() {
+		return members, nil
+	}
+	tags = mergeTags(r.defaultTags, tags)
+	tags[vmPoolTag] = data.Name.ValueString()
+	createReq.Tags = tagsToAPI(tags)
+
+	for i := 0; i < count; i++ {
+		tflog.Debug(ctx, "Creating pool VM", map[string]interface{}{
+			"pool":       data.Name.ValueString(),
+			"host_group": data.HostGroup.ValueString(),
+		})
+
+		result, err := r.client.CreateVM(ctx, data.HostGroup.ValueString(), createReq)
+		if err != nil {
+			return members, err
+		}
+
+		members = append(members, slicer.SlicerNode{
+			Hostname:  result.Hostname,
+			IP:        result.IP,
+			CreatedAt: result.CreatedAt,
+		})
+
+		tflog.Trace(ctx, "Created pool VM", map[string]interface{}{
+			"pool":     data.Name.ValueString(),
+			"hostname": result.Hostname,
+		})
+	}
+
+	return members, nil
+}
+
+// scaleDown deletes the count most recently created VMs of the pool and
+// returns the remaining members.
+func (r *VMPoolResource) scaleDown(ctx context.Context, data *VMPoolResourceModel, members []slicer.SlicerNode, count int) ([]slicer.SlicerNode, error) {
+	hostnames := make([]string, 0, count)
+	for _, member := range members[len(members)-count:] {
+		hostnames = append(hostnames, member.Hostname)
+	}
+
+	tflog.Debug(ctx, "Deleting pool VMs", map[string]interface{}{
+		"pool":      data.Name.ValueString(),
+		"hostnames": hostnames,
+	})
+
+	// The response lists the deleted VMs even if some deletions failed
+	result, err := r.client.DeleteVMs(ctx, data.HostGroup.ValueString(), hostnames)
+	if result != nil {
+		members = slices.DeleteFunc(members, func(member slicer.SlicerNode) bool {
+			return slices.Contains(result.Deleted, member.Hostname)
+		})
+
+		tflog.Trace(ctx, "Deleted pool VMs", map[string]interface{}{
+			"pool":      data.Name.ValueString(),
+			"hostnames": result.Deleted,
+		})
+	}
+
+	return members, err
+}
+
+// setVMPoolMembers stores the size, hostnames and IPs of the pool VMs in the model.
+func setVMPoolMembers(ctx context.Context, data *VMPoolResourceModel, members []slicer.SlicerNode) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	hostnames := make([]string, 0, len(members))
+	ips := make([]string, 0, len(members))
+	for _, member := range members {
+		hostnames = append(hostnames, member.Hostname)
+		ip, _, _ := strings.Cut(member.IP, "/")
+		ips = append(ips, ip)
+	}
+
+	data.Size = types.Int64Value(int64(len(members)))
+
+	hostnamesValue, d := types.ListValueFrom(ctx, types.StringType, hostnames)
+	diags.Append(d...)
+	data.Hostnames = hostnamesValue
+
+	ipsValue, d := types.ListValueFrom(ctx, types.StringType, ips)
+	diags.Append(d...)
+	data.IPs = ipsValue
+
+	return diags
+}
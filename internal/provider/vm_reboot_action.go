@@ -0,0 +1,198 @@
+// Copyright (c) German Arutyunov
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gaarutyunov/terraform-provider-slicer/internal/slicer"
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ action.Action = &VMRebootAction{}
+var _ action.ActionWithConfigure = &VMRebootAction{}
+var _ action.ActionWithModifyPlan = &VMRebootAction{}
+var _ action.ActionWithValidateConfig = &VMRebootAction{}
+
+func NewVMRebootAction() action.Action {
+	return &VMRebootAction{}
+}
+
+// VMRebootAction defines the action implementation.
+type VMRebootAction struct {
+	client      *slicer.SlicerClient
+	serverInfo  *slicer.SlicerServerInfo
+	tokenScopes []string
+	vms         *vmListCache
+}
+
+// VMRebootActionModel describes the action data model.
+type VMRebootActionModel struct {
+	HostGroup types.String `tfsdk:"host_group"`
+	Hostname  types.String `tfsdk:"hostname"`
+	Wait      types.Bool   `tfsdk:"wait"`
+	Timeout   types.String `tfsdk:"timeout"`
+}
+
+func (a *VMRebootAction) Metadata(ctx context.Context, req action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_vm_reboot"
+}
+
+func (a *VMRebootAction) Schema(ctx context.Context, req action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Reboots a Slicer VM, e.g. after a kernel parameter file changed. Trigger it from the `lifecycle` block of the resource whose changes need a reboot, or run it with `terraform apply -invoke`. Requires Terraform 1.14 or later.",
+
+		Attributes: map[string]schema.Attribute{
+			"host_group": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The host group of the VM.",
+			},
+			"hostname": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The hostname of the VM to reboot.",
+			},
+			"wait": schema.BoolAttribute{
+				Optional:            true,
+				MarkdownDescription: "Wait until the VM agent answers again after the reboot. Defaults to true.",
+			},
+			"timeout": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "How long to wait for the VM to come back (e.g., '10m'). Defaults to '5m'.",
+			},
+		},
+	}
+}
+
+func (a *VMRebootAction) Configure(ctx context.Context, req action.ConfigureRequest, resp *action.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*SlicerProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Action Configure Type",
+			fmt.Sprintf("Expected *SlicerProviderData, got: %T", req.ProviderData),
+		)
+		return
+	}
+
+	a.client = providerData.Client
+	a.tokenScopes = providerData.TokenScopes
+	a.serverInfo = providerData.ServerInfo
+	a.vms = providerData.VMs
+}
+
+func (a *VMRebootAction) ValidateConfig(ctx context.Context, req action.ValidateConfigRequest, resp *action.ValidateConfigResponse) {
+	var data VMRebootActionModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Timeout.IsNull() || data.Timeout.IsUnknown() {
+		return
+	}
+
+	if d, err := time.ParseDuration(data.Timeout.ValueString()); err != nil || d <= 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("timeout"),
+			"Invalid Duration",
+			fmt.Sprintf("timeout must be a positive duration such as '30s' or '5m', got: %s", data.Timeout.ValueString()),
+		)
+	}
+}
+
+func (a *VMRebootAction) ModifyPlan(ctx context.Context, req action.ModifyPlanRequest, resp *action.ModifyPlanResponse) {
+	if !hasScope(a.tokenScopes, scopeVMsWrite) {
+		granted := strings.Join(a.tokenScopes, ", ")
+		if granted == "" {
+			granted = "none"
+		}
+		resp.Diagnostics.AddError(
+			"Insufficient Token Scope",
+			fmt.Sprintf("The Slicer API token lacks %s; slicer_vm_reboot actions will fail. Scopes granted to the token: %s.", scopeVMsWrite, granted),
+		)
+	}
+
+	checkCapability(a.serverInfo, capabilityVMPower, path.Root("hostname"), &resp.Diagnostics)
+}
+
+func (a *VMRebootAction) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	var data VMRebootActionModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	hostname := data.Hostname.ValueString()
+
+	tflog.Debug(ctx, "Rebooting VM", map[string]interface{}{
+		"hostname":   hostname,
+		"host_group": data.HostGroup.ValueString(),
+	})
+
+	defer a.vms.invalidate()
+
+	err := a.client.RebootVM(ctx, data.HostGroup.ValueString(), hostname)
+	if errors.Is(err, slicer.ErrNotSupported) {
+		resp.Diagnostics.AddError(
+			"VM Power Management Not Supported",
+			"The Slicer API does not support rebooting VMs.",
+		)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to reboot VM: %s", err))
+		return
+	}
+
+	if !data.Wait.IsNull() && !data.Wait.ValueBool() {
+		return
+	}
+
+	resp.SendProgress(action.InvokeProgressEvent{
+		Message: fmt.Sprintf("Waiting for %s to come back after the reboot", hostname),
+	})
+
+	timeout := defaultVMWaitTimeout
+	if !data.Timeout.IsNull() {
+		// Checked in ValidateConfig
+		timeout, _ = time.ParseDuration(data.Timeout.ValueString())
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var lastErr error
+	err = a.client.WaitUntil(waitCtx, func(ctx context.Context) (bool, error) {
+		_, lastErr = a.client.GetAgentHealth(ctx, hostname, false)
+		return lastErr == nil, nil
+	})
+	if errors.Is(err, context.DeadlineExceeded) && lastErr != nil {
+		err = fmt.Errorf("%w (last error: %s)", err, lastErr)
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"VM Not Ready",
+			fmt.Sprintf("VM %s was rebooted but its agent did not answer again: %s", hostname, err),
+		)
+		return
+	}
+
+	tflog.Trace(ctx, "Rebooted VM", map[string]interface{}{
+		"hostname": hostname,
+	})
+}
@@ -5,17 +5,30 @@ package provider
 
 import (
 	"context"
+	"crypto/sha256"
+	"errors"
 	"fmt"
+	"net"
+	"reflect"
+	"regexp"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/gaarutyunov/terraform-provider-slicer/internal/slicer"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
@@ -24,6 +37,8 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &VMResource{}
 var _ resource.ResourceWithImportState = &VMResource{}
+var _ resource.ResourceWithModifyPlan = &VMResource{}
+var _ resource.ResourceWithValidateConfig = &VMResource{}
 
 func NewVMResource() resource.Resource {
 	return &VMResource{}
@@ -31,26 +46,85 @@ func NewVMResource() resource.Resource {
 
 // VMResource defines the resource implementation.
 type VMResource struct {
-	client *slicer.SlicerClient
+	client             *slicer.SlicerClient
+	ignoredTagPrefixes []string
+	defaultTags        map[string]string
+	serverInfo         *slicer.SlicerServerInfo
+	tokenScopes        []string
+	vms                *vmListCache
 }
 
 // VMResourceModel describes the resource data model.
 type VMResourceModel struct {
-	ID         types.String `tfsdk:"id"`
-	HostGroup  types.String `tfsdk:"host_group"`
-	Hostname   types.String `tfsdk:"hostname"`
-	IP         types.String `tfsdk:"ip"`
-	CPUs       types.Int64  `tfsdk:"cpus"`
-	RamGB      types.Int64  `tfsdk:"ram_gb"`
-	Persistent types.Bool   `tfsdk:"persistent"`
-	DiskImage  types.String `tfsdk:"disk_image"`
-	ImportUser types.String `tfsdk:"import_user"`
-	SSHKeys    types.List   `tfsdk:"ssh_keys"`
-	Userdata   types.String `tfsdk:"userdata"`
-	Tags       types.Map    `tfsdk:"tags"`
-	Secrets    types.List   `tfsdk:"secrets"`
-	Arch       types.String `tfsdk:"arch"`
-	CreatedAt  types.String `tfsdk:"created_at"`
+	ID                 types.String      `tfsdk:"id"`
+	HostGroup          types.String      `tfsdk:"host_group"`
+	TemplateID         types.String      `tfsdk:"template_id"`
+	SourceHostname     types.String      `tfsdk:"source_hostname"`
+	Name               types.String      `tfsdk:"name"`
+	HostnamePrefix     types.String      `tfsdk:"hostname_prefix"`
+	Hostname           types.String      `tfsdk:"hostname"`
+	IP                 types.String      `tfsdk:"ip"`
+	CPUs               types.Int64       `tfsdk:"cpus"`
+	RamGB              types.Int64       `tfsdk:"ram_gb"`
+	RamBytes           types.Int64       `tfsdk:"ram_bytes"`
+	GPUCount           types.Int64       `tfsdk:"gpu_count"`
+	GPUType            types.String      `tfsdk:"gpu_type"`
+	Persistent         types.Bool        `tfsdk:"persistent"`
+	DiskImage          types.String      `tfsdk:"disk_image"`
+	ImportUser         types.String      `tfsdk:"import_user"`
+	SSHKeys            types.List        `tfsdk:"ssh_keys"`
+	SSHKeyNames        types.List        `tfsdk:"ssh_key_names"`
+	Userdata           types.String      `tfsdk:"userdata"`
+	UserdataSHA256     types.String      `tfsdk:"userdata_sha256"`
+	Tags               types.Map         `tfsdk:"tags"`
+	Metadata           types.Map         `tfsdk:"metadata"`
+	IgnoredTagPrefixes types.List        `tfsdk:"ignored_tag_prefixes"`
+	Secrets            types.List        `tfsdk:"secrets"`
+	VolumeAttachments  types.Set         `tfsdk:"volume_attachments"`
+	Priority           types.String      `tfsdk:"priority"`
+	PowerState         types.String      `tfsdk:"power_state"`
+	ReverseDNS         types.String      `tfsdk:"reverse_dns"`
+	CDROMImage         types.String      `tfsdk:"cdrom_image"`
+	IPAddress          types.String      `tfsdk:"ip_address"`
+	Subnet             types.String      `tfsdk:"subnet"`
+	SecureBoot         types.Bool        `tfsdk:"secure_boot"`
+	TPM                types.Bool        `tfsdk:"tpm"`
+	Schedule           *VMScheduleModel  `tfsdk:"schedule"`
+	Watchdog           *VMWatchdogModel  `tfsdk:"watchdog"`
+	AdditionalDisks    []VMDiskModel     `tfsdk:"additional_disks"`
+	WaitFor            *VMWaitForModel   `tfsdk:"wait_for"`
+	CloudInit          *VMCloudInitModel `tfsdk:"cloud_init"`
+	Arch               types.String      `tfsdk:"arch"`
+	CreatedAt          types.String      `tfsdk:"created_at"`
+	ConnectionInfo     types.Object      `tfsdk:"connection_info"`
+	ConsoleUser        types.String      `tfsdk:"console_user"`
+	ConsolePassword    types.String      `tfsdk:"console_password"`
+
+	ShutdownBeforeDelete types.Bool   `tfsdk:"shutdown_before_delete"`
+	ShutdownGracePeriod  types.String `tfsdk:"shutdown_grace_period"`
+	SkipDestroy          types.Bool   `tfsdk:"skip_destroy"`
+
+	Timeouts *TimeoutsModel `tfsdk:"timeouts"`
+}
+
+// VMScheduleModel describes the start/stop schedule of a VM.
+type VMScheduleModel struct {
+	Start    types.String `tfsdk:"start"`
+	Stop     types.String `tfsdk:"stop"`
+	Timezone types.String `tfsdk:"timezone"`
+}
+
+// VMDiskModel describes an additional disk of a VM.
+type VMDiskModel struct {
+	SizeGB types.Int64  `tfsdk:"size_gb"`
+	Label  types.String `tfsdk:"label"`
+	Format types.String `tfsdk:"format"`
+}
+
+// VMWatchdogModel describes the watchdog device of a VM.
+type VMWatchdogModel struct {
+	Model  types.String `tfsdk:"model"`
+	Action types.String `tfsdk:"action"`
 }
 
 func (r *VMResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -76,9 +150,31 @@ func (r *VMResource) Schema(ctx context.Context, req resource.SchemaRequest, res
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
+			"template_id": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "ID of a `slicer_vm_template` to create the VM from. Attributes set on the VM override the template. Changing it replaces the VM.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"source_hostname": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Hostname of an existing VM whose disk is cloned into the new VM. The source VM is left running. Conflicts with `disk_image`. Changing it replaces the VM.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "The hostname of the VM. Known at plan time, so other resources can reference it before the VM exists. Conflicts with `hostname_prefix`. Changing it replaces the VM.",
+			},
+			"hostname_prefix": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Prefix of the generated hostname in place of the host group name, e.g. `db` for `db-1`. Conflicts with `name`. Changing it replaces the VM.",
+			},
 			"hostname": schema.StringAttribute{
 				Computed:            true,
-				MarkdownDescription: "The auto-generated hostname of the VM.",
+				MarkdownDescription: "The hostname of the VM, either `name` or generated by Slicer.",
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
@@ -87,18 +183,53 @@ func (r *VMResource) Schema(ctx context.Context, req resource.SchemaRequest, res
 				Computed:            true,
 				MarkdownDescription: "The IP address of the VM.",
 			},
+			"ip_address": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Static IP address of the VM, e.g. `192.168.137.10`, instead of one assigned from the host group's pool. It must be free and inside `subnet`, or the host group's subnet if `subnet` is not set. Changing it replaces the VM.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"subnet": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "CIDR of the subnet the VM is attached to, e.g. `10.10.0.0/24`, as listed by `data.slicer_subnets`. Defaults to the host group's subnet. Changing it replaces the VM.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
 			"cpus": schema.Int64Attribute{
 				Optional:            true,
 				Computed:            true,
-				MarkdownDescription: "Number of CPUs. Defaults to host group setting.",
+				MarkdownDescription: "Number of CPUs. Defaults to host group setting. Changed in place on Slicer versions that support resizing VMs.",
 				Default:             int64default.StaticInt64(0),
 			},
 			"ram_gb": schema.Int64Attribute{
 				Optional:            true,
 				Computed:            true,
-				MarkdownDescription: "RAM in GB. Defaults to host group setting.",
+				MarkdownDescription: "RAM in GB. Defaults to host group setting. Changed in place on Slicer versions that support resizing VMs. Rounded to the nearest GB when read from the API; see `ram_bytes` for the exact amount.",
 				Default:             int64default.StaticInt64(0),
 			},
+			"ram_bytes": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "The exact amount of RAM allocated to the VM, in bytes.",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"gpu_count": schema.Int64Attribute{
+				Optional:            true,
+				MarkdownDescription: "Number of GPUs to attach to the VM. Must not exceed the host group's `gpu_count`. Changing it replaces the VM.",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"gpu_type": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "GPU model to attach, e.g. `a100`. Any GPU in the host group is used if not set. Requires `gpu_count`. Changing it replaces the VM.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
 			"persistent": schema.BoolAttribute{
 				Optional:            true,
 				Computed:            true,
@@ -118,13 +249,32 @@ func (r *VMResource) Schema(ctx context.Context, req resource.SchemaRequest, res
 				MarkdownDescription: "List of SSH public keys to inject.",
 				ElementType:         types.StringType,
 			},
+			"ssh_key_names": schema.ListAttribute{
+				Optional:            true,
+				MarkdownDescription: "Names of SSH keys stored with `slicer_ssh_key` to inject, in addition to `ssh_keys`. Keys are looked up when the VM is created.",
+				ElementType:         types.StringType,
+			},
 			"userdata": schema.StringAttribute{
 				Optional:            true,
-				MarkdownDescription: "Cloud-init userdata script.",
+				MarkdownDescription: "Cloud-init userdata script. Userdata only runs at first boot, so changing it, or the VM's userdata changing outside of Terraform, replaces the VM. Combined with the `cloud_init` block when both are set.",
+			},
+			"userdata_sha256": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "SHA256 of the userdata the VM was provisioned with, including the userdata built from `cloud_init`, as reported by the Slicer API. Used to detect userdata drift.",
 			},
 			"tags": schema.MapAttribute{
 				Optional:            true,
-				MarkdownDescription: "Tags to apply to the VM (key=value format).",
+				MarkdownDescription: "Tags to apply to the VM (key=value format). Merged over the provider's `default_tags`. Changing them updates the VM in place, and tags added or removed outside of Terraform are shown as drift, except for those matching `ignored_tag_prefixes`.",
+				ElementType:         types.StringType,
+			},
+			"metadata": schema.MapAttribute{
+				Optional:            true,
+				MarkdownDescription: "Key/value metadata served to the VM by the Slicer metadata service, e.g. instance configuration that does not fit in tags. Unlike tags it is not used to select VMs. Changing it updates the VM in place, but cloud-init only reads it at boot.",
+				ElementType:         types.StringType,
+			},
+			"ignored_tag_prefixes": schema.ListAttribute{
+				Optional:            true,
+				MarkdownDescription: "Tag key prefixes added by Slicer that are ignored on read, in addition to the provider's `ignored_tag_prefixes`.",
 				ElementType:         types.StringType,
 			},
 			"secrets": schema.ListAttribute{
@@ -132,14 +282,207 @@ func (r *VMResource) Schema(ctx context.Context, req resource.SchemaRequest, res
 				MarkdownDescription: "List of secret names to inject into the VM.",
 				ElementType:         types.StringType,
 			},
+			"volume_attachments": schema.SetAttribute{
+				Optional:            true,
+				MarkdownDescription: "Names of `slicer_volume` volumes to attach to the VM. Volumes are attached and detached in place, and detached before the VM is destroyed so their data is kept.",
+				ElementType:         types.StringType,
+			},
+			"priority": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "CPU and IO share class of the VM at the hypervisor: `low`, `normal` or `high`. Defaults to `normal`. Changing it updates the VM in place.",
+				Default:             stringdefault.StaticString(vmPriorityNormal),
+			},
+			"power_state": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Whether the VM is `running` or `stopped`. Changing it starts or stops the VM in place; a stopped VM keeps its disk and IP. When not set the power state is only reported, e.g. for VMs with a `schedule`, which conflicts with setting it.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"reverse_dns": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Fully qualified name for the PTR record of the VM's IP, e.g. `mail.example.com`. Changing it updates the record in place.",
+			},
+			"cdrom_image": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "ISO image attached to the VM's virtual CD-ROM drive at boot, e.g. an installer or driver image. Changing it swaps the disc in place and removing it ejects the disc.",
+			},
+			"shutdown_before_delete": schema.BoolAttribute{
+				Optional:            true,
+				MarkdownDescription: "Whether the VM is shut down cleanly before it is deleted, so the guest can flush its disks and stop its services. Defaults to `false`. Like `skip_destroy`, it must be applied before the VM is destroyed to take effect.",
+			},
+			"shutdown_grace_period": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "How long to wait for the VM to stop after the shutdown was requested, e.g. `30s` or `5m`. The VM is deleted when the grace period runs out, whether it stopped or not. Defaults to `2m`.",
+			},
+			"skip_destroy": schema.BoolAttribute{
+				Optional:            true,
+				MarkdownDescription: "Whether destroying the resource only removes the VM from the Terraform state and leaves it running in Slicer, e.g. when handing a VM over to other tooling. This also applies when the VM is replaced. Defaults to `false`.",
+			},
+			"secure_boot": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Boot the VM with UEFI Secure Boot enabled. The host group must support it. Changing it replaces the VM.",
+				Default:             booldefault.StaticBool(false),
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"tpm": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Attach a virtual TPM 2.0 device, e.g. for Windows or measured boot. The host group must support it. Changing it replaces the VM.",
+				Default:             booldefault.StaticBool(false),
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
 			"arch": schema.StringAttribute{
 				Computed:            true,
 				MarkdownDescription: "The architecture of the VM (e.g., 'amd64').",
 			},
+			"console_user": schema.StringAttribute{
+				Computed:            true,
+				Sensitive:           true,
+				MarkdownDescription: "User for emergency console access to the VM. Null unless Slicer issues per-VM console credentials.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"console_password": schema.StringAttribute{
+				Computed:            true,
+				Sensitive:           true,
+				MarkdownDescription: "Password for emergency console access to the VM. Null unless Slicer issues per-VM console credentials.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
 			"created_at": schema.StringAttribute{
 				Computed:            true,
 				MarkdownDescription: "The creation timestamp of the VM.",
 			},
+			"connection_info": schema.SingleNestedAttribute{
+				Computed:            true,
+				MarkdownDescription: "Everything needed to connect to the VM over SSH, for passing to modules or `connection` blocks as a whole.",
+				Attributes: map[string]schema.Attribute{
+					"type": schema.StringAttribute{
+						Computed:            true,
+						MarkdownDescription: "Connection type, always `ssh`.",
+					},
+					"host": schema.StringAttribute{
+						Computed:            true,
+						MarkdownDescription: "Address to connect to (the IPv4 address of the VM).",
+					},
+					"hostname": schema.StringAttribute{
+						Computed:            true,
+						MarkdownDescription: "The hostname of the VM.",
+					},
+					"ip": schema.StringAttribute{
+						Computed:            true,
+						MarkdownDescription: "The IPv4 address of the VM.",
+					},
+					"ipv6": schema.StringAttribute{
+						Computed:            true,
+						MarkdownDescription: "The IPv6 address of the VM, if any.",
+					},
+					"user": schema.StringAttribute{
+						Computed:            true,
+						MarkdownDescription: "SSH user. Defaults to `ubuntu` when the API does not report one.",
+					},
+					"port": schema.Int64Attribute{
+						Computed:            true,
+						MarkdownDescription: "SSH port. Defaults to 22 when the API does not report one.",
+					},
+					"host_keys": schema.ListAttribute{
+						Computed:            true,
+						MarkdownDescription: "Public SSH host keys of the VM, for host key verification.",
+						ElementType:         types.StringType,
+					},
+				},
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"timeouts": timeoutsBlock(),
+			"schedule": schema.SingleNestedBlock{
+				MarkdownDescription: "Powers the VM on and off on a schedule stored in Slicer, e.g. to stop development VMs outside working hours. Changing or removing the schedule updates the VM in place.",
+				Attributes: map[string]schema.Attribute{
+					"start": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "Cron expression for when the VM is started (e.g., '0 8 * * 1-5').",
+					},
+					"stop": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "Cron expression for when the VM is stopped (e.g., '0 19 * * 1-5').",
+					},
+					"timezone": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "IANA time zone the expressions are evaluated in (e.g., 'Europe/Berlin'). Defaults to UTC.",
+					},
+				},
+			},
+			"watchdog": schema.SingleNestedBlock{
+				MarkdownDescription: "Attaches a virtual watchdog device so the hypervisor recovers a hung guest automatically. The guest must run a watchdog daemon. Changing or removing the block replaces the VM.",
+				PlanModifiers: []planmodifier.Object{
+					objectplanmodifier.RequiresReplace(),
+				},
+				Attributes: map[string]schema.Attribute{
+					"model": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "Watchdog device model (e.g., 'i6300esb'). Defaults to the hypervisor's default model.",
+					},
+					"action": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "What the hypervisor does when the watchdog fires: `reset` or `poweroff`.",
+					},
+				},
+			},
+			"additional_disks": schema.ListNestedBlock{
+				MarkdownDescription: "Data disks created with the VM in addition to its root disk, e.g. for database files. They appear in the guest in the order given, after the root disk, and are deleted with the VM. Disks cannot be attached to a running VM, so changing the blocks replaces the VM; use `slicer_volume` for disks that outlive the VM.",
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"size_gb": schema.Int64Attribute{
+							Required:            true,
+							MarkdownDescription: "Size of the disk in GB.",
+						},
+						"label": schema.StringAttribute{
+							Optional:            true,
+							MarkdownDescription: "Filesystem label, so the disk can be mounted from `/dev/disk/by-label` regardless of its device name. Requires `format`.",
+						},
+						"format": schema.StringAttribute{
+							Optional:            true,
+							MarkdownDescription: "Filesystem created on the disk: `ext4` or `xfs`. If not set, the disk is left unformatted.",
+						},
+					},
+				},
+			},
+			"cloud_init": vmCloudInitBlock(),
+			"wait_for": schema.SingleNestedBlock{
+				MarkdownDescription: "Blocks creation until the VM is ready, so dependent `slicer_exec` and `slicer_file` resources do not run against a VM that is still booting. The VM agent is always waited for; the attributes add further conditions. Only checked when the VM is created. If a condition is not met in time the VM is kept and marked tainted.",
+				Attributes: map[string]schema.Attribute{
+					"ssh": schema.BoolAttribute{
+						Optional:            true,
+						MarkdownDescription: "Wait until the SSH port in `connection_info` accepts TCP connections from where Terraform runs.",
+					},
+					"cloud_init": schema.BoolAttribute{
+						Optional:            true,
+						MarkdownDescription: "Wait until cloud-init has finished. Creation fails if cloud-init reports an error.",
+					},
+					"command": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "Shell script run as root on the VM until it exits successfully (e.g., 'systemctl is-active docker').",
+					},
+					"timeout": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "How long to wait for all conditions (e.g., '10m'). Defaults to '5m'.",
+					},
+				},
+			},
 		},
 	}
 }
@@ -159,217 +502,1857 @@ func (r *VMResource) Configure(ctx context.Context, req resource.ConfigureReques
 	}
 
 	r.client = providerData.Client
+	r.tokenScopes = providerData.TokenScopes
+	r.ignoredTagPrefixes = providerData.IgnoredTagPrefixes
+	r.defaultTags = providerData.DefaultTags
+	r.serverInfo = providerData.ServerInfo
+	r.vms = providerData.VMs
 }
 
-func (r *VMResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
-	var data VMResourceModel
+func (r *VMResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var priority, powerState, sourceHostname, diskImage, reverseDNS, name, hostnamePrefix, gpuType, shutdownGracePeriod, ipAddress, subnet types.String
+	var gpuCount types.Int64
+	var metadata types.Map
+	var schedule *VMScheduleModel
+	var watchdog *VMWatchdogModel
+	var waitFor *VMWaitForModel
+	var additionalDisks []VMDiskModel
+	var cloudInit *VMCloudInitModel
+	var timeouts *TimeoutsModel
 
-	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("priority"), &priority)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("power_state"), &powerState)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("timeouts"), &timeouts)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("wait_for"), &waitFor)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("watchdog"), &watchdog)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("reverse_dns"), &reverseDNS)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("schedule"), &schedule)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("source_hostname"), &sourceHostname)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("disk_image"), &diskImage)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("shutdown_grace_period"), &shutdownGracePeriod)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("additional_disks"), &additionalDisks)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("name"), &name)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("hostname_prefix"), &hostnamePrefix)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("gpu_count"), &gpuCount)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("gpu_type"), &gpuType)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("cloud_init"), &cloudInit)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("ip_address"), &ipAddress)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("subnet"), &subnet)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("metadata"), &metadata)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Build create request
-	createReq := slicer.SlicerCreateNodeRequest{
-		Persistent: data.Persistent.ValueBool(),
+	validateCloudInit(cloudInit, &resp.Diagnostics)
+
+	if _, ok := metadata.Elements()[""]; ok {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("metadata"),
+			"Invalid Metadata Key",
+			"metadata keys must not be empty.",
+		)
 	}
+	validateVMAddressing(ipAddress, subnet, &resp.Diagnostics)
 
-	if !data.CPUs.IsNull() && data.CPUs.ValueInt64() > 0 {
-		createReq.CPUs = int(data.CPUs.ValueInt64())
+	if !gpuCount.IsNull() && !gpuCount.IsUnknown() && gpuCount.ValueInt64() < 1 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("gpu_count"),
+			"Invalid GPU Count",
+			fmt.Sprintf("gpu_count must be at least 1, got: %d", gpuCount.ValueInt64()),
+		)
 	}
 
-	if !data.RamGB.IsNull() && data.RamGB.ValueInt64() > 0 {
-		createReq.RamBytes = slicer.GiB(data.RamGB.ValueInt64())
+	if !gpuType.IsNull() && gpuCount.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("gpu_type"),
+			"Missing GPU Count",
+			"gpu_type requires gpu_count to be set.",
+		)
 	}
 
-	if !data.DiskImage.IsNull() {
-		createReq.DiskImage = data.DiskImage.ValueString()
+	if !name.IsNull() && !hostnamePrefix.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("name"),
+			"Conflicting Attributes",
+			"Only one of 'name' or 'hostname_prefix' can be specified.",
+		)
 	}
 
-	if !data.ImportUser.IsNull() {
-		createReq.ImportUser = data.ImportUser.ValueString()
+	validateHostnameLabel(name, path.Root("name"), &resp.Diagnostics)
+	validateTimeouts(timeouts, &resp.Diagnostics)
+	validateHostnameLabel(hostnamePrefix, path.Root("hostname_prefix"), &resp.Diagnostics)
+
+	if !reverseDNS.IsNull() && !reverseDNS.IsUnknown() && !dnsNamePattern.MatchString(reverseDNS.ValueString()) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("reverse_dns"),
+			"Invalid Reverse DNS Name",
+			fmt.Sprintf("reverse_dns must be a fully qualified domain name such as 'mail.example.com', got: %s", reverseDNS.ValueString()),
+		)
 	}
 
-	if !data.SSHKeys.IsNull() {
-		var sshKeys []string
-		resp.Diagnostics.Append(data.SSHKeys.ElementsAs(ctx, &sshKeys, false)...)
-		if resp.Diagnostics.HasError() {
-			return
+	if !sourceHostname.IsNull() && !diskImage.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("source_hostname"),
+			"Conflicting Attributes",
+			"Only one of 'source_hostname' or 'disk_image' can be specified.",
+		)
+	}
+
+	if !priority.IsNull() && !priority.IsUnknown() {
+		switch priority.ValueString() {
+		case vmPriorityLow, vmPriorityNormal, vmPriorityHigh:
+		default:
+			resp.Diagnostics.AddAttributeError(
+				path.Root("priority"),
+				"Invalid Priority Value",
+				fmt.Sprintf("priority must be one of 'low', 'normal' or 'high', got: %s", priority.ValueString()),
+			)
 		}
-		createReq.SSHKeys = sshKeys
 	}
 
-	if !data.Userdata.IsNull() {
-		createReq.Userdata = data.Userdata.ValueString()
+	if !powerState.IsNull() && !powerState.IsUnknown() {
+		switch powerState.ValueString() {
+		case slicer.VMPowerStateRunning, slicer.VMPowerStateStopped:
+		default:
+			resp.Diagnostics.AddAttributeError(
+				path.Root("power_state"),
+				"Invalid Power State",
+				fmt.Sprintf("power_state must be one of 'running' or 'stopped', got: %s", powerState.ValueString()),
+			)
+		}
 	}
 
-	if !data.Tags.IsNull() {
-		var tags map[string]string
-		resp.Diagnostics.Append(data.Tags.ElementsAs(ctx, &tags, false)...)
-		if resp.Diagnostics.HasError() {
-			return
+	// A schedule changes the power state, which would show up as drift
+	if !powerState.IsNull() && schedule != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("power_state"),
+			"Conflicting Attributes",
+			"power_state cannot be set together with a schedule block.",
+		)
+	}
+
+	if schedule != nil {
+		if schedule.Start.IsNull() && schedule.Stop.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("schedule"),
+				"Invalid Schedule",
+				"At least one of start or stop must be set.",
+			)
 		}
-		for k, v := range tags {
-			createReq.Tags = append(createReq.Tags, fmt.Sprintf("%s=%s", k, v))
+
+		validateCronAttribute(schedule.Start, path.Root("schedule").AtName("start"), &resp.Diagnostics)
+		validateCronAttribute(schedule.Stop, path.Root("schedule").AtName("stop"), &resp.Diagnostics)
+	}
+
+	if watchdog != nil {
+		switch {
+		case watchdog.Action.IsNull():
+			resp.Diagnostics.AddAttributeError(
+				path.Root("watchdog").AtName("action"),
+				"Missing Watchdog Action",
+				"action must be set in the watchdog block.",
+			)
+		case watchdog.Action.IsUnknown():
+		case watchdog.Action.ValueString() != vmWatchdogReset && watchdog.Action.ValueString() != vmWatchdogPoweroff:
+			resp.Diagnostics.AddAttributeError(
+				path.Root("watchdog").AtName("action"),
+				"Invalid Watchdog Action",
+				fmt.Sprintf("action must be one of 'reset' or 'poweroff', got: %s", watchdog.Action.ValueString()),
+			)
 		}
 	}
 
-	if !data.Secrets.IsNull() {
-		var secrets []string
-		resp.Diagnostics.Append(data.Secrets.ElementsAs(ctx, &secrets, false)...)
-		if resp.Diagnostics.HasError() {
-			return
+	if waitFor != nil && !waitFor.Timeout.IsNull() && !waitFor.Timeout.IsUnknown() {
+		if d, err := time.ParseDuration(waitFor.Timeout.ValueString()); err != nil || d <= 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("wait_for").AtName("timeout"),
+				"Invalid Duration",
+				fmt.Sprintf("timeout must be a positive duration such as '30s' or '5m', got: %s", waitFor.Timeout.ValueString()),
+			)
 		}
-		createReq.Secrets = secrets
 	}
 
-	tflog.Debug(ctx, "Creating VM", map[string]interface{}{
-		"host_group": data.HostGroup.ValueString(),
-	})
+	for i, disk := range additionalDisks {
+		diskPath := path.Root("additional_disks").AtListIndex(i)
 
-	// Create the VM
-	result, err := r.client.CreateVM(ctx, data.HostGroup.ValueString(), createReq)
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create VM: %s", err))
-		return
-	}
+		if !disk.SizeGB.IsUnknown() && disk.SizeGB.ValueInt64() < 1 {
+			resp.Diagnostics.AddAttributeError(
+				diskPath.AtName("size_gb"),
+				"Invalid Disk Size",
+				fmt.Sprintf("size_gb must be at least 1, got: %d", disk.SizeGB.ValueInt64()),
+			)
+		}
 
-	// Parse IP (remove CIDR notation if present)
-	ip := result.IP
-	if strings.Contains(ip, "/") {
-		ip = strings.Split(ip, "/")[0]
+		if !disk.Format.IsNull() && !disk.Format.IsUnknown() && disk.Format.ValueString() != vmDiskFormatExt4 && disk.Format.ValueString() != vmDiskFormatXFS {
+			resp.Diagnostics.AddAttributeError(
+				diskPath.AtName("format"),
+				"Invalid Disk Format",
+				fmt.Sprintf("format must be one of 'ext4' or 'xfs', got: %s", disk.Format.ValueString()),
+			)
+		}
+
+		if !disk.Label.IsNull() && disk.Format.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				diskPath.AtName("label"),
+				"Missing Attribute",
+				"format is required to label a disk, since the label belongs to its filesystem.",
+			)
+		}
 	}
 
-	// Set computed values
-	data.ID = types.StringValue(result.Hostname)
-	data.Hostname = types.StringValue(result.Hostname)
-	data.IP = types.StringValue(ip)
-	data.Arch = types.StringValue(result.Arch)
-	data.CreatedAt = types.StringValue(result.CreatedAt.Format(time.RFC3339))
+	if !shutdownGracePeriod.IsNull() && !shutdownGracePeriod.IsUnknown() {
+		if d, err := time.ParseDuration(shutdownGracePeriod.ValueString()); err != nil || d <= 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("shutdown_grace_period"),
+				"Invalid Duration",
+				fmt.Sprintf("shutdown_grace_period must be a positive duration such as '30s' or '5m', got: %s", shutdownGracePeriod.ValueString()),
+			)
+		}
+	}
+}
 
-	tflog.Trace(ctx, "Created VM", map[string]interface{}{
-		"hostname": result.Hostname,
-		"ip":       ip,
-	})
+func (r *VMResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	checkScope(req, r.tokenScopes, scopeVMsWrite, "slicer_vm", &resp.Diagnostics)
 
-	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
-}
+	// Nothing to do on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
 
-func (r *VMResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
-	var data VMResourceModel
+	r.planHostname(ctx, req, resp)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	r.planResize(ctx, req, resp)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// List all VMs and find ours
-	vms, err := r.client.ListVMs(ctx)
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list VMs: %s", err))
+	var priority types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("priority"), &priority)...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	var found *slicer.SlicerNode
-	for _, vm := range vms {
-		if vm.Hostname == data.Hostname.ValueString() {
-			found = &vm
-			break
-		}
+	if !priority.IsUnknown() && priority.ValueString() != vmPriorityNormal {
+		checkCapability(r.serverInfo, capabilityVMPriority, path.Root("priority"), &resp.Diagnostics)
 	}
 
-	if found == nil {
-		// VM was deleted outside of Terraform
-		resp.State.RemoveResource(ctx)
+	var schedule *VMScheduleModel
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("schedule"), &schedule)...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Parse IP (remove CIDR notation if present)
-	ip := found.IP
-	if strings.Contains(ip, "/") {
-		ip = strings.Split(ip, "/")[0]
+	if schedule != nil {
+		checkCapability(r.serverInfo, capabilityVMSchedule, path.Root("schedule"), &resp.Diagnostics)
 	}
 
-	// Update state with current values
-	data.IP = types.StringValue(ip)
-	data.Arch = types.StringValue(found.Arch)
-	data.CreatedAt = types.StringValue(found.CreatedAt.Format(time.RFC3339))
+	var metadata types.Map
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("metadata"), &metadata)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	if found.CPUs > 0 {
-		data.CPUs = types.Int64Value(int64(found.CPUs))
+	if !metadata.IsNull() && !metadata.IsUnknown() && len(metadata.Elements()) > 0 {
+		checkCapability(r.serverInfo, capabilityVMMetadata, path.Root("metadata"), &resp.Diagnostics)
 	}
-	if found.RamBytes > 0 {
-		data.RamGB = types.Int64Value(found.RamBytes / (1024 * 1024 * 1024))
+
+	var powerState types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("power_state"), &powerState)...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	// Parse tags
-	if len(found.Tags) > 0 {
-		tags := make(map[string]string)
-		for _, tag := range found.Tags {
-			parts := strings.SplitN(tag, "=", 2)
-			if len(parts) == 2 {
-				tags[parts[0]] = parts[1]
-			}
-		}
-		tagsValue, diags := types.MapValueFrom(ctx, types.StringType, tags)
-		resp.Diagnostics.Append(diags...)
-		if !resp.Diagnostics.HasError() {
-			data.Tags = tagsValue
-		}
+	if powerState.ValueString() == slicer.VMPowerStateStopped {
+		checkCapability(r.serverInfo, capabilityVMPower, path.Root("power_state"), &resp.Diagnostics)
 	}
 
-	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
-}
+	var shutdownBeforeDelete types.Bool
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("shutdown_before_delete"), &shutdownBeforeDelete)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-func (r *VMResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var data VMResourceModel
+	if shutdownBeforeDelete.ValueBool() {
+		checkCapability(r.serverInfo, capabilityVMPower, path.Root("shutdown_before_delete"), &resp.Diagnostics)
+	}
 
-	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	var watchdog *VMWatchdogModel
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("watchdog"), &watchdog)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Slicer doesn't support updating VMs in place
-	// Most changes require replacement (handled by RequiresReplace plan modifier)
-	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
-}
-
-func (r *VMResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	var data VMResourceModel
+	if watchdog != nil {
+		checkCapability(r.serverInfo, capabilityVMWatchdog, path.Root("watchdog"), &resp.Diagnostics)
+	}
 
-	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	var templateID types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("template_id"), &templateID)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	tflog.Debug(ctx, "Deleting VM", map[string]interface{}{
-		"hostname":   data.Hostname.ValueString(),
-		"host_group": data.HostGroup.ValueString(),
-	})
+	if !templateID.IsNull() {
+		checkCapability(r.serverInfo, capabilityVMTemplates, path.Root("template_id"), &resp.Diagnostics)
+	}
 
-	_, err := r.client.DeleteVM(ctx, data.HostGroup.ValueString(), data.Hostname.ValueString())
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete VM: %s", err))
+	var sourceHostname types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("source_hostname"), &sourceHostname)...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	tflog.Trace(ctx, "Deleted VM", map[string]interface{}{
-		"hostname": data.Hostname.ValueString(),
-	})
+	if !sourceHostname.IsNull() {
+		checkCapability(r.serverInfo, capabilityVMClone, path.Root("source_hostname"), &resp.Diagnostics)
+	}
+
+	var sshKeyNames types.List
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("ssh_key_names"), &sshKeyNames)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !sshKeyNames.IsNull() {
+		checkCapability(r.serverInfo, capabilitySSHKeys, path.Root("ssh_key_names"), &resp.Diagnostics)
+	}
+
+	var reverseDNS types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("reverse_dns"), &reverseDNS)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !reverseDNS.IsNull() {
+		checkCapability(r.serverInfo, capabilityReverseDNS, path.Root("reverse_dns"), &resp.Diagnostics)
+	}
+
+	var cdromImage types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("cdrom_image"), &cdromImage)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !cdromImage.IsNull() {
+		checkCapability(r.serverInfo, capabilityVMCDROM, path.Root("cdrom_image"), &resp.Diagnostics)
+	}
+
+	var volumeAttachments types.Set
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("volume_attachments"), &volumeAttachments)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !volumeAttachments.IsNull() && !volumeAttachments.IsUnknown() && len(volumeAttachments.Elements()) > 0 {
+		checkCapability(r.serverInfo, capabilityVolumes, path.Root("volume_attachments"), &resp.Diagnostics)
+	}
+
+	// Firmware options, GPUs and disks can only be set when the VM is created
+	if req.State.Raw.IsNull() {
+		var additionalDisks []VMDiskModel
+		resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("additional_disks"), &additionalDisks)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		if len(additionalDisks) > 0 {
+			checkCapability(r.serverInfo, capabilityVMDisks, path.Root("additional_disks"), &resp.Diagnostics)
+		}
+
+		r.checkFirmware(ctx, req, resp)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		r.checkGPUs(ctx, req, resp)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		r.checkAddressing(ctx, req, resp)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	var userdataAttr types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("userdata"), &userdataAttr)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var cloudInit *VMCloudInitModel
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("cloud_init"), &cloudInit)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	userdataPath := path.Root("userdata")
+	if cloudInit != nil {
+		userdataPath = path.Root("cloud_init")
+	}
+
+	userdata, known, diags := vmUserdata(ctx, cloudInit, userdataAttr)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !known {
+		if !req.State.Raw.IsNull() {
+			resp.RequiresReplace = append(resp.RequiresReplace, userdataPath)
+		}
+		return
+	}
+
+	hash := userdataHash(userdata)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("userdata_sha256"), hash)...)
+
+	if req.State.Raw.IsNull() {
+		return
+	}
+
+	// Compare against the hash in state, which Read refreshes from the API,
+	// so both config changes and out-of-band changes are caught
+	var stateHash types.String
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("userdata_sha256"), &stateHash)...)
+	if !stateHash.IsNull() && stateHash.ValueString() != hash {
+		resp.RequiresReplace = append(resp.RequiresReplace, userdataPath)
+	}
+}
+
+func (r *VMResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data VMResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Lookups after this point must see the new VM
+	defer r.vms.invalidate()
+
+	ctx, cancel := withTimeout(ctx, data.Timeouts, timeoutCreate)
+	defer cancel()
+
+	// Build create request
+	createReq := slicer.SlicerCreateNodeRequest{
+		Hostname:       data.Name.ValueString(),
+		HostnamePrefix: data.HostnamePrefix.ValueString(),
+		Persistent:     data.Persistent.ValueBool(),
+		Priority:       data.Priority.ValueString(),
+		Template:       data.TemplateID.ValueString(),
+		Source:         data.SourceHostname.ValueString(),
+		ReverseDNS:     data.ReverseDNS.ValueString(),
+		CDROMImage:     data.CDROMImage.ValueString(),
+		IP:             data.IPAddress.ValueString(),
+		Subnet:         data.Subnet.ValueString(),
+	}
+
+	if data.SecureBoot.ValueBool() || data.TPM.ValueBool() {
+		createReq.Firmware = &slicer.SlicerFirmwareOptions{
+			SecureBoot: data.SecureBoot.ValueBool(),
+			TPM:        data.TPM.ValueBool(),
+		}
+	}
+
+	if !data.CPUs.IsNull() && data.CPUs.ValueInt64() > 0 {
+		createReq.CPUs = int(data.CPUs.ValueInt64())
+	}
+
+	if !data.RamGB.IsNull() && data.RamGB.ValueInt64() > 0 {
+		createReq.RamBytes = slicer.GiB(data.RamGB.ValueInt64())
+	}
+
+	if !data.GPUCount.IsNull() {
+		createReq.GPUCount = int(data.GPUCount.ValueInt64())
+		createReq.GPUType = data.GPUType.ValueString()
+	}
+
+	if !data.DiskImage.IsNull() {
+		createReq.DiskImage = data.DiskImage.ValueString()
+	}
+
+	if !data.ImportUser.IsNull() {
+		createReq.ImportUser = data.ImportUser.ValueString()
+	}
+
+	if !data.SSHKeys.IsNull() {
+		var sshKeys []string
+		resp.Diagnostics.Append(data.SSHKeys.ElementsAs(ctx, &sshKeys, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		createReq.SSHKeys = sshKeys
+	}
+
+	if !data.SSHKeyNames.IsNull() {
+		var names []string
+		resp.Diagnostics.Append(data.SSHKeyNames.ElementsAs(ctx, &names, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		sshKeys, err := r.lookupSSHKeys(ctx, names)
+		if errors.Is(err, slicer.ErrNotSupported) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("ssh_key_names"),
+				"SSH Keys Not Supported",
+				"The Slicer API does not support storing SSH keys. Set the keys in ssh_keys instead.",
+			)
+			return
+		}
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("ssh_key_names"), "Client Error", fmt.Sprintf("Unable to look up SSH keys: %s", err))
+			return
+		}
+		createReq.SSHKeys = append(createReq.SSHKeys, sshKeys...)
+	}
+
+	userdata, _, diags := vmUserdata(ctx, data.CloudInit, data.Userdata)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	createReq.Userdata = userdata
+
+	var tags map[string]string
+	if !data.Tags.IsNull() {
+		resp.Diagnostics.Append(data.Tags.ElementsAs(ctx, &tags, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	for k, v := range mergeTags(r.defaultTags, tags) {
+		createReq.Tags = append(createReq.Tags, fmt.Sprintf("%s=%s", k, v))
+	}
+
+	if !data.Metadata.IsNull() {
+		resp.Diagnostics.Append(data.Metadata.ElementsAs(ctx, &createReq.Metadata, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	if !data.Secrets.IsNull() {
+		var secrets []string
+		resp.Diagnostics.Append(data.Secrets.ElementsAs(ctx, &secrets, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		createReq.Secrets = secrets
+	}
+
+	createReq.Schedule = scheduleFromModel(data.Schedule)
+
+	if data.Watchdog != nil {
+		createReq.Watchdog = &slicer.SlicerWatchdog{
+			Model:  data.Watchdog.Model.ValueString(),
+			Action: data.Watchdog.Action.ValueString(),
+		}
+	}
+
+	for _, disk := range data.AdditionalDisks {
+		createReq.AdditionalDisks = append(createReq.AdditionalDisks, slicer.SlicerDisk{
+			SizeBytes: slicer.GiB(disk.SizeGB.ValueInt64()),
+			Label:     disk.Label.ValueString(),
+			Format:    disk.Format.ValueString(),
+		})
+	}
+
+	tflog.Debug(ctx, "Creating VM", map[string]interface{}{
+		"host_group": data.HostGroup.ValueString(),
+	})
+
+	// Create the VM
+	result, err := r.client.CreateVM(ctx, data.HostGroup.ValueString(), createReq)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create VM: %s", err))
+		return
+	}
+
+	// Older APIs ignore the requested hostname; the VM must not be kept
+	// under a name other than the one planned
+	if !data.Name.IsNull() && result.Hostname != data.Name.ValueString() {
+		if _, err := r.client.DeleteVM(ctx, data.HostGroup.ValueString(), result.Hostname); err != nil {
+			tflog.Warn(ctx, "Unable to delete VM created with an unexpected hostname", map[string]interface{}{
+				"hostname": result.Hostname,
+				"error":    err.Error(),
+			})
+		}
+		resp.Diagnostics.AddAttributeError(
+			path.Root("name"),
+			"VM Naming Not Supported",
+			fmt.Sprintf("The Slicer API created the VM as %s instead of %s, so it does not support choosing hostnames. The VM was deleted.", result.Hostname, data.Name.ValueString()),
+		)
+		return
+	}
+
+	// Parse IP (remove CIDR notation if present)
+	ip := result.IP
+	if strings.Contains(ip, "/") {
+		ip = strings.Split(ip, "/")[0]
+	}
+
+	// Set computed values
+	data.ID = types.StringValue(result.Hostname)
+	data.Hostname = types.StringValue(result.Hostname)
+	data.IP = types.StringValue(ip)
+	data.Arch = types.StringValue(result.Arch)
+	data.CreatedAt = types.StringValue(result.CreatedAt.Format(time.RFC3339))
+
+	// The create response has no sizing; the host group default is filled in on read
+	data.RamBytes = types.Int64Null()
+	if data.RamGB.ValueInt64() > 0 {
+		data.RamBytes = types.Int64Value(slicer.GiB(data.RamGB.ValueInt64()))
+	}
+
+	connectionInfo, diags := connectionInfoValue(ctx, result.Hostname, ip, result.SSHAccess)
+	resp.Diagnostics.Append(diags...)
+	data.ConnectionInfo = connectionInfo
+
+	data.ConsoleUser, data.ConsolePassword = consoleCredentials(result.ConsoleAccess)
+
+	tflog.Trace(ctx, "Created VM", map[string]interface{}{
+		"hostname": result.Hostname,
+		"ip":       ip,
+	})
+
+	// VMs are created running and stopped last, so state records a running
+	// VM until the stop succeeds
+	stop := data.PowerState.ValueString() == slicer.VMPowerStateStopped
+	data.PowerState = types.StringValue(slicer.VMPowerStateRunning)
+
+	// The VM is kept in state with the volumes that could be attached
+	if !data.VolumeAttachments.IsNull() {
+		var volumes []string
+		resp.Diagnostics.Append(data.VolumeAttachments.ElementsAs(ctx, &volumes, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		attached, err := r.updateVolumeAttachments(ctx, data.HostGroup.ValueString(), result.Hostname, volumes, nil)
+		data.VolumeAttachments = volumeAttachmentsValue(ctx, attached, data.VolumeAttachments, &resp.Diagnostics)
+		if err != nil {
+			addVolumeAttachmentError(err, &resp.Diagnostics)
+			resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+			return
+		}
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The VM is already in state, so a failed wait taints it rather than
+	// losing track of it
+	if data.WaitFor != nil {
+		if err := r.waitForVM(ctx, result.Hostname, ip, sshPort(result.SSHAccess), data.WaitFor); err != nil {
+			resp.Diagnostics.AddError(
+				"VM Not Ready",
+				fmt.Sprintf("VM %s was created but did not become ready: %s", result.Hostname, err),
+			)
+			return
+		}
+	}
+
+	if stop {
+		r.setPowerState(ctx, &data, slicer.VMPowerStateStopped, &resp.Diagnostics)
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	}
+}
+
+func (r *VMResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data VMResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := withTimeout(ctx, data.Timeouts, timeoutRead)
+	defer cancel()
+
+	// Read the VM directly rather than from the shared list, which may
+	// predate changes made by other resources in this run
+	found, err := r.client.GetVM(ctx, data.Hostname.ValueString())
+	if errors.Is(err, slicer.ErrVMNotFound) {
+		// VM was deleted outside of Terraform
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read VM: %s", err))
+		return
+	}
+
+	// Parse IP (remove CIDR notation if present)
+	ip := found.IP
+	if strings.Contains(ip, "/") {
+		ip = strings.Split(ip, "/")[0]
+	}
+
+	// Update state with current values
+	data.IP = types.StringValue(ip)
+	if !data.IPAddress.IsNull() && ip != "" && data.IPAddress.ValueString() != ip {
+		// The address changed outside of Terraform, so the VM is replaced
+		data.IPAddress = types.StringValue(ip)
+	}
+	data.Arch = types.StringValue(found.Arch)
+	data.CreatedAt = types.StringValue(found.CreatedAt.Format(time.RFC3339))
+
+	connectionInfo, diags := connectionInfoValue(ctx, found.Hostname, ip, found.SSHAccess)
+	resp.Diagnostics.Append(diags...)
+	data.ConnectionInfo = connectionInfo
+
+	// Credentials may only be returned on create; keep them unless they were rotated
+	if found.ConsoleUser != "" || found.ConsolePassword != "" {
+		data.ConsoleUser, data.ConsolePassword = consoleCredentials(found.ConsoleAccess)
+	}
+
+	if found.CPUs > 0 {
+		data.CPUs = types.Int64Value(int64(found.CPUs))
+	}
+	if found.RamBytes > 0 {
+		data.RamGB = types.Int64Value(slicer.BytesToGiB(found.RamBytes))
+		data.RamBytes = types.Int64Value(found.RamBytes)
+	}
+
+	if found.UserdataSHA256 != "" {
+		if found.UserdataSHA256 != data.UserdataSHA256.ValueString() {
+			tflog.Warn(ctx, "VM userdata differs from the userdata in state", map[string]interface{}{
+				"hostname": found.Hostname,
+			})
+		}
+		data.UserdataSHA256 = types.StringValue(found.UserdataSHA256)
+	}
+
+	if found.Priority != "" {
+		data.Priority = types.StringValue(found.Priority)
+	}
+
+	if found.PowerState != "" {
+		data.PowerState = types.StringValue(found.PowerState)
+	} else if data.PowerState.IsNull() {
+		// Imported VMs are assumed to be running when the API does not say
+		data.PowerState = types.StringValue(slicer.VMPowerStateRunning)
+	}
+
+	data.Schedule = scheduleToModel(found.Schedule)
+
+	if found.Watchdog != nil {
+		watchdog := &VMWatchdogModel{
+			Model:  optionalString(found.Watchdog.Model),
+			Action: types.StringValue(found.Watchdog.Action),
+		}
+		// Keep the model unset when the hypervisor default was used
+		if data.Watchdog == nil || data.Watchdog.Model.IsNull() {
+			watchdog.Model = types.StringNull()
+		}
+		data.Watchdog = watchdog
+	}
+
+	if len(found.AdditionalDisks) > 0 {
+		data.AdditionalDisks = disksToModel(found.AdditionalDisks, data.AdditionalDisks)
+	}
+
+	if found.Template != "" {
+		data.TemplateID = types.StringValue(found.Template)
+	}
+
+	if found.ReverseDNS != "" {
+		data.ReverseDNS = types.StringValue(found.ReverseDNS)
+	}
+
+	if found.CDROMImage != "" {
+		data.CDROMImage = types.StringValue(found.CDROMImage)
+	}
+
+	if found.Firmware != nil {
+		data.SecureBoot = types.BoolValue(found.Firmware.SecureBoot)
+		data.TPM = types.BoolValue(found.Firmware.TPM)
+	}
+
+	ignoredPrefixes, diags := r.tagPrefixesToIgnore(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Default tags set on the VM itself stay in tags
+	var stateTags map[string]string
+	if !data.Tags.IsNull() {
+		resp.Diagnostics.Append(data.Tags.ElementsAs(ctx, &stateTags, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// Parse tags, dropping the ones managed by Slicer and the provider's
+	// default tags
+	tags := make(map[string]string)
+	for _, tag := range found.Tags {
+		parts := strings.SplitN(tag, "=", 2)
+		if len(parts) != 2 || hasAnyPrefix(parts[0], ignoredPrefixes) {
+			continue
+		}
+		if v, ok := r.defaultTags[parts[0]]; ok && v == parts[1] {
+			if _, ok := stateTags[parts[0]]; !ok {
+				continue
+			}
+		}
+		tags[parts[0]] = parts[1]
+	}
+	// Tags removed on the VM show up as drift, unless tags were never set
+	if len(tags) > 0 || !data.Tags.IsNull() {
+		tagsValue, diags := types.MapValueFrom(ctx, types.StringType, tags)
+		resp.Diagnostics.Append(diags...)
+		if !resp.Diagnostics.HasError() {
+			data.Tags = tagsValue
+		}
+	}
+
+	// Metadata changed on the VM shows up as drift when the API reports it
+	if found.Metadata != nil && (len(found.Metadata) > 0 || !data.Metadata.IsNull()) {
+		metadata, diags := types.MapValueFrom(ctx, types.StringType, found.Metadata)
+		resp.Diagnostics.Append(diags...)
+		if !resp.Diagnostics.HasError() {
+			data.Metadata = metadata
+		}
+	}
+
+	// Volumes attached outside of Terraform are only tracked once
+	// volume_attachments is configured
+	if !data.VolumeAttachments.IsNull() {
+		volumes, err := r.client.ListVolumes(ctx)
+		if err != nil && !errors.Is(err, slicer.ErrNotSupported) {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list volumes: %s", err))
+			return
+		}
+
+		if err == nil {
+			var attached []string
+			for _, volume := range volumes {
+				if volume.AttachedTo == found.Hostname {
+					attached = append(attached, volume.Name)
+				}
+			}
+			data.VolumeAttachments = volumeAttachmentsValue(ctx, attached, data.VolumeAttachments, &resp.Diagnostics)
+		}
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *VMResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data, state VMResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	defer r.vms.invalidate()
+
+	ctx, cancel := withTimeout(ctx, data.Timeouts, timeoutUpdate)
+	defer cancel()
+
+	// Values only the API can change stay as they are in state
+	data.IP = state.IP
+	data.Arch = state.Arch
+	data.CreatedAt = state.CreatedAt
+	data.RamBytes = state.RamBytes
+	data.ConnectionInfo = state.ConnectionInfo
+	data.ConsoleUser = state.ConsoleUser
+	data.ConsolePassword = state.ConsolePassword
+
+	// A stopped VM is started before anything else changes and stopped after
+	powerState := data.PowerState.ValueString()
+	data.PowerState = state.PowerState
+	if powerState == slicer.VMPowerStateRunning && !data.PowerState.Equal(types.StringValue(powerState)) {
+		r.setPowerState(ctx, &data, powerState, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	var updateReq slicer.SlicerUpdateNodeRequest
+	var changed []path.Path
+	if !data.Priority.Equal(state.Priority) {
+		updateReq.Priority = data.Priority.ValueString()
+		changed = append(changed, path.Root("priority"))
+	}
+	if !data.ReverseDNS.Equal(state.ReverseDNS) {
+		reverseDNS := data.ReverseDNS.ValueString()
+		updateReq.ReverseDNS = &reverseDNS
+		changed = append(changed, path.Root("reverse_dns"))
+	}
+	if !data.CDROMImage.Equal(state.CDROMImage) {
+		// An empty image ejects the disc
+		cdromImage := data.CDROMImage.ValueString()
+		updateReq.CDROMImage = &cdromImage
+		changed = append(changed, path.Root("cdrom_image"))
+	}
+	if !data.CPUs.Equal(state.CPUs) {
+		updateReq.CPUs = int(data.CPUs.ValueInt64())
+		changed = append(changed, path.Root("cpus"))
+	}
+	if !data.RamGB.Equal(state.RamGB) {
+		updateReq.RamBytes = slicer.GiB(data.RamGB.ValueInt64())
+		changed = append(changed, path.Root("ram_gb"))
+	}
+	if !data.Tags.Equal(state.Tags) {
+		tags, err := r.updatedTags(ctx, &data, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read VM tags: %s", err))
+			return
+		}
+		updateReq.Tags = &tags
+		changed = append(changed, path.Root("tags"))
+	}
+	if !data.Metadata.Equal(state.Metadata) {
+		// An empty map removes all metadata
+		metadata := map[string]string{}
+		if !data.Metadata.IsNull() {
+			resp.Diagnostics.Append(data.Metadata.ElementsAs(ctx, &metadata, false)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+		}
+		updateReq.Metadata = &metadata
+		changed = append(changed, path.Root("metadata"))
+	}
+
+	if len(changed) > 0 {
+		tflog.Debug(ctx, "Updating VM", map[string]interface{}{
+			"hostname":    data.Hostname.ValueString(),
+			"priority":    data.Priority.ValueString(),
+			"reverse_dns": data.ReverseDNS.ValueString(),
+			"cdrom_image": data.CDROMImage.ValueString(),
+			"cpus":        data.CPUs.ValueInt64(),
+			"ram_gb":      data.RamGB.ValueInt64(),
+		})
+
+		updated, err := r.client.UpdateVM(ctx, data.HostGroup.ValueString(), data.Hostname.ValueString(), updateReq)
+		if errors.Is(err, slicer.ErrNotSupported) {
+			resp.Diagnostics.AddAttributeError(
+				changed[0],
+				"In-Place Update Not Supported",
+				fmt.Sprintf("The Slicer API does not support updating a running VM. Recreate the VM to change %s.", changed[0]),
+			)
+			return
+		}
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update VM: %s", err))
+			return
+		}
+
+		if updateReq.RamBytes > 0 {
+			data.RamBytes = types.Int64Value(updateReq.RamBytes)
+			if updated.RamBytes > 0 {
+				data.RamBytes = types.Int64Value(updated.RamBytes)
+			}
+		}
+
+		tflog.Trace(ctx, "Updated VM", map[string]interface{}{
+			"hostname": data.Hostname.ValueString(),
+		})
+	}
+
+	if schedule, stateSchedule := scheduleFromModel(data.Schedule), scheduleFromModel(state.Schedule); !reflect.DeepEqual(schedule, stateSchedule) {
+		tflog.Debug(ctx, "Updating VM schedule", map[string]interface{}{
+			"hostname": data.Hostname.ValueString(),
+		})
+
+		var err error
+		if schedule == nil {
+			err = r.client.DeleteVMSchedule(ctx, data.HostGroup.ValueString(), data.Hostname.ValueString())
+		} else {
+			err = r.client.SetVMSchedule(ctx, data.HostGroup.ValueString(), data.Hostname.ValueString(), *schedule)
+		}
+		if errors.Is(err, slicer.ErrNotSupported) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("schedule"),
+				"VM Schedules Not Supported",
+				"The Slicer API does not support VM schedules.",
+			)
+			return
+		}
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update VM schedule: %s", err))
+			return
+		}
+	}
+
+	if !data.VolumeAttachments.Equal(state.VolumeAttachments) {
+		var want, have []string
+		// Either side may be null when volume_attachments was added or removed
+		resp.Diagnostics.Append(data.VolumeAttachments.ElementsAs(ctx, &want, true)...)
+		resp.Diagnostics.Append(state.VolumeAttachments.ElementsAs(ctx, &have, true)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		// State records the attachments made so far if one fails
+		attached, err := r.updateVolumeAttachments(ctx, data.HostGroup.ValueString(), data.Hostname.ValueString(), want, have)
+		data.VolumeAttachments = volumeAttachmentsValue(ctx, attached, data.VolumeAttachments, &resp.Diagnostics)
+		if err != nil {
+			addVolumeAttachmentError(err, &resp.Diagnostics)
+			resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+			return
+		}
+	}
+
+	if powerState == slicer.VMPowerStateStopped && !data.PowerState.Equal(types.StringValue(powerState)) {
+		r.setPowerState(ctx, &data, powerState, &resp.Diagnostics)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *VMResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data VMResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.SkipDestroy.ValueBool() {
+		tflog.Debug(ctx, "Skipping VM deletion", map[string]interface{}{
+			"hostname":   data.Hostname.ValueString(),
+			"host_group": data.HostGroup.ValueString(),
+		})
+
+		resp.Diagnostics.AddWarning(
+			"VM Not Deleted",
+			fmt.Sprintf("skip_destroy is set, so VM %s was removed from the Terraform state but still exists in Slicer.", data.Hostname.ValueString()),
+		)
+		return
+	}
+
+	defer r.vms.invalidate()
+
+	ctx, cancel := withTimeout(ctx, data.Timeouts, timeoutDelete)
+	defer cancel()
+
+	if data.ShutdownBeforeDelete.ValueBool() && data.PowerState.ValueString() != slicer.VMPowerStateStopped {
+		r.shutdownVM(ctx, &data, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// Detach volumes first so they survive the VM
+	if !data.VolumeAttachments.IsNull() {
+		var volumes []string
+		resp.Diagnostics.Append(data.VolumeAttachments.ElementsAs(ctx, &volumes, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		if _, err := r.updateVolumeAttachments(ctx, data.HostGroup.ValueString(), data.Hostname.ValueString(), nil, volumes); err != nil {
+			addVolumeAttachmentError(err, &resp.Diagnostics)
+			return
+		}
+	}
+
+	tflog.Debug(ctx, "Deleting VM", map[string]interface{}{
+		"hostname":   data.Hostname.ValueString(),
+		"host_group": data.HostGroup.ValueString(),
+	})
+
+	_, err := r.client.DeleteVM(ctx, data.HostGroup.ValueString(), data.Hostname.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete VM: %s", err))
+		return
+	}
+
+	tflog.Trace(ctx, "Deleted VM", map[string]interface{}{
+		"hostname": data.Hostname.ValueString(),
+	})
+}
+
+func (r *VMResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Import format: host_group/hostname
+	parts := strings.SplitN(req.ID, "/", 2)
+	if len(parts) != 2 {
+		resp.Diagnostics.AddError(
+			"Invalid Import ID",
+			"Import ID must be in the format: host_group/hostname",
+		)
+		return
+	}
+
+	found, err := r.client.GetVM(ctx, parts[1])
+	if errors.Is(err, slicer.ErrVMNotFound) {
+		resp.Diagnostics.AddError(
+			"VM Not Found",
+			fmt.Sprintf("No VM with hostname '%s' exists.", parts[1]),
+		)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read VM: %s", err))
+		return
+	}
+
+	if found.HostGroup != "" && found.HostGroup != parts[0] {
+		resp.Diagnostics.AddError(
+			"Invalid Import ID",
+			fmt.Sprintf("VM %s belongs to host group '%s', not '%s'.", parts[1], found.HostGroup, parts[0]),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("host_group"), parts[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("hostname"), parts[1])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), parts[1])...)
+
+	// Attributes with defaults take them when the API does not report the
+	// value, so configurations that leave them unset plan no changes. Read
+	// runs after the import and fills in the remaining attributes, such as
+	// ip, arch, created_at and tags.
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("cpus"), int64(found.CPUs))...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("ram_gb"), slicer.BytesToGiB(found.RamBytes))...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("persistent"), false)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("priority"), vmPriorityNormal)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("secure_boot"), false)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("tpm"), false)...)
+
+	// Read does not refresh secrets, so they are only taken from the API here
+	if len(found.Secrets) > 0 {
+		secrets, diags := types.ListValueFrom(ctx, types.StringType, found.Secrets)
+		resp.Diagnostics.Append(diags...)
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("secrets"), secrets)...)
+	}
+}
+
+// planHostname plans the hostname of a VM with a name, and replaces the VM
+// when name or hostname_prefix change. Imported VMs have neither in state, so
+// they are only replaced when the configured name does not fit the hostname.
+func (r *VMResource) planHostname(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var name, hostnamePrefix types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("name"), &name)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("hostname_prefix"), &hostnamePrefix)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !name.IsNull() || !hostnamePrefix.IsNull() {
+		checkCapability(r.serverInfo, capabilityVMNaming, path.Root("name"), &resp.Diagnostics)
+	}
+
+	if req.State.Raw.IsNull() {
+		if !name.IsNull() && !name.IsUnknown() {
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("hostname"), name)...)
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("id"), name)...)
+		}
+		return
+	}
+
+	var stateName, statePrefix, stateHostname types.String
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("name"), &stateName)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("hostname_prefix"), &statePrefix)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("hostname"), &stateHostname)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !name.Equal(stateName) && !(stateName.IsNull() && name.Equal(stateHostname)) {
+		resp.RequiresReplace = append(resp.RequiresReplace, path.Root("name"))
+	}
+
+	fitsHostname := statePrefix.IsNull() && !hostnamePrefix.IsUnknown() &&
+		strings.HasPrefix(stateHostname.ValueString(), hostnamePrefix.ValueString()+"-")
+	if !hostnamePrefix.Equal(statePrefix) && !fitsHostname {
+		resp.RequiresReplace = append(resp.RequiresReplace, path.Root("hostname_prefix"))
+	}
+}
+
+// planResize plans in-place changes of cpus and ram_gb. Leaving either unset
+// keeps the VM's current size rather than planning a change to the host group
+// default, which a running VM cannot be reset to.
+func (r *VMResource) planResize(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.State.Raw.IsNull() {
+		return
+	}
+
+	var configCPUs, configRamGB, cpus, ramGB, stateCPUs, stateRamGB types.Int64
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("cpus"), &configCPUs)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("ram_gb"), &configRamGB)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("cpus"), &cpus)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("ram_gb"), &ramGB)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("cpus"), &stateCPUs)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("ram_gb"), &stateRamGB)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if configCPUs.IsNull() {
+		cpus = stateCPUs
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("cpus"), cpus)...)
+	}
+	if configRamGB.IsNull() {
+		ramGB = stateRamGB
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("ram_gb"), ramGB)...)
+	}
+
+	if !cpus.Equal(stateCPUs) {
+		checkCapability(r.serverInfo, capabilityVMResize, path.Root("cpus"), &resp.Diagnostics)
+	}
+	if !ramGB.Equal(stateRamGB) {
+		checkCapability(r.serverInfo, capabilityVMResize, path.Root("ram_gb"), &resp.Diagnostics)
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("ram_bytes"), types.Int64Unknown())...)
+	}
 }
 
-func (r *VMResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	// Import format: host_group/hostname
-	parts := strings.SplitN(req.ID, "/", 2)
-	if len(parts) != 2 {
-		resp.Diagnostics.AddError(
-			"Invalid Import ID",
-			"Import ID must be in the format: host_group/hostname",
+// checkFirmware fails the plan when secure_boot or tpm is requested from a
+// Slicer version or host group that does not support it. Host groups that do
+// not report their firmware features are left for the API to decide.
+func (r *VMResource) checkFirmware(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var hostGroup types.String
+	var secureBoot, tpm types.Bool
+
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("host_group"), &hostGroup)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("secure_boot"), &secureBoot)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("tpm"), &tpm)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var requested []string
+	if secureBoot.ValueBool() {
+		requested = append(requested, slicer.FirmwareFeatureSecureBoot)
+	}
+	if tpm.ValueBool() {
+		requested = append(requested, slicer.FirmwareFeatureTPM)
+	}
+	if len(requested) == 0 {
+		return
+	}
+
+	// The firmware features are named after their attributes
+	for _, feature := range requested {
+		checkCapability(r.serverInfo, capabilityVMFirmware, path.Root(feature), &resp.Diagnostics)
+	}
+	if resp.Diagnostics.HasError() || hostGroup.IsUnknown() || r.client == nil {
+		return
+	}
+
+	hostGroups, err := r.client.GetHostGroups(ctx)
+	if err != nil {
+		tflog.Warn(ctx, "Unable to list host groups to check firmware support", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	for _, hg := range hostGroups {
+		if hg.Name != hostGroup.ValueString() || hg.FirmwareFeatures == nil {
+			continue
+		}
+
+		for _, feature := range requested {
+			if !slices.Contains(hg.FirmwareFeatures, feature) {
+				resp.Diagnostics.AddAttributeError(
+					path.Root(feature),
+					"Unsupported Firmware Option",
+					fmt.Sprintf("Host group %s does not support %s. Supported firmware options: [%s].", hg.Name, feature, strings.Join(hg.FirmwareFeatures, ", ")),
+				)
+			}
+		}
+	}
+}
+
+// checkGPUs fails the plan when gpu_count exceeds the GPUs per VM of the host
+// group. Unknown host groups are left for the API to report.
+func (r *VMResource) checkGPUs(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var hostGroup types.String
+	var gpuCount types.Int64
+
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("host_group"), &hostGroup)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("gpu_count"), &gpuCount)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if gpuCount.IsNull() || gpuCount.IsUnknown() || hostGroup.IsUnknown() || r.client == nil {
+		return
+	}
+
+	hostGroups, err := r.client.GetHostGroups(ctx)
+	if err != nil {
+		tflog.Warn(ctx, "Unable to list host groups to check GPU count", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	for _, hg := range hostGroups {
+		if hg.Name != hostGroup.ValueString() {
+			continue
+		}
+
+		if int64(hg.GPUCount) < gpuCount.ValueInt64() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("gpu_count"),
+				"Too Many GPUs",
+				fmt.Sprintf("Host group %s provides %d GPUs per VM, but gpu_count is %d.", hg.Name, hg.GPUCount, gpuCount.ValueInt64()),
+			)
+		}
+	}
+}
+
+// checkAddressing plans the ip of a VM with a static address and reports a
+// subnet that Slicer does not know, before the VM is created.
+func (r *VMResource) checkAddressing(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var ipAddress, subnet types.String
+
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("ip_address"), &ipAddress)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("subnet"), &subnet)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !ipAddress.IsNull() {
+		checkCapability(r.serverInfo, capabilityVMAddressing, path.Root("ip_address"), &resp.Diagnostics)
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("ip"), ipAddress)...)
+	}
+
+	if subnet.IsNull() {
+		return
+	}
+
+	checkCapability(r.serverInfo, capabilityVMAddressing, path.Root("subnet"), &resp.Diagnostics)
+	if subnet.IsUnknown() || r.client == nil {
+		return
+	}
+
+	subnets, err := r.client.ListSubnets(ctx)
+	if err != nil {
+		tflog.Warn(ctx, "Unable to list subnets to check subnet", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	_, want, err := net.ParseCIDR(subnet.ValueString())
+	if err != nil {
+		return
+	}
+	for _, s := range subnets {
+		if _, network, err := net.ParseCIDR(s.CIDR); err == nil && network.String() == want.String() {
+			return
+		}
+	}
+
+	resp.Diagnostics.AddAttributeError(
+		path.Root("subnet"),
+		"Unknown Subnet",
+		fmt.Sprintf("Slicer has no subnet %s. Use one of the CIDRs listed by data.slicer_subnets.", subnet.ValueString()),
+	)
+}
+
+// setPowerState starts or stops the VM and records powerState in data once
+// the API accepted the change.
+func (r *VMResource) setPowerState(ctx context.Context, data *VMResourceModel, powerState string, diags *diag.Diagnostics) {
+	tflog.Debug(ctx, "Changing VM power state", map[string]interface{}{
+		"hostname":    data.Hostname.ValueString(),
+		"power_state": powerState,
+	})
+
+	var err error
+	if powerState == slicer.VMPowerStateStopped {
+		err = r.client.StopVM(ctx, data.HostGroup.ValueString(), data.Hostname.ValueString())
+	} else {
+		err = r.client.StartVM(ctx, data.HostGroup.ValueString(), data.Hostname.ValueString())
+	}
+	if errors.Is(err, slicer.ErrNotSupported) {
+		diags.AddAttributeError(
+			path.Root("power_state"),
+			"VM Power Management Not Supported",
+			"The Slicer API does not support starting and stopping VMs.",
 		)
 		return
 	}
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to change VM power state to %s: %s", powerState, err))
+		return
+	}
 
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("host_group"), parts[0])...)
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("hostname"), parts[1])...)
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), parts[1])...)
+	data.PowerState = types.StringValue(powerState)
+
+	tflog.Trace(ctx, "Changed VM power state", map[string]interface{}{
+		"hostname":    data.Hostname.ValueString(),
+		"power_state": powerState,
+	})
+}
+
+// shutdownVM requests a clean shutdown of the VM and waits up to the
+// shutdown grace period for it to stop. Running out of time is not an error,
+// since the VM is deleted either way.
+func (r *VMResource) shutdownVM(ctx context.Context, data *VMResourceModel, diags *diag.Diagnostics) {
+	hostGroup := data.HostGroup.ValueString()
+	hostname := data.Hostname.ValueString()
+
+	gracePeriod := defaultVMShutdownGracePeriod
+	if !data.ShutdownGracePeriod.IsNull() {
+		d, err := time.ParseDuration(data.ShutdownGracePeriod.ValueString())
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("shutdown_grace_period"),
+				"Invalid Duration",
+				fmt.Sprintf("Unable to parse shutdown_grace_period: %s", err),
+			)
+			return
+		}
+		gracePeriod = d
+	}
+
+	tflog.Debug(ctx, "Shutting down VM before deletion", map[string]interface{}{
+		"hostname":     hostname,
+		"grace_period": gracePeriod.String(),
+	})
+
+	err := r.client.StopVM(ctx, hostGroup, hostname)
+	if errors.Is(err, slicer.ErrNotSupported) {
+		diags.AddWarning(
+			"VM Power Management Not Supported",
+			fmt.Sprintf("The Slicer API does not support stopping VMs, so VM %s is deleted without a clean shutdown.", hostname),
+		)
+		return
+	}
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to shut down VM: %s", err))
+		return
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, gracePeriod)
+	defer cancel()
+
+	// Older servers do not report the power state, in which case the VM is
+	// considered stopped once its agent stops answering
+	err = r.client.WaitUntil(waitCtx, func(ctx context.Context) (bool, error) {
+		vm, err := r.client.GetVM(ctx, hostname)
+		if errors.Is(err, slicer.ErrVMNotFound) {
+			return true, nil
+		}
+		if err != nil {
+			return false, nil
+		}
+		if vm.PowerState != "" {
+			return vm.PowerState == slicer.VMPowerStateStopped, nil
+		}
+
+		_, err = r.client.GetAgentHealth(ctx, hostname, false)
+		return err != nil, nil
+	})
+	if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+		tflog.Warn(ctx, "VM did not stop within the shutdown grace period, deleting it anyway", map[string]interface{}{
+			"hostname":     hostname,
+			"grace_period": gracePeriod.String(),
+		})
+		return
+	}
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to wait for VM %s to shut down: %s", hostname, err))
+		return
+	}
+
+	tflog.Trace(ctx, "Shut down VM", map[string]interface{}{
+		"hostname": hostname,
+	})
+}
+
+// lookupSSHKeys returns the public keys stored under names, in the same
+// order. Returns slicer.ErrNotSupported if the API does not store SSH keys.
+func (r *VMResource) lookupSSHKeys(ctx context.Context, names []string) ([]string, error) {
+	keys, err := r.client.ListSSHKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	stored := make(map[string]string, len(keys))
+	for _, key := range keys {
+		stored[key.Name] = key.PublicKey
+	}
+
+	publicKeys := make([]string, 0, len(names))
+	for _, name := range names {
+		publicKey, ok := stored[name]
+		if !ok {
+			return nil, fmt.Errorf("SSH key %q not found", name)
+		}
+		publicKeys = append(publicKeys, publicKey)
+	}
+
+	return publicKeys, nil
+}
+
+// updateVolumeAttachments detaches the volumes in have that are not in want,
+// then attaches the volumes in want that are not in have. It returns the
+// volumes attached afterwards, which on error reflects the changes made so far.
+func (r *VMResource) updateVolumeAttachments(ctx context.Context, hostGroup, hostname string, want, have []string) ([]string, error) {
+	attached := slices.Clone(have)
+
+	for _, volume := range have {
+		if slices.Contains(want, volume) {
+			continue
+		}
+
+		tflog.Debug(ctx, "Detaching volume", map[string]interface{}{
+			"hostname": hostname,
+			"volume":   volume,
+		})
+
+		if err := r.client.DetachVolume(ctx, hostGroup, hostname, volume); err != nil {
+			return attached, fmt.Errorf("failed to detach volume %s: %w", volume, err)
+		}
+		attached = slices.DeleteFunc(attached, func(v string) bool { return v == volume })
+	}
+
+	for _, volume := range want {
+		if slices.Contains(have, volume) {
+			continue
+		}
+
+		tflog.Debug(ctx, "Attaching volume", map[string]interface{}{
+			"hostname": hostname,
+			"volume":   volume,
+		})
+
+		result, err := r.client.AttachVolume(ctx, hostGroup, hostname, volume)
+		if err != nil {
+			return attached, fmt.Errorf("failed to attach volume %s: %w", volume, err)
+		}
+		attached = append(attached, volume)
+
+		tflog.Trace(ctx, "Attached volume", map[string]interface{}{
+			"hostname": hostname,
+			"volume":   volume,
+			"device":   result.Device,
+		})
+	}
+
+	return attached, nil
+}
+
+// volumeAttachmentsValue converts attached volumes to the volume_attachments
+// set. A configuration without volume_attachments stays null when none are
+// attached.
+func volumeAttachmentsValue(ctx context.Context, attached []string, current types.Set, diags *diag.Diagnostics) types.Set {
+	if current.IsNull() && len(attached) == 0 {
+		return current
+	}
+
+	value, d := types.SetValueFrom(ctx, types.StringType, attached)
+	diags.Append(d...)
+	return value
+}
+
+// addVolumeAttachmentError reports a failed attach or detach.
+func addVolumeAttachmentError(err error, diags *diag.Diagnostics) {
+	if errors.Is(err, slicer.ErrNotSupported) {
+		diags.AddAttributeError(
+			path.Root("volume_attachments"),
+			"Volumes Not Supported",
+			"The Slicer API does not support volumes.",
+		)
+		return
+	}
+	diags.AddError("Client Error", fmt.Sprintf("Unable to update volume attachments: %s", err))
+}
+
+// Defaults for connection_info when the API does not report SSH details.
+const (
+	defaultSSHUser = "ubuntu"
+	defaultSSHPort = 22
+)
+
+// sshPort returns the SSH port reported by the API, or the default.
+func sshPort(access slicer.SSHAccess) int {
+	if access.SSHPort == 0 {
+		return defaultSSHPort
+	}
+	return access.SSHPort
+}
+
+// connectionInfoAttrTypes describes the connection_info object.
+var connectionInfoAttrTypes = map[string]attr.Type{
+	"type":      types.StringType,
+	"host":      types.StringType,
+	"hostname":  types.StringType,
+	"ip":        types.StringType,
+	"ipv6":      types.StringType,
+	"user":      types.StringType,
+	"port":      types.Int64Type,
+	"host_keys": types.ListType{ElemType: types.StringType},
+}
+
+// connectionInfoValue builds the connection_info object for a VM.
+func connectionInfoValue(ctx context.Context, hostname, ip string, access slicer.SSHAccess) (types.Object, diag.Diagnostics) {
+	user := access.SSHUser
+	if user == "" {
+		user = defaultSSHUser
+	}
+
+	port := sshPort(access)
+
+	hostKeys := access.SSHHostKeys
+	if hostKeys == nil {
+		hostKeys = []string{}
+	}
+	hostKeysValue, diags := types.ListValueFrom(ctx, types.StringType, hostKeys)
+	if diags.HasError() {
+		return types.ObjectNull(connectionInfoAttrTypes), diags
+	}
+
+	return types.ObjectValue(connectionInfoAttrTypes, map[string]attr.Value{
+		"type":      types.StringValue("ssh"),
+		"host":      types.StringValue(ip),
+		"hostname":  types.StringValue(hostname),
+		"ip":        types.StringValue(ip),
+		"ipv6":      optionalString(access.IPv6),
+		"user":      types.StringValue(user),
+		"port":      types.Int64Value(int64(port)),
+		"host_keys": hostKeysValue,
+	})
+}
+
+// VM priority classes accepted by the Slicer API.
+const (
+	vmPriorityLow    = "low"
+	vmPriorityNormal = "normal"
+	vmPriorityHigh   = "high"
+)
+
+// Filesystems an additional disk can be formatted with.
+const (
+	vmDiskFormatExt4 = "ext4"
+	vmDiskFormatXFS  = "xfs"
+)
+
+// disksToModel converts the additional disks reported by the API to the
+// model. Labels and formats the API does not echo back are kept from state.
+func disksToModel(disks []slicer.SlicerDisk, state []VMDiskModel) []VMDiskModel {
+	models := make([]VMDiskModel, len(disks))
+	for i, disk := range disks {
+		models[i] = VMDiskModel{
+			SizeGB: types.Int64Value(slicer.BytesToGiB(disk.SizeBytes)),
+			Label:  optionalString(disk.Label),
+			Format: optionalString(disk.Format),
+		}
+		if i < len(state) {
+			if disk.Label == "" {
+				models[i].Label = state[i].Label
+			}
+			if disk.Format == "" {
+				models[i].Format = state[i].Format
+			}
+		}
+	}
+	return models
+}
+
+// Actions a watchdog device can take.
+const (
+	vmWatchdogReset    = "reset"
+	vmWatchdogPoweroff = "poweroff"
+)
+
+// defaultVMShutdownGracePeriod bounds the wait for a clean shutdown before
+// deletion when no shutdown_grace_period is configured.
+const defaultVMShutdownGracePeriod = 2 * time.Minute
+
+// userdataHash returns the hex encoded SHA256 of userdata.
+func userdataHash(userdata string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(userdata)))
+}
+
+// scheduleFromModel converts the schedule block to its API representation.
+// It returns nil when the block is not set.
+func scheduleFromModel(schedule *VMScheduleModel) *slicer.SlicerVMSchedule {
+	if schedule == nil {
+		return nil
+	}
+	return &slicer.SlicerVMSchedule{
+		Start:    schedule.Start.ValueString(),
+		Stop:     schedule.Stop.ValueString(),
+		Timezone: schedule.Timezone.ValueString(),
+	}
+}
+
+// scheduleToModel converts a schedule reported by the API to the schedule block.
+func scheduleToModel(schedule *slicer.SlicerVMSchedule) *VMScheduleModel {
+	if schedule == nil {
+		return nil
+	}
+	return &VMScheduleModel{
+		Start:    optionalString(schedule.Start),
+		Stop:     optionalString(schedule.Stop),
+		Timezone: optionalString(schedule.Timezone),
+	}
+}
+
+// optionalString returns a null string for "" and the value otherwise.
+func optionalString(s string) types.String {
+	if s == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(s)
+}
+
+// validateCronAttribute adds an error at attrPath when expr is set to
+// something that is not a cron expression.
+func validateCronAttribute(expr types.String, attrPath path.Path, diags *diag.Diagnostics) {
+	if expr.IsNull() || expr.IsUnknown() || isCronExpression(expr.ValueString()) {
+		return
+	}
+	diags.AddAttributeError(
+		attrPath,
+		"Invalid Cron Expression",
+		fmt.Sprintf("Expected a cron expression with five fields or a descriptor such as '@daily', got: %s", expr.ValueString()),
+	)
+}
+
+// consoleCredentials converts console credentials to attribute values,
+// using null for credentials the API did not issue.
+func consoleCredentials(access slicer.ConsoleAccess) (types.String, types.String) {
+	user, password := types.StringNull(), types.StringNull()
+	if access.ConsoleUser != "" {
+		user = types.StringValue(access.ConsoleUser)
+	}
+	if access.ConsolePassword != "" {
+		password = types.StringValue(access.ConsolePassword)
+	}
+	return user, password
+}
+
+// validateHostnameLabel adds an error at attrPath when value is set but is
+// not a valid hostname label.
+func validateHostnameLabel(value types.String, attrPath path.Path, diags *diag.Diagnostics) {
+	if value.IsNull() || value.IsUnknown() || hostnameLabelPattern.MatchString(value.ValueString()) {
+		return
+	}
+
+	diags.AddAttributeError(
+		attrPath,
+		"Invalid Hostname",
+		fmt.Sprintf("%s must contain only lowercase letters, digits and hyphens, start with a letter and be at most 63 characters long, got: %s", attrPath, value.ValueString()),
+	)
+}
+
+// validateVMAddressing adds errors when ip_address is not an IPv4 address,
+// subnet is not a CIDR, or the address lies outside the subnet.
+func validateVMAddressing(ipAddress, subnet types.String, diags *diag.Diagnostics) {
+	validateCIDRAttribute(subnet, path.Root("subnet"), diags)
+
+	if ipAddress.IsNull() || ipAddress.IsUnknown() {
+		return
+	}
+
+	ip := net.ParseIP(ipAddress.ValueString())
+	if ip == nil || ip.To4() == nil {
+		diags.AddAttributeError(
+			path.Root("ip_address"),
+			"Invalid IP Address",
+			fmt.Sprintf("ip_address must be an IPv4 address without a prefix length, got: %s", ipAddress.ValueString()),
+		)
+		return
+	}
+
+	if subnet.IsNull() || subnet.IsUnknown() {
+		return
+	}
+
+	if _, network, err := net.ParseCIDR(subnet.ValueString()); err == nil && !network.Contains(ip) {
+		diags.AddAttributeError(
+			path.Root("ip_address"),
+			"IP Address Outside Subnet",
+			fmt.Sprintf("ip_address %s is not in subnet %s.", ipAddress.ValueString(), subnet.ValueString()),
+		)
+	}
+}
+
+// hostnameLabelPattern matches a single lowercase DNS label usable as a
+// hostname or hostname prefix.
+var hostnameLabelPattern = regexp.MustCompile(`^[a-z]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// dnsNamePattern matches fully qualified domain names, optionally with a trailing dot.
+var dnsNamePattern = regexp.MustCompile(`^([a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?\.)+[a-zA-Z]{2,63}\.?$`)
+
+// isCronExpression reports whether expr looks like a five field cron
+// expression or a descriptor such as "@daily". Field values are checked by the API.
+func isCronExpression(expr string) bool {
+	if strings.HasPrefix(expr, "@") {
+		return len(strings.Fields(expr)) == 1
+	}
+	return len(strings.Fields(expr)) == 5
+}
+
+// tagPrefixesToIgnore returns the prefixes of tag keys that are managed
+// outside of Terraform, from the provider and from ignored_tag_prefixes.
+func (r *VMResource) tagPrefixesToIgnore(ctx context.Context, data *VMResourceModel) ([]string, diag.Diagnostics) {
+	prefixes := append([]string{}, r.ignoredTagPrefixes...)
+	if data.IgnoredTagPrefixes.IsNull() {
+		return prefixes, nil
+	}
+
+	var configured []string
+	diags := data.IgnoredTagPrefixes.ElementsAs(ctx, &configured, false)
+	return append(prefixes, configured...), diags
+}
+
+// updatedTags returns the full tag list to set on the VM: the configured tags
+// merged over the provider's default tags, plus the VM's current tags with
+// ignored prefixes, which are managed outside of Terraform.
+func (r *VMResource) updatedTags(ctx context.Context, data *VMResourceModel, diags *diag.Diagnostics) ([]string, error) {
+	ignoredPrefixes, d := r.tagPrefixesToIgnore(ctx, data)
+	diags.Append(d...)
+
+	var configured map[string]string
+	if !data.Tags.IsNull() {
+		diags.Append(data.Tags.ElementsAs(ctx, &configured, false)...)
+	}
+	if diags.HasError() {
+		return nil, nil
+	}
+
+	tags := []string{}
+	if len(ignoredPrefixes) > 0 {
+		found, err := r.client.GetVM(ctx, data.Hostname.ValueString())
+		if err != nil {
+			return nil, err
+		}
+		for _, tag := range found.Tags {
+			key, _, _ := strings.Cut(tag, "=")
+			if hasAnyPrefix(key, ignoredPrefixes) {
+				tags = append(tags, tag)
+			}
+		}
+	}
+
+	for k, v := range mergeTags(r.defaultTags, configured) {
+		tags = append(tags, fmt.Sprintf("%s=%s", k, v))
+	}
+	slices.Sort(tags)
+	return tags, nil
+}
+
+// hasAnyPrefix reports whether s starts with any of prefixes.
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,369 @@
+// Copyright (c) German Arutyunov
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gaarutyunov/terraform-provider-slicer/internal/slicer"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &VMTemplateResource{}
+var _ resource.ResourceWithImportState = &VMTemplateResource{}
+var _ resource.ResourceWithModifyPlan = &VMTemplateResource{}
+
+func NewVMTemplateResource() resource.Resource {
+	return &VMTemplateResource{}
+}
+
+// VMTemplateResource defines the resource implementation.
+type VMTemplateResource struct {
+	client      *slicer.SlicerClient
+	serverInfo  *slicer.SlicerServerInfo
+	tokenScopes []string
+}
+
+// VMTemplateResourceModel describes the resource data model.
+type VMTemplateResourceModel struct {
+	ID         types.String `tfsdk:"id"`
+	Name       types.String `tfsdk:"name"`
+	CPUs       types.Int64  `tfsdk:"cpus"`
+	RamGB      types.Int64  `tfsdk:"ram_gb"`
+	DiskImage  types.String `tfsdk:"disk_image"`
+	ImportUser types.String `tfsdk:"import_user"`
+	SSHKeys    types.List   `tfsdk:"ssh_keys"`
+	Userdata   types.String `tfsdk:"userdata"`
+	Tags       types.Map    `tfsdk:"tags"`
+	Secrets    types.List   `tfsdk:"secrets"`
+	Revision   types.Int64  `tfsdk:"revision"`
+}
+
+func (r *VMTemplateResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_vm_template"
+}
+
+func (r *VMTemplateResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages a reusable VM spec stored in Slicer. VMs reference it with `template_id`. " +
+			"Changing a template does not touch VMs already created from it; use `revision` with `replace_triggered_by` to roll changes out.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The unique identifier of the template (name).",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The name of the template.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"cpus": schema.Int64Attribute{
+				Optional:            true,
+				MarkdownDescription: "Number of CPUs. Defaults to host group setting.",
+			},
+			"ram_gb": schema.Int64Attribute{
+				Optional:            true,
+				MarkdownDescription: "RAM in GB. Defaults to host group setting.",
+			},
+			"disk_image": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Custom disk image to use.",
+			},
+			"import_user": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Import SSH keys from GitHub user.",
+			},
+			"ssh_keys": schema.ListAttribute{
+				Optional:            true,
+				MarkdownDescription: "List of SSH public keys to inject.",
+				ElementType:         types.StringType,
+			},
+			"userdata": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Cloud-init userdata script.",
+			},
+			"tags": schema.MapAttribute{
+				Optional:            true,
+				MarkdownDescription: "Tags to apply to VMs created from the template (key=value format).",
+				ElementType:         types.StringType,
+			},
+			"secrets": schema.ListAttribute{
+				Optional:            true,
+				MarkdownDescription: "List of secret names to inject into VMs created from the template.",
+				ElementType:         types.StringType,
+			},
+			"revision": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "Revision of the template, incremented by Slicer on every change.",
+			},
+		},
+	}
+}
+
+func (r *VMTemplateResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*SlicerProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *SlicerProviderData, got: %T", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = providerData.Client
+	r.tokenScopes = providerData.TokenScopes
+	r.serverInfo = providerData.ServerInfo
+}
+
+func (r *VMTemplateResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	checkScope(req, r.tokenScopes, scopeVMsWrite, "slicer_vm_template", &resp.Diagnostics)
+
+	// Only creation depends on the server version
+	if req.Plan.Raw.IsNull() || !req.State.Raw.IsNull() {
+		return
+	}
+
+	checkCapability(r.serverInfo, capabilityVMTemplates, path.Root("name"), &resp.Diagnostics)
+}
+
+func (r *VMTemplateResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data VMTemplateResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	template := templateFromModel(ctx, &data, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Creating VM template", map[string]interface{}{
+		"name": template.Name,
+	})
+
+	created, err := r.client.CreateTemplate(ctx, template)
+	if errors.Is(err, slicer.ErrNotSupported) {
+		resp.Diagnostics.AddError(
+			"VM Templates Not Supported",
+			"The Slicer API does not support VM templates.",
+		)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create VM template: %s", err))
+		return
+	}
+
+	data.ID = data.Name
+	data.Revision = types.Int64Value(created.Revision)
+
+	tflog.Trace(ctx, "Created VM template", map[string]interface{}{
+		"name":     created.Name,
+		"revision": created.Revision,
+	})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *VMTemplateResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data VMTemplateResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	templates, err := r.client.ListTemplates(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list VM templates: %s", err))
+		return
+	}
+
+	var found *slicer.SlicerVMTemplate
+	for _, template := range templates {
+		if template.Name == data.Name.ValueString() {
+			found = &template
+			break
+		}
+	}
+
+	if found == nil {
+		// Template was deleted outside of Terraform
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	data.ID = data.Name
+	data.Revision = types.Int64Value(found.Revision)
+
+	if found.CPUs > 0 {
+		data.CPUs = types.Int64Value(int64(found.CPUs))
+	}
+	if found.RamBytes > 0 {
+		data.RamGB = types.Int64Value(found.RamBytes / (1024 * 1024 * 1024))
+	}
+	if found.DiskImage != "" {
+		data.DiskImage = types.StringValue(found.DiskImage)
+	}
+	if found.ImportUser != "" {
+		data.ImportUser = types.StringValue(found.ImportUser)
+	}
+	if found.Userdata != "" {
+		data.Userdata = types.StringValue(found.Userdata)
+	}
+	if len(found.Tags) > 0 {
+		tagsValue, diags := types.MapValueFrom(ctx, types.StringType, tagsFromAPI(found.Tags))
+		resp.Diagnostics.Append(diags...)
+		data.Tags = tagsValue
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *VMTemplateResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data VMTemplateResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	template := templateFromModel(ctx, &data, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Updating VM template", map[string]interface{}{
+		"name": template.Name,
+	})
+
+	updated, err := r.client.UpdateTemplate(ctx, template)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update VM template: %s", err))
+		return
+	}
+
+	data.Revision = types.Int64Value(updated.Revision)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *VMTemplateResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data VMTemplateResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Deleting VM template", map[string]interface{}{
+		"name": data.Name.ValueString(),
+	})
+
+	err := r.client.DeleteTemplate(ctx, data.Name.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete VM template: %s", err))
+		return
+	}
+
+	tflog.Trace(ctx, "Deleted VM template", map[string]interface{}{
+		"name": data.Name.ValueString(),
+	})
+}
+
+func (r *VMTemplateResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+}
+
+// templateFromModel builds the API payload from the resource model.
+func templateFromModel(ctx context.Context, data *VMTemplateResourceModel, diags *diag.Diagnostics) slicer.SlicerVMTemplate {
+	template := slicer.SlicerVMTemplate{
+		Name:       data.Name.ValueString(),
+		CPUs:       int(data.CPUs.ValueInt64()),
+		DiskImage:  data.DiskImage.ValueString(),
+		ImportUser: data.ImportUser.ValueString(),
+		Userdata:   data.Userdata.ValueString(),
+	}
+
+	if !data.RamGB.IsNull() {
+		template.RamBytes = slicer.GiB(data.RamGB.ValueInt64())
+	}
+
+	if !data.SSHKeys.IsNull() {
+		diags.Append(data.SSHKeys.ElementsAs(ctx, &template.SSHKeys, false)...)
+	}
+
+	if !data.Secrets.IsNull() {
+		diags.Append(data.Secrets.ElementsAs(ctx, &template.Secrets, false)...)
+	}
+
+	if !data.Tags.IsNull() {
+		var tags map[string]string
+		diags.Append(data.Tags.ElementsAs(ctx, &tags, false)...)
+		template.Tags = tagsToAPI(tags)
+	}
+
+	return template
+}
+
+// tagsToAPI converts a tag map to the key=value list used by the API,
+// sorted so the payload is stable.
+func tagsToAPI(tags map[string]string) []string {
+	list := make([]string, 0, len(tags))
+	for k, v := range tags {
+		list = append(list, fmt.Sprintf("%s=%s", k, v))
+	}
+	sort.Strings(list)
+	return list
+}
+
+// mergeTags returns the provider's default tags overridden by tags.
+func mergeTags(defaults, tags map[string]string) map[string]string {
+	merged := make(map[string]string, len(defaults)+len(tags))
+	for k, v := range defaults {
+		merged[k] = v
+	}
+	for k, v := range tags {
+		merged[k] = v
+	}
+	return merged
+}
+
+// tagsFromAPI converts the key=value list used by the API to a tag map.
+// Entries without "=" are skipped.
+func tagsFromAPI(list []string) map[string]string {
+	tags := make(map[string]string, len(list))
+	for _, tag := range list {
+		parts := strings.SplitN(tag, "=", 2)
+		if len(parts) == 2 {
+			tags[parts[0]] = parts[1]
+		}
+	}
+	return tags
+}
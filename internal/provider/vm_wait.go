@@ -0,0 +1,130 @@
+// Copyright (c) German Arutyunov
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// VMWaitForModel describes the readiness conditions checked after a VM is created.
+type VMWaitForModel struct {
+	SSH       types.Bool   `tfsdk:"ssh"`
+	CloudInit types.Bool   `tfsdk:"cloud_init"`
+	Command   types.String `tfsdk:"command"`
+	Timeout   types.String `tfsdk:"timeout"`
+}
+
+// defaultVMWaitTimeout bounds the wait_for checks when no timeout is configured.
+const defaultVMWaitTimeout = 5 * time.Minute
+
+// sshDialTimeout bounds a single attempt to reach a VM's SSH port.
+const sshDialTimeout = 5 * time.Second
+
+// vmWaitTimeout returns the configured wait_for timeout. ValidateConfig has
+// already rejected values that do not parse.
+func vmWaitTimeout(waitFor *VMWaitForModel) time.Duration {
+	if waitFor.Timeout.IsNull() {
+		return defaultVMWaitTimeout
+	}
+	timeout, _ := time.ParseDuration(waitFor.Timeout.ValueString())
+	return timeout
+}
+
+// waitForVM blocks until the VM's agent answers and every condition in the
+// wait_for block holds. The error names the condition that was not met.
+func (r *VMResource) waitForVM(ctx context.Context, hostname, ip string, sshPort int, waitFor *VMWaitForModel) error {
+	ctx, cancel := context.WithTimeout(ctx, vmWaitTimeout(waitFor))
+	defer cancel()
+
+	// lastErr keeps the most recent transient failure so a timeout says why
+	// the condition was never met
+	var lastErr error
+	wait := func(condition string, check func(ctx context.Context) (bool, error)) error {
+		tflog.Debug(ctx, "Waiting for VM", map[string]interface{}{
+			"hostname":  hostname,
+			"condition": condition,
+		})
+
+		lastErr = nil
+		err := r.client.WaitUntil(ctx, check)
+		if errors.Is(err, context.DeadlineExceeded) && lastErr != nil {
+			return fmt.Errorf("%s: %w (last error: %s)", condition, err, lastErr)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", condition, err)
+		}
+
+		tflog.Trace(ctx, "VM condition met", map[string]interface{}{
+			"hostname":  hostname,
+			"condition": condition,
+		})
+		return nil
+	}
+
+	// Every other check runs through the agent
+	err := wait("agent", func(ctx context.Context) (bool, error) {
+		_, lastErr = r.client.GetAgentHealth(ctx, hostname, false)
+		return lastErr == nil, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if waitFor.SSH.ValueBool() {
+		address := net.JoinHostPort(ip, strconv.Itoa(sshPort))
+		err := wait("ssh", func(ctx context.Context) (bool, error) {
+			dialer := net.Dialer{Timeout: sshDialTimeout}
+			conn, err := dialer.DialContext(ctx, "tcp", address)
+			if err != nil {
+				lastErr = err
+				return false, nil
+			}
+			conn.Close()
+			return true, nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if waitFor.CloudInit.ValueBool() {
+		err := wait("cloud_init", func(ctx context.Context) (bool, error) {
+			// cloud-init exits non-zero on failures, so the status line is
+			// checked before the error
+			output, err := runRemote(ctx, r.client, hostname, "cloud-init", "status")
+			switch {
+			case strings.Contains(output, "status: done"):
+				return true, nil
+			case strings.Contains(output, "status: error"):
+				return false, errors.New("cloud-init finished with errors, see /var/log/cloud-init.log on the VM")
+			}
+			lastErr = err
+			return false, nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if !waitFor.Command.IsNull() {
+		err := wait("command", func(ctx context.Context) (bool, error) {
+			_, lastErr = runRemoteScript(ctx, r.client, hostname, waitFor.Command.ValueString())
+			return lastErr == nil, nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
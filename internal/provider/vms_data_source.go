@@ -6,6 +6,7 @@ package provider
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"strings"
 	"time"
 
@@ -13,12 +14,15 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ datasource.DataSource = &VMsDataSource{}
+var _ datasource.DataSourceWithValidateConfig = &VMsDataSource{}
 
 func NewVMsDataSource() datasource.DataSource {
 	return &VMsDataSource{}
@@ -34,21 +38,31 @@ type VMsDataSourceModel struct {
 	Filter     types.List  `tfsdk:"filter"`
 	VMs        types.List  `tfsdk:"vms"`
 	TotalCount types.Int64 `tfsdk:"total_count"`
+	TotalCPUs  types.Int64 `tfsdk:"total_cpus"`
+	TotalRamGB types.Int64 `tfsdk:"total_ram_gb"`
+	ArchCounts types.Map   `tfsdk:"arch_counts"`
+	ByGroup    types.Map   `tfsdk:"vms_by_host_group"`
 }
 
 // VMsFilterModel describes a filter block.
 type VMsFilterModel struct {
-	Tag types.String `tfsdk:"tag"`
+	Tag           types.String `tfsdk:"tag"`
+	HostGroup     types.String `tfsdk:"host_group"`
+	Arch          types.String `tfsdk:"arch"`
+	HostnameRegex types.String `tfsdk:"hostname_regex"`
+	CreatedAfter  types.String `tfsdk:"created_after"`
 }
 
 // VMsVMModel describes a VM in the list.
 type VMsVMModel struct {
 	Hostname  types.String `tfsdk:"hostname"`
+	HostGroup types.String `tfsdk:"host_group"`
 	IP        types.String `tfsdk:"ip"`
 	CPUs      types.Int64  `tfsdk:"cpus"`
 	RamGB     types.Int64  `tfsdk:"ram_gb"`
 	Arch      types.String `tfsdk:"arch"`
 	Tags      types.Map    `tfsdk:"tags"`
+	Secrets   types.List   `tfsdk:"secrets"`
 	CreatedAt types.String `tfsdk:"created_at"`
 }
 
@@ -70,6 +84,10 @@ func (d *VMsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest
 							Computed:            true,
 							MarkdownDescription: "The hostname of the VM.",
 						},
+						"host_group": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "The host group the VM runs in.",
+						},
 						"ip": schema.StringAttribute{
 							Computed:            true,
 							MarkdownDescription: "The IP address of the VM.",
@@ -91,6 +109,11 @@ func (d *VMsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest
 							MarkdownDescription: "Tags applied to the VM.",
 							ElementType:         types.StringType,
 						},
+						"secrets": schema.ListAttribute{
+							Computed:            true,
+							MarkdownDescription: "Names of the secrets mounted on the VM.",
+							ElementType:         types.StringType,
+						},
 						"created_at": schema.StringAttribute{
 							Computed:            true,
 							MarkdownDescription: "The creation timestamp of the VM.",
@@ -102,16 +125,50 @@ func (d *VMsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest
 				Computed:            true,
 				MarkdownDescription: "The number of VMs matching the filter.",
 			},
+			"total_cpus": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "The total number of CPUs of the VMs matching the filter.",
+			},
+			"total_ram_gb": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "The total RAM in GB of the VMs matching the filter, rounded to the nearest GB.",
+			},
+			"arch_counts": schema.MapAttribute{
+				Computed:            true,
+				MarkdownDescription: "The number of VMs matching the filter per architecture, e.g. `{ x86_64 = 3, arm64 = 1 }`.",
+				ElementType:         types.Int64Type,
+			},
+			"vms_by_host_group": schema.MapAttribute{
+				Computed:            true,
+				MarkdownDescription: "Hostnames of the VMs matching the filter per host group, e.g. `{ w1-medium = [\"w1-medium-1\", \"w1-medium-2\"] }`.",
+				ElementType:         types.ListType{ElemType: types.StringType},
+			},
 		},
 		Blocks: map[string]schema.Block{
 			"filter": schema.ListNestedBlock{
-				MarkdownDescription: "Filter criteria for VMs.",
+				MarkdownDescription: "Filter criteria for VMs. A VM must match every predicate of every filter block. `host_group`, `arch` and `created_after` are evaluated by the API when it supports them.",
 				NestedObject: schema.NestedBlockObject{
 					Attributes: map[string]schema.Attribute{
 						"tag": schema.StringAttribute{
 							Optional:            true,
 							MarkdownDescription: "Filter by tag (key=value format).",
 						},
+						"host_group": schema.StringAttribute{
+							Optional:            true,
+							MarkdownDescription: "Only VMs in this host group.",
+						},
+						"arch": schema.StringAttribute{
+							Optional:            true,
+							MarkdownDescription: "Only VMs of this architecture, e.g. `arm64`.",
+						},
+						"hostname_regex": schema.StringAttribute{
+							Optional:            true,
+							MarkdownDescription: "Only VMs whose hostname matches this regular expression (RE2 syntax).",
+						},
+						"created_after": schema.StringAttribute{
+							Optional:            true,
+							MarkdownDescription: "Only VMs created after this time (RFC3339), e.g. `2024-01-01T00:00:00Z`.",
+						},
 					},
 				},
 			},
@@ -136,6 +193,27 @@ func (d *VMsDataSource) Configure(ctx context.Context, req datasource.ConfigureR
 	d.client = providerData.Client
 }
 
+func (d *VMsDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var data VMsDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Filter.IsNull() || data.Filter.IsUnknown() {
+		return
+	}
+
+	var filters []VMsFilterModel
+	resp.Diagnostics.Append(data.Filter.ElementsAs(ctx, &filters, true)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	parseVMFilters(filters, &resp.Diagnostics)
+}
+
 func (d *VMsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 	var data VMsDataSourceModel
 
@@ -145,36 +223,73 @@ func (d *VMsDataSource) Read(ctx context.Context, req datasource.ReadRequest, re
 	}
 
 	// Parse filters
-	var filters []VMsFilterModel
+	var filterModels []VMsFilterModel
 	if !data.Filter.IsNull() {
-		resp.Diagnostics.Append(data.Filter.ElementsAs(ctx, &filters, false)...)
+		resp.Diagnostics.Append(data.Filter.ElementsAs(ctx, &filterModels, false)...)
 		if resp.Diagnostics.HasError() {
 			return
 		}
 	}
 
+	filters := parseVMFilters(filterModels, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	listFilter := serverVMFilter(filters)
+
 	tflog.Debug(ctx, "Listing VMs", map[string]interface{}{
 		"filter_count": len(filters),
+		"host_group":   listFilter.HostGroup,
+		"arch":         listFilter.Arch,
 	})
 
-	// List all VMs
-	vms, err := d.client.ListVMs(ctx)
+	vms, err := d.client.ListVMsFiltered(ctx, listFilter)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list VMs: %s", err))
 		return
 	}
 
-	// Apply filters
-	var filteredVMs []slicer.SlicerNode
+	// Older APIs ignore the server-side filter, so every predicate is applied
+	// again here. Host groups are resolved only for the remaining VMs as that
+	// may take a request per host group.
+	var candidates []slicer.SlicerNode
 	for _, vm := range vms {
 		if matchesFilters(vm, filters) {
+			candidates = append(candidates, vm)
+		}
+	}
+
+	hostGroups, err := d.resolveHostGroups(ctx, candidates)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list host group VMs: %s", err))
+		return
+	}
+
+	var filteredVMs []slicer.SlicerNode
+	for _, vm := range candidates {
+		if matchesHostGroupFilters(hostGroups[vm.Hostname], filters) {
 			filteredVMs = append(filteredVMs, vm)
 		}
 	}
 
 	// Convert to model
 	vmModels := make([]VMsVMModel, 0, len(filteredVMs))
+	byGroup := make(map[string][]string)
+	var totalCPUs, totalRamBytes int64
+	archCounts := make(map[string]int64)
 	for _, vm := range filteredVMs {
+		totalCPUs += int64(vm.CPUs)
+		totalRamBytes += vm.RamBytes
+		if vm.Arch != "" {
+			archCounts[vm.Arch]++
+		}
+
+		hostGroup := hostGroups[vm.Hostname]
+		if hostGroup != "" {
+			byGroup[hostGroup] = append(byGroup[hostGroup], vm.Hostname)
+		}
+
 		// Parse IP (remove CIDR notation if present)
 		ip := vm.IP
 		if strings.Contains(ip, "/") {
@@ -183,6 +298,7 @@ func (d *VMsDataSource) Read(ctx context.Context, req datasource.ReadRequest, re
 
 		vmModel := VMsVMModel{
 			Hostname:  types.StringValue(vm.Hostname),
+			HostGroup: optionalString(hostGroup),
 			IP:        types.StringValue(ip),
 			Arch:      types.StringValue(vm.Arch),
 			CreatedAt: types.StringValue(vm.CreatedAt.Format(time.RFC3339)),
@@ -195,7 +311,7 @@ func (d *VMsDataSource) Read(ctx context.Context, req datasource.ReadRequest, re
 		}
 
 		if vm.RamBytes > 0 {
-			vmModel.RamGB = types.Int64Value(vm.RamBytes / (1024 * 1024 * 1024))
+			vmModel.RamGB = types.Int64Value(slicer.BytesToGiB(vm.RamBytes))
 		} else {
 			vmModel.RamGB = types.Int64Null()
 		}
@@ -218,17 +334,23 @@ func (d *VMsDataSource) Read(ctx context.Context, req datasource.ReadRequest, re
 			vmModel.Tags = types.MapNull(types.StringType)
 		}
 
+		secretsValue, diags := types.ListValueFrom(ctx, types.StringType, vm.Secrets)
+		resp.Diagnostics.Append(diags...)
+		vmModel.Secrets = secretsValue
+
 		vmModels = append(vmModels, vmModel)
 	}
 
 	vmsValue, diags := types.ListValueFrom(ctx, types.ObjectType{
 		AttrTypes: map[string]attr.Type{
 			"hostname":   types.StringType,
+			"host_group": types.StringType,
 			"ip":         types.StringType,
 			"cpus":       types.Int64Type,
 			"ram_gb":     types.Int64Type,
 			"arch":       types.StringType,
 			"tags":       types.MapType{ElemType: types.StringType},
+			"secrets":    types.ListType{ElemType: types.StringType},
 			"created_at": types.StringType,
 		},
 	}, vmModels)
@@ -236,6 +358,16 @@ func (d *VMsDataSource) Read(ctx context.Context, req datasource.ReadRequest, re
 
 	data.VMs = vmsValue
 	data.TotalCount = types.Int64Value(int64(len(filteredVMs)))
+	data.TotalCPUs = types.Int64Value(totalCPUs)
+	data.TotalRamGB = types.Int64Value(slicer.BytesToGiB(totalRamBytes))
+
+	archCountsValue, diags := types.MapValueFrom(ctx, types.Int64Type, archCounts)
+	resp.Diagnostics.Append(diags...)
+	data.ArchCounts = archCountsValue
+
+	byGroupValue, diags := types.MapValueFrom(ctx, types.ListType{ElemType: types.StringType}, byGroup)
+	resp.Diagnostics.Append(diags...)
+	data.ByGroup = byGroupValue
 
 	tflog.Trace(ctx, "Listed VMs", map[string]interface{}{
 		"count": len(filteredVMs),
@@ -244,17 +376,120 @@ func (d *VMsDataSource) Read(ctx context.Context, req datasource.ReadRequest, re
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
-func matchesFilters(vm slicer.SlicerNode, filters []VMsFilterModel) bool {
-	if len(filters) == 0 {
-		return true
+// resolveHostGroups maps the hostname of each VM to its host group. Older APIs
+// do not report the host group in /nodes, in which case the VMs of every host
+// group are listed to find it.
+func (d *VMsDataSource) resolveHostGroups(ctx context.Context, vms []slicer.SlicerNode) (map[string]string, error) {
+	hostGroups := make(map[string]string, len(vms))
+	complete := true
+	for _, vm := range vms {
+		if vm.HostGroup == "" {
+			complete = false
+			continue
+		}
+		hostGroups[vm.Hostname] = vm.HostGroup
+	}
+	if complete {
+		return hostGroups, nil
+	}
+
+	groups, err := d.client.GetHostGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, group := range groups {
+		nodes, err := d.client.GetHostGroupNodes(ctx, group.Name)
+		if err != nil {
+			return nil, err
+		}
+		for _, node := range nodes {
+			if _, ok := hostGroups[node.Hostname]; !ok {
+				hostGroups[node.Hostname] = group.Name
+			}
+		}
 	}
 
+	return hostGroups, nil
+}
+
+// vmFilter is a parsed filter block. Zero fields do not filter.
+type vmFilter struct {
+	tag           string
+	hostGroup     string
+	arch          string
+	hostnameRegex *regexp.Regexp
+	createdAfter  time.Time
+}
+
+// parseVMFilters parses the filter blocks, reporting invalid regular
+// expressions and timestamps. Unknown values do not filter.
+func parseVMFilters(models []VMsFilterModel, diags *diag.Diagnostics) []vmFilter {
+	filters := make([]vmFilter, 0, len(models))
+	for i, model := range models {
+		filter := vmFilter{
+			tag:       model.Tag.ValueString(),
+			hostGroup: model.HostGroup.ValueString(),
+			arch:      model.Arch.ValueString(),
+		}
+
+		if !model.HostnameRegex.IsNull() && !model.HostnameRegex.IsUnknown() {
+			re, err := regexp.Compile(model.HostnameRegex.ValueString())
+			if err != nil {
+				diags.AddAttributeError(
+					path.Root("filter").AtListIndex(i).AtName("hostname_regex"),
+					"Invalid Regular Expression",
+					fmt.Sprintf("hostname_regex must be a valid regular expression: %s", err),
+				)
+			}
+			filter.hostnameRegex = re
+		}
+
+		if !model.CreatedAfter.IsNull() && !model.CreatedAfter.IsUnknown() {
+			t, err := time.Parse(time.RFC3339, model.CreatedAfter.ValueString())
+			if err != nil {
+				diags.AddAttributeError(
+					path.Root("filter").AtListIndex(i).AtName("created_after"),
+					"Invalid Timestamp",
+					fmt.Sprintf("created_after must be an RFC3339 timestamp such as '2024-01-01T00:00:00Z', got: %s", model.CreatedAfter.ValueString()),
+				)
+			}
+			filter.createdAfter = t
+		}
+
+		filters = append(filters, filter)
+	}
+
+	return filters
+}
+
+// serverVMFilter returns the predicates the API can evaluate. Every filter
+// block must match, so any host group or architecture narrows the result, as
+// does the latest created_after.
+func serverVMFilter(filters []vmFilter) slicer.SlicerListVMsFilter {
+	var listFilter slicer.SlicerListVMsFilter
+	for _, filter := range filters {
+		if listFilter.HostGroup == "" {
+			listFilter.HostGroup = filter.hostGroup
+		}
+		if listFilter.Arch == "" {
+			listFilter.Arch = filter.arch
+		}
+		if filter.createdAfter.After(listFilter.CreatedAfter) {
+			listFilter.CreatedAfter = filter.createdAfter
+		}
+	}
+	return listFilter
+}
+
+// matchesFilters reports whether vm matches every predicate of filters except
+// host_group, see matchesHostGroupFilters.
+func matchesFilters(vm slicer.SlicerNode, filters []vmFilter) bool {
 	for _, filter := range filters {
-		if !filter.Tag.IsNull() {
-			tagFilter := filter.Tag.ValueString()
+		if filter.tag != "" {
 			found := false
 			for _, tag := range vm.Tags {
-				if tag == tagFilter || strings.Contains(tag, tagFilter) {
+				if tag == filter.tag || strings.Contains(tag, filter.tag) {
 					found = true
 					break
 				}
@@ -263,6 +498,30 @@ func matchesFilters(vm slicer.SlicerNode, filters []VMsFilterModel) bool {
 				return false
 			}
 		}
+
+		if filter.arch != "" && vm.Arch != filter.arch {
+			return false
+		}
+
+		if filter.hostnameRegex != nil && !filter.hostnameRegex.MatchString(vm.Hostname) {
+			return false
+		}
+
+		if !filter.createdAfter.IsZero() && !vm.CreatedAt.After(filter.createdAfter) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchesHostGroupFilters reports whether a VM in hostGroup matches the
+// host_group predicate of every filter.
+func matchesHostGroupFilters(hostGroup string, filters []vmFilter) bool {
+	for _, filter := range filters {
+		if filter.hostGroup != "" && hostGroup != filter.hostGroup {
+			return false
+		}
 	}
 
 	return true
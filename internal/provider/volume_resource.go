@@ -0,0 +1,298 @@
+// Copyright (c) German Arutyunov
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gaarutyunov/terraform-provider-slicer/internal/slicer"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &VolumeResource{}
+var _ resource.ResourceWithImportState = &VolumeResource{}
+var _ resource.ResourceWithModifyPlan = &VolumeResource{}
+
+func NewVolumeResource() resource.Resource {
+	return &VolumeResource{}
+}
+
+// VolumeResource defines the resource implementation.
+type VolumeResource struct {
+	client      *slicer.SlicerClient
+	serverInfo  *slicer.SlicerServerInfo
+	tokenScopes []string
+}
+
+// VolumeResourceModel describes the resource data model.
+type VolumeResourceModel struct {
+	ID         types.String `tfsdk:"id"`
+	Name       types.String `tfsdk:"name"`
+	HostGroup  types.String `tfsdk:"host_group"`
+	SizeGB     types.Int64  `tfsdk:"size_gb"`
+	AttachedTo types.String `tfsdk:"attached_to"`
+	Device     types.String `tfsdk:"device"`
+	CreatedAt  types.String `tfsdk:"created_at"`
+}
+
+func (r *VolumeResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_volume"
+}
+
+func (r *VolumeResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages a persistent data disk. Volumes outlive the VMs they are attached to; attach them with `volume_attachments` on `slicer_vm`. Destroying the resource deletes the volume and its data.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The unique identifier of the volume (name).",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The name of the volume.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"host_group": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The host group whose VMs can attach the volume.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"size_gb": schema.Int64Attribute{
+				Required:            true,
+				MarkdownDescription: "Size of the volume in GB. Growing a volume is done in place; shrinking it replaces the volume, losing its data.",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplaceIf(
+						func(ctx context.Context, req planmodifier.Int64Request, resp *int64planmodifier.RequiresReplaceIfFuncResponse) {
+							resp.RequiresReplace = req.PlanValue.ValueInt64() < req.StateValue.ValueInt64()
+						},
+						"Volumes cannot be shrunk.",
+						"Volumes cannot be shrunk.",
+					),
+				},
+			},
+			"attached_to": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The hostname of the VM the volume is attached to. Null when it is detached.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"device": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The block device of the volume inside the VM it is attached to, e.g. `/dev/vdb`. Null when it is detached.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"created_at": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The time the volume was created (RFC3339).",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *VolumeResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*SlicerProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *SlicerProviderData, got: %T", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = providerData.Client
+	r.tokenScopes = providerData.TokenScopes
+	r.serverInfo = providerData.ServerInfo
+}
+
+func (r *VolumeResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	checkScope(req, r.tokenScopes, scopeVMsWrite, "slicer_volume", &resp.Diagnostics)
+
+	// Only creation depends on the server version
+	if req.Plan.Raw.IsNull() || !req.State.Raw.IsNull() {
+		return
+	}
+
+	checkCapability(r.serverInfo, capabilityVolumes, path.Root("name"), &resp.Diagnostics)
+}
+
+func (r *VolumeResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data VolumeResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Creating volume", map[string]interface{}{
+		"name":       data.Name.ValueString(),
+		"host_group": data.HostGroup.ValueString(),
+		"size_gb":    data.SizeGB.ValueInt64(),
+	})
+
+	volume, err := r.client.CreateVolume(ctx, slicer.SlicerCreateVolumeRequest{
+		Name:      data.Name.ValueString(),
+		HostGroup: data.HostGroup.ValueString(),
+		SizeBytes: slicer.GiB(data.SizeGB.ValueInt64()),
+	})
+	if errors.Is(err, slicer.ErrNotSupported) {
+		resp.Diagnostics.AddError(
+			"Volumes Not Supported",
+			"The Slicer API does not support volumes.",
+		)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create volume: %s", err))
+		return
+	}
+
+	data.ID = data.Name
+	setVolume(&data, volume)
+
+	tflog.Trace(ctx, "Created volume", map[string]interface{}{
+		"name": volume.Name,
+	})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *VolumeResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data VolumeResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	volumes, err := r.client.ListVolumes(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list volumes: %s", err))
+		return
+	}
+
+	for _, volume := range volumes {
+		if volume.Name == data.Name.ValueString() {
+			data.SizeGB = types.Int64Value(slicer.BytesToGiB(volume.SizeBytes))
+			setVolume(&data, &volume)
+			resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+			return
+		}
+	}
+
+	// Volume was deleted outside of Terraform
+	resp.State.RemoveResource(ctx)
+}
+
+func (r *VolumeResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data, state VolumeResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Shrinking replaces the volume, so only growth reaches Update
+	if data.SizeGB.Equal(state.SizeGB) {
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		return
+	}
+
+	tflog.Debug(ctx, "Resizing volume", map[string]interface{}{
+		"name":    data.Name.ValueString(),
+		"size_gb": data.SizeGB.ValueInt64(),
+	})
+
+	volume, err := r.client.ResizeVolume(ctx, data.Name.ValueString(), slicer.SlicerResizeVolumeRequest{
+		SizeBytes: slicer.GiB(data.SizeGB.ValueInt64()),
+	})
+	if errors.Is(err, slicer.ErrNotSupported) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("size_gb"),
+			"Volume Resize Not Supported",
+			"The Slicer API does not support resizing volumes. Recreate the volume to change its size.",
+		)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to resize volume: %s", err))
+		return
+	}
+
+	setVolume(&data, volume)
+
+	tflog.Trace(ctx, "Resized volume", map[string]interface{}{
+		"name": volume.Name,
+	})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *VolumeResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data VolumeResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Deleting volume", map[string]interface{}{
+		"name": data.Name.ValueString(),
+	})
+
+	err := r.client.DeleteVolume(ctx, data.Name.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete volume: %s", err))
+		return
+	}
+
+	tflog.Trace(ctx, "Deleted volume", map[string]interface{}{
+		"name": data.Name.ValueString(),
+	})
+}
+
+func (r *VolumeResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+}
+
+// setVolume stores the computed attributes of a volume returned by the API
+// in the model.
+func setVolume(data *VolumeResourceModel, volume *slicer.SlicerVolume) {
+	if volume.HostGroup != "" {
+		data.HostGroup = types.StringValue(volume.HostGroup)
+	}
+	data.AttachedTo = optionalString(volume.AttachedTo)
+	data.Device = optionalString(volume.Device)
+	data.CreatedAt = types.StringValue(volume.CreatedAt.Format(time.RFC3339))
+}
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -13,14 +14,27 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
 var (
 	// ErrSecretExists is an error returned when a secret with given name already exists.
 	ErrSecretExists = errors.New("secret already exists")
+
+	// ErrNotSupported is returned when the Slicer API does not expose an endpoint,
+	// e.g. optional admin operations on older or restricted installations.
+	ErrNotSupported = errors.New("operation not supported by the Slicer API")
+
+	// ErrVMNotFound is returned when a VM does not exist.
+	ErrVMNotFound = errors.New("VM not found")
+
+	// ErrFileTooLarge is returned when a file read from a VM exceeds the
+	// requested size limit.
+	ErrFileTooLarge = errors.New("file exceeds the size limit")
 )
 
 // SlicerClient handles all HTTP communication with the Slicer API.
@@ -29,19 +43,66 @@ type SlicerClient struct {
 	baseURL    string
 	token      string
 	userAgent  string
+
+	// createSem bounds the number of in-flight CreateVM calls, nil means unbounded.
+	createSem chan struct{}
+
+	// requestSem bounds the number of in-flight API requests, nil means unbounded.
+	requestSem chan struct{}
+
+	// pollInterval and pollJitter pace wait operations, see WithPolling.
+	pollInterval time.Duration
+	pollJitter   time.Duration
+
+	// limiter paces requests, see WithRateLimit, and maxRetries bounds the
+	// retries of throttled requests, see WithRetries.
+	limiter    rateLimiter
+	maxRetries int
+}
+
+// ClientOption configures optional behaviour of a SlicerClient.
+type ClientOption func(*SlicerClient)
+
+// WithMaxConcurrentCreates bounds the number of CreateVM calls that may be in
+// flight at once. A value of 1 serializes VM creation; 0 or less means unbounded.
+func WithMaxConcurrentCreates(n int) ClientOption {
+	return func(c *SlicerClient) {
+		if n > 0 {
+			c.createSem = make(chan struct{}, n)
+		}
+	}
+}
+
+// WithMaxConcurrentRequests bounds the number of API requests that may be in
+// flight at once. A request holds its slot until the response headers arrive,
+// so streamed response bodies do not block other requests. 0 or less means
+// unbounded.
+func WithMaxConcurrentRequests(n int) ClientOption {
+	return func(c *SlicerClient) {
+		if n > 0 {
+			c.requestSem = make(chan struct{}, n)
+		}
+	}
 }
 
 // NewSlicerClient creates a new Slicer API client.
-func NewSlicerClient(baseURL, token string, userAgent string, httpClient *http.Client) *SlicerClient {
+func NewSlicerClient(baseURL, token string, userAgent string, httpClient *http.Client, opts ...ClientOption) *SlicerClient {
 	if httpClient == nil {
 		httpClient = http.DefaultClient
 	}
-	return &SlicerClient{
+	c := &SlicerClient{
 		httpClient: httpClient,
 		baseURL:    baseURL,
 		token:      token,
 		userAgent:  userAgent,
+
+		pollInterval: DefaultPollInterval,
+		maxRetries:   DefaultMaxRetries,
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // makeJSONRequest creates and executes an HTTP request with proper authentication.
@@ -54,43 +115,527 @@ func (c *SlicerClient) makeJSONRequest(method, endpoint string, body interface{}
 func (c *SlicerClient) makeJSONRequestWithContext(ctx context.Context, method, endpoint string, body interface{}) (*http.Response, error) {
 	u, err := url.Parse(c.baseURL)
 	if err != nil {
-		return nil, fmt.Errorf("invalid base URL: %w", err)
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	u.Path = path.Join(u.Path, endpoint)
+
+	var reqBody io.Reader
+	if body != nil {
+		jsonBody, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request body: %w", err)
+		}
+		reqBody = bytes.NewReader(jsonBody)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	return c.do(req)
+}
+
+// do sends req, waiting for its turn if the client was created
+// WithRateLimit. Requests rejected with 429 Too Many Requests are retried
+// after the wait given by Retry-After, or an exponential backoff without
+// one, and hold back all other requests in the meantime. The response is
+// returned as is once the retries are used up, the body cannot be sent
+// again, or the wait would exceed retryMaxWait or outlast the request's
+// deadline.
+func (c *SlicerClient) do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	for attempt := 0; ; attempt++ {
+		if err := c.limiter.wait(ctx); err != nil {
+			return nil, fmt.Errorf("waiting for the request rate limit: %w", err)
+		}
+
+		res, err := c.send(req)
+		if err != nil || res.StatusCode != http.StatusTooManyRequests || attempt >= c.maxRetries {
+			return res, err
+		}
+
+		wait, ok := retryWait(res, attempt)
+		if !ok {
+			return res, nil
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Now().Add(wait).After(deadline) {
+			return res, nil
+		}
+		if !retryRequest(req, res) {
+			return res, nil
+		}
+
+		c.limiter.pause(time.Now().Add(wait))
+	}
+}
+
+// send sends req once. The HTTP client's timeout bounds requests without a
+// context deadline; requests with one, such as those made under a resource's
+// timeouts block, may run until the deadline instead. If the client was
+// created WithMaxConcurrentRequests, send waits for a free slot first.
+func (c *SlicerClient) send(req *http.Request) (*http.Response, error) {
+	if c.requestSem != nil {
+		select {
+		case c.requestSem <- struct{}{}:
+			defer func() { <-c.requestSem }()
+		case <-req.Context().Done():
+			return nil, fmt.Errorf("waiting for a free request slot: %w", req.Context().Err())
+		}
+	}
+
+	if _, ok := req.Context().Deadline(); ok && c.httpClient.Timeout > 0 {
+		client := *c.httpClient
+		client.Timeout = 0
+		return client.Do(req)
+	}
+	return c.httpClient.Do(req)
+}
+
+// GetServerInfo fetches the control plane version and the optional API
+// features it supports. Returns ErrNotSupported if the API predates the
+// info endpoint.
+func (c *SlicerClient) GetServerInfo(ctx context.Context) (*SlicerServerInfo, error) {
+	res, err := c.makeJSONRequestWithContext(ctx, http.MethodGet, "/info", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch server info: %w", err)
+	}
+
+	var body []byte
+	if res.Body != nil {
+		defer res.Body.Close()
+		body, _ = io.ReadAll(res.Body)
+	}
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil, ErrNotSupported
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API request failed: %s - %s", res.Status, string(body))
+	}
+
+	var info SlicerServerInfo
+	if err := json.Unmarshal(body, &info); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &info, nil
+}
+
+// GetHealth fetches the health of the control plane. An unhealthy control
+// plane answers with 503 and is reported through the returned status, not
+// as an error. Returns ErrNotSupported if the API has no health endpoint.
+func (c *SlicerClient) GetHealth(ctx context.Context) (*SlicerHealth, error) {
+	res, err := c.makeJSONRequestWithContext(ctx, http.MethodGet, "/healthz", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch health: %w", err)
+	}
+
+	var body []byte
+	if res.Body != nil {
+		defer res.Body.Close()
+		body, _ = io.ReadAll(res.Body)
+	}
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil, ErrNotSupported
+	}
+
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusServiceUnavailable {
+		return nil, fmt.Errorf("API request failed: %s - %s", res.Status, string(body))
+	}
+
+	var health SlicerHealth
+	if err := json.Unmarshal(body, &health); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if health.Status == "" {
+		health.Status = HealthStatusOK
+		if res.StatusCode == http.StatusServiceUnavailable {
+			health.Status = HealthStatusUnavailable
+		}
+	}
+
+	return &health, nil
+}
+
+// GetTokenInfo fetches the scopes granted to the API token in use.
+// Returns ErrNotSupported if the API does not support token introspection.
+func (c *SlicerClient) GetTokenInfo(ctx context.Context) (*SlicerTokenInfo, error) {
+	res, err := c.makeJSONRequestWithContext(ctx, http.MethodGet, "/token", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch token info: %w", err)
+	}
+
+	var body []byte
+	if res.Body != nil {
+		defer res.Body.Close()
+		body, _ = io.ReadAll(res.Body)
+	}
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil, ErrNotSupported
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API request failed: %s - %s", res.Status, string(body))
+	}
+
+	var info SlicerTokenInfo
+	if err := json.Unmarshal(body, &info); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &info, nil
+}
+
+// ListSubnets fetches the networks known to Slicer.
+// Returns ErrNotSupported if the API does not expose networks.
+func (c *SlicerClient) ListSubnets(ctx context.Context) ([]SlicerSubnet, error) {
+	res, err := c.makeJSONRequestWithContext(ctx, http.MethodGet, "/networks", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list subnets: %w", err)
+	}
+
+	var body []byte
+	if res.Body != nil {
+		defer res.Body.Close()
+		body, _ = io.ReadAll(res.Body)
+	}
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil, ErrNotSupported
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API request failed: %s - %s", res.Status, string(body))
+	}
+
+	var subnets []SlicerSubnet
+	if err := json.Unmarshal(body, &subnets); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return subnets, nil
+}
+
+// GetHostGroups fetches all host groups from the API.
+func (c *SlicerClient) GetHostGroups(ctx context.Context) ([]SlicerHostGroup, error) {
+	res, err := c.makeJSONRequestWithContext(ctx, http.MethodGet, "/hostgroup", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var body []byte
+	if res.Body != nil {
+		defer res.Body.Close()
+		body, _ = io.ReadAll(res.Body)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API request failed: %s - %s", res.Status, string(body))
+	}
+
+	var hostGroups []SlicerHostGroup
+	if err := json.Unmarshal(body, &hostGroups); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return hostGroups, nil
+}
+
+// CreateHostGroup creates a new host group.
+// Returns ErrNotSupported if the API does not allow managing host groups.
+func (c *SlicerClient) CreateHostGroup(ctx context.Context, hostGroup SlicerHostGroup) (*SlicerHostGroup, error) {
+	res, err := c.makeJSONRequestWithContext(ctx, http.MethodPost, "/hostgroup", hostGroup)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create host group: %w", err)
+	}
+
+	var body []byte
+	if res.Body != nil {
+		defer res.Body.Close()
+		body, _ = io.ReadAll(res.Body)
+	}
+
+	if res.StatusCode == http.StatusNotFound || res.StatusCode == http.StatusMethodNotAllowed {
+		return nil, ErrNotSupported
+	}
+
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("API request failed: %s - %s", res.Status, string(body))
+	}
+
+	var created SlicerHostGroup
+	if err := json.Unmarshal(body, &created); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &created, nil
+}
+
+// UpdateHostGroup updates the sizing of an existing host group.
+func (c *SlicerClient) UpdateHostGroup(ctx context.Context, groupName string, hostGroup SlicerHostGroup) (*SlicerHostGroup, error) {
+	endpoint := path.Join("/hostgroup", groupName)
+	res, err := c.makeJSONRequestWithContext(ctx, http.MethodPut, endpoint, hostGroup)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update host group: %w", err)
+	}
+
+	var body []byte
+	if res.Body != nil {
+		defer res.Body.Close()
+		body, _ = io.ReadAll(res.Body)
+	}
+
+	if res.StatusCode == http.StatusMethodNotAllowed {
+		return nil, ErrNotSupported
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API request failed: %s - %s", res.Status, string(body))
+	}
+
+	var updated SlicerHostGroup
+	if err := json.Unmarshal(body, &updated); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &updated, nil
+}
+
+// DeleteHostGroup removes a host group.
+func (c *SlicerClient) DeleteHostGroup(ctx context.Context, groupName string) error {
+	endpoint := path.Join("/hostgroup", groupName)
+	res, err := c.makeJSONRequestWithContext(ctx, http.MethodDelete, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete host group: %w", err)
+	}
+
+	var body []byte
+	if res.Body != nil {
+		defer res.Body.Close()
+		body, _ = io.ReadAll(res.Body)
+	}
+
+	if res.StatusCode == http.StatusMethodNotAllowed {
+		return ErrNotSupported
+	}
+
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("API request failed: %s - %s", res.Status, string(body))
+	}
+
+	return nil
+}
+
+// CordonHostGroup stops the scheduler from placing new VMs in a host group.
+// Existing VMs keep running. Returns ErrNotSupported if the API has no
+// maintenance endpoints.
+func (c *SlicerClient) CordonHostGroup(ctx context.Context, groupName, reason string) error {
+	return c.setHostGroupCordon(ctx, groupName, "cordon", &SlicerCordonRequest{Reason: reason})
+}
+
+// UncordonHostGroup allows VMs to be placed in a cordoned host group again.
+func (c *SlicerClient) UncordonHostGroup(ctx context.Context, groupName string) error {
+	return c.setHostGroupCordon(ctx, groupName, "uncordon", nil)
+}
+
+func (c *SlicerClient) setHostGroupCordon(ctx context.Context, groupName, action string, request interface{}) error {
+	endpoint := fmt.Sprintf("hostgroup/%s/%s", groupName, action)
+	res, err := c.makeJSONRequestWithContext(ctx, http.MethodPost, endpoint, request)
+	if err != nil {
+		return fmt.Errorf("failed to %s host group: %w", action, err)
+	}
+
+	var body []byte
+	if res.Body != nil {
+		defer res.Body.Close()
+		body, _ = io.ReadAll(res.Body)
+	}
+
+	if res.StatusCode == http.StatusMethodNotAllowed {
+		return ErrNotSupported
+	}
+
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("API request failed: %s - %s", res.Status, string(body))
+	}
+
+	return nil
+}
+
+// EvacuateHostGroup moves all VMs out of a cordoned host group.
+// The call blocks until the API reports the evacuation as finished.
+// An error is returned if any VM could not be moved; the response still
+// lists the VMs that were migrated.
+func (c *SlicerClient) EvacuateHostGroup(ctx context.Context, groupName string) (*SlicerEvacuateResponse, error) {
+	endpoint := fmt.Sprintf("hostgroup/%s/evacuate", groupName)
+	res, err := c.makeJSONRequestWithContext(ctx, http.MethodPost, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to evacuate host group: %w", err)
+	}
+
+	var body []byte
+	if res.Body != nil {
+		defer res.Body.Close()
+		body, _ = io.ReadAll(res.Body)
+	}
+
+	if res.StatusCode == http.StatusMethodNotAllowed {
+		return nil, ErrNotSupported
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API request failed: %s - %s", res.Status, string(body))
+	}
+
+	var result SlicerEvacuateResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &result, vmFailuresError("evacuate", result.Errors)
+}
+
+// ListTemplates fetches all VM templates.
+func (c *SlicerClient) ListTemplates(ctx context.Context) ([]SlicerVMTemplate, error) {
+	res, err := c.makeJSONRequestWithContext(ctx, http.MethodGet, "/templates", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list templates: %w", err)
+	}
+
+	var body []byte
+	if res.Body != nil {
+		defer res.Body.Close()
+		body, _ = io.ReadAll(res.Body)
+	}
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil, ErrNotSupported
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API request failed: %s - %s", res.Status, string(body))
+	}
+
+	var templates []SlicerVMTemplate
+	if err := json.Unmarshal(body, &templates); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return templates, nil
+}
+
+// CreateTemplate creates a VM template.
+// Returns ErrNotSupported if the API does not support VM templates.
+func (c *SlicerClient) CreateTemplate(ctx context.Context, template SlicerVMTemplate) (*SlicerVMTemplate, error) {
+	return c.writeTemplate(ctx, http.MethodPost, "/templates", template)
+}
+
+// UpdateTemplate replaces the spec of an existing VM template.
+// VMs already created from the template are not changed.
+func (c *SlicerClient) UpdateTemplate(ctx context.Context, template SlicerVMTemplate) (*SlicerVMTemplate, error) {
+	return c.writeTemplate(ctx, http.MethodPut, path.Join("/templates", template.Name), template)
+}
+
+func (c *SlicerClient) writeTemplate(ctx context.Context, method, endpoint string, template SlicerVMTemplate) (*SlicerVMTemplate, error) {
+	res, err := c.makeJSONRequestWithContext(ctx, method, endpoint, template)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write template: %w", err)
+	}
+
+	var body []byte
+	if res.Body != nil {
+		defer res.Body.Close()
+		body, _ = io.ReadAll(res.Body)
+	}
+
+	if res.StatusCode == http.StatusNotFound || res.StatusCode == http.StatusMethodNotAllowed {
+		return nil, ErrNotSupported
+	}
+
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("API request failed: %s - %s", res.Status, string(body))
+	}
+
+	var written SlicerVMTemplate
+	if err := json.Unmarshal(body, &written); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &written, nil
+}
+
+// DeleteTemplate removes a VM template. VMs created from it keep running.
+func (c *SlicerClient) DeleteTemplate(ctx context.Context, name string) error {
+	res, err := c.makeJSONRequestWithContext(ctx, http.MethodDelete, path.Join("/templates", name), nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete template: %w", err)
+	}
+
+	var body []byte
+	if res.Body != nil {
+		defer res.Body.Close()
+		body, _ = io.ReadAll(res.Body)
+	}
+
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("API request failed: %s - %s", res.Status, string(body))
+	}
+
+	return nil
+}
+
+// ListIPReservations fetches the IP addresses reserved in a host group's pool.
+// Returns ErrNotSupported if the API does not support IP reservations.
+func (c *SlicerClient) ListIPReservations(ctx context.Context, groupName string) ([]SlicerIPReservation, error) {
+	res, err := c.makeJSONRequestWithContext(ctx, http.MethodGet, path.Join("/hostgroup", groupName, "reservations"), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list IP reservations: %w", err)
 	}
-	u.Path = path.Join(u.Path, endpoint)
 
-	var reqBody io.Reader
-	if body != nil {
-		jsonBody, err := json.Marshal(body)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal request body: %w", err)
-		}
-		reqBody = bytes.NewReader(jsonBody)
+	var body []byte
+	if res.Body != nil {
+		defer res.Body.Close()
+		body, _ = io.ReadAll(res.Body)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, u.String(), reqBody)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	if res.StatusCode == http.StatusNotFound {
+		return nil, ErrNotSupported
 	}
 
-	if c.userAgent != "" {
-		req.Header.Set("User-Agent", c.userAgent)
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API request failed: %s - %s", res.Status, string(body))
 	}
 
-	if body != nil {
-		req.Header.Set("Content-Type", "application/json")
-	}
-	if c.token != "" {
-		req.Header.Set("Authorization", "Bearer "+c.token)
+	var reservations []SlicerIPReservation
+	if err := json.Unmarshal(body, &reservations); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	return c.httpClient.Do(req)
+	return reservations, nil
 }
 
-// GetHostGroups fetches all host groups from the API.
-func (c *SlicerClient) GetHostGroups(ctx context.Context) ([]SlicerHostGroup, error) {
-	res, err := c.makeJSONRequestWithContext(ctx, http.MethodGet, "/hostgroup", nil)
+// CreateIPReservation reserves an IP address in a host group's pool. When
+// reservation.IP is empty the next free address is reserved.
+// Returns ErrNotSupported if the API does not support IP reservations.
+func (c *SlicerClient) CreateIPReservation(ctx context.Context, groupName string, reservation SlicerIPReservation) (*SlicerIPReservation, error) {
+	res, err := c.makeJSONRequestWithContext(ctx, http.MethodPost, path.Join("/hostgroup", groupName, "reservations"), reservation)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to create IP reservation: %w", err)
 	}
 
 	var body []byte
@@ -99,16 +644,40 @@ func (c *SlicerClient) GetHostGroups(ctx context.Context) ([]SlicerHostGroup, er
 		body, _ = io.ReadAll(res.Body)
 	}
 
-	if res.StatusCode != http.StatusOK {
+	if res.StatusCode == http.StatusNotFound || res.StatusCode == http.StatusMethodNotAllowed {
+		return nil, ErrNotSupported
+	}
+
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusCreated {
 		return nil, fmt.Errorf("API request failed: %s - %s", res.Status, string(body))
 	}
 
-	var hostGroups []SlicerHostGroup
-	if err := json.Unmarshal(body, &hostGroups); err != nil {
+	var created SlicerIPReservation
+	if err := json.Unmarshal(body, &created); err != nil {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	return hostGroups, nil
+	return &created, nil
+}
+
+// DeleteIPReservation releases a reserved IP address back to the pool.
+func (c *SlicerClient) DeleteIPReservation(ctx context.Context, groupName, ip string) error {
+	res, err := c.makeJSONRequestWithContext(ctx, http.MethodDelete, path.Join("/hostgroup", groupName, "reservations", ip), nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete IP reservation: %w", err)
+	}
+
+	var body []byte
+	if res.Body != nil {
+		defer res.Body.Close()
+		body, _ = io.ReadAll(res.Body)
+	}
+
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("API request failed: %s - %s", res.Status, string(body))
+	}
+
+	return nil
 }
 
 // GetHostGroupNodes fetches nodes for a specific host group.
@@ -210,6 +779,39 @@ func (c *SlicerClient) ListSecrets(ctx context.Context) ([]Secret, error) {
 	return secrets, nil
 }
 
+// GetSecretValue retrieves the data of a secret.
+// Returns ErrNotSupported if the API does not expose secret values; older
+// APIs also answer 404 for unknown secrets, so callers should check that the
+// secret exists before reporting that.
+func (c *SlicerClient) GetSecretValue(ctx context.Context, secretName string) (string, error) {
+	endpoint := path.Join("/secrets", secretName, "value")
+	res, err := c.makeJSONRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch secret value: %w", err)
+	}
+
+	var body []byte
+	if res.Body != nil {
+		defer res.Body.Close()
+		body, _ = io.ReadAll(res.Body)
+	}
+
+	if res.StatusCode == http.StatusNotFound || res.StatusCode == http.StatusMethodNotAllowed {
+		return "", ErrNotSupported
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("API request failed: %s - %s", res.Status, string(body))
+	}
+
+	var value SecretValue
+	if err := json.Unmarshal(body, &value); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return value.Data, nil
+}
+
 // CreateSecret creates a new secret.
 // Returns ErrSecretExists if a secret with the same name already exists.
 // An error is returned if creation fails.
@@ -283,6 +885,8 @@ func (c *SlicerClient) DeleteSecret(ctx context.Context, secretName string) erro
 
 // Exec executes a command on the specified node and streams the output.
 // The channel is unbuffered so the caller should read from it promptly to avoid blocking.
+// The exit code of the command is reported in ExitCode of the last result;
+// Error is only set when the command could not be run to completion.
 func (c *SlicerClient) Exec(ctx context.Context, nodeName string, execReq SlicerExecRequest) (chan SlicerExecWriteResult, error) {
 
 	resChan := make(chan SlicerExecWriteResult)
@@ -314,6 +918,20 @@ func (c *SlicerClient) Exec(ctx context.Context, nodeName string, execReq Slicer
 		q.Set("permissions", execReq.Permissions)
 	}
 
+	if len(execReq.OutputEncoding) > 0 {
+		q.Set("encoding", execReq.OutputEncoding)
+	}
+
+	if execReq.Timeout > 0 {
+		q.Set("timeout", execReq.Timeout.String())
+		if len(execReq.KillSignal) > 0 {
+			q.Set("signal", execReq.KillSignal)
+		}
+		if execReq.KillGracePeriod > 0 {
+			q.Set("grace_period", execReq.KillGracePeriod.String())
+		}
+	}
+
 	var bodyReader io.Reader
 
 	if stdin {
@@ -336,10 +954,12 @@ func (c *SlicerClient) Exec(ctx context.Context, nodeName string, execReq Slicer
 	}
 
 	req.Header.Set("Authorization", "Bearer "+c.token)
+	// Agents that support the framed v2 protocol answer with the same header
+	req.Header.Set(ExecProtocolHeader, ExecProtocolV2)
 
 	req.URL.RawQuery = q.Encode()
 
-	res, err := c.httpClient.Do(req)
+	res, err := c.do(req)
 	if err != nil {
 		return resChan, fmt.Errorf("failed to execute request: %w", err)
 	}
@@ -357,6 +977,11 @@ func (c *SlicerClient) Exec(ctx context.Context, nodeName string, execReq Slicer
 		return resChan, fmt.Errorf("no body received from VM")
 	}
 
+	if res.Header.Get(ExecProtocolHeader) == ExecProtocolV2 {
+		go c.streamExecV2(ctx, nodeName, res.Header.Get(ExecIDHeader), res.Body, resChan)
+		return resChan, nil
+	}
+
 	go func() {
 		r := bufio.NewReader(res.Body)
 
@@ -393,26 +1018,29 @@ func (c *SlicerClient) Exec(ctx context.Context, nodeName string, execReq Slicer
 				return
 			}
 
-			if result.Error != "" {
-				resChan <- SlicerExecWriteResult{
-					Timestamp: result.Timestamp,
-					Error:     fmt.Sprintf("failed to execute command: %s", result.Error),
-					Stdout:    result.Stdout,
-					Stderr:    result.Stderr,
+			if execReq.OutputEncoding == ExecOutputEncodingBase64 {
+				if err := decodeExecOutput(&result); err != nil {
+					resChan <- SlicerExecWriteResult{
+						Timestamp: result.Timestamp,
+						Error:     fmt.Sprintf("failed to decode output: %v", err),
+					}
+					return
 				}
-				return
 			}
 
-			if result.ExitCode != 0 {
+			if result.Error != "" {
 				resChan <- SlicerExecWriteResult{
 					Timestamp: result.Timestamp,
-					Error:     fmt.Sprintf("failed to execute command: %d", result.ExitCode),
+					Error:     fmt.Sprintf("failed to execute command: %s", result.Error),
 					Stdout:    result.Stdout,
 					Stderr:    result.Stderr,
+					ExitCode:  result.ExitCode,
 				}
 				return
 			}
 
+			// A non-zero exit code is a result, not an error; callers decide
+			// which exit codes count as success
 			resChan <- result
 		}
 
@@ -421,11 +1049,26 @@ func (c *SlicerClient) Exec(ctx context.Context, nodeName string, execReq Slicer
 	return resChan, nil
 }
 
+// decodeExecOutput decodes base64 encoded stdout and stderr chunks in place.
+func decodeExecOutput(result *SlicerExecWriteResult) error {
+	stdout, err := base64.StdEncoding.DecodeString(result.Stdout)
+	if err != nil {
+		return fmt.Errorf("stdout: %w", err)
+	}
+	stderr, err := base64.StdEncoding.DecodeString(result.Stderr)
+	if err != nil {
+		return fmt.Errorf("stderr: %w", err)
+	}
+	result.Stdout = string(stdout)
+	result.Stderr = string(stderr)
+	return nil
+}
+
 // CpToVM copies files from a local path to a VM path.
 // The localPath can be a file or directory. The tar stream is created
 // internally and sent to the VM.
 // uid and gid specify the ownership for extracted files (0 means use default).
-func (c *SlicerClient) CpToVM(ctx context.Context, vmName, localPath, vmPath string, uid, gid uint32, permissions, mode string) error {
+func (c *SlicerClient) CpToVM(ctx context.Context, vmName, localPath, vmPath string, uid, gid uint32, permissions, mode string, opts ...CpOption) error {
 	// Get absolute path to handle symlinks correctly
 	absSrc, err := filepath.Abs(localPath)
 	if err != nil {
@@ -437,20 +1080,33 @@ func (c *SlicerClient) CpToVM(ctx context.Context, vmName, localPath, vmPath str
 		return fmt.Errorf("source does not exist: %w", err)
 	}
 
+	var o cpOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var copyFn func(o cpOptions) error
 	switch mode {
 	default:
 		return fmt.Errorf("invalid mode: %s", mode)
 	case "tar":
-		if err := copyToVMTar(ctx, c, absSrc, vmName, vmPath, uid, gid, permissions); err != nil {
-			return err
+		copyFn = func(o cpOptions) error {
+			return copyToVMTar(ctx, c, absSrc, vmName, vmPath, uid, gid, permissions, o)
 		}
 	case "binary":
-		if err := copyToVMBinary(ctx, c, absSrc, vmName, vmPath, uid, gid, permissions); err != nil {
-			return err
+		copyFn = func(o cpOptions) error {
+			return copyToVMBinary(ctx, c, absSrc, vmName, vmPath, uid, gid, permissions, o)
 		}
 	}
 
-	return nil
+	err = copyFn(o)
+	if errors.Is(err, errUnsupportedEncoding) {
+		// The agent does not accept compressed uploads, send the data as-is
+		o.compress = false
+		err = copyFn(o)
+	}
+
+	return err
 }
 
 // CpFromVM copies files from a VM path to a local path.
@@ -471,6 +1127,54 @@ func (c *SlicerClient) CpFromVM(ctx context.Context, vmName, vmPath, localPath s
 
 }
 
+// ReadFileFromVM returns the contents of a file on a VM. ErrFileTooLarge is
+// returned when the file is larger than maxBytes; a maxBytes of 0 means
+// unlimited.
+func (c *SlicerClient) ReadFileFromVM(ctx context.Context, vmName, vmPath string, maxBytes int64) ([]byte, error) {
+	u, err := url.Parse(c.baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse API URL: %w", err)
+	}
+
+	u.Path = fmt.Sprintf("/vm/%s/cp", vmName)
+	u.RawQuery = url.Values{"path": {vmPath}}.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/octet-stream")
+	c.setAuthHeaders(req)
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(res.Body)
+		return nil, fmt.Errorf("failed to copy from VM: %s: %s", res.Status, strings.TrimSpace(string(body)))
+	}
+
+	var body io.Reader = res.Body
+	if maxBytes > 0 {
+		// Read one byte past the limit to tell a file of exactly maxBytes apart
+		body = io.LimitReader(res.Body, maxBytes+1)
+	}
+
+	content, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	if maxBytes > 0 && int64(len(content)) > maxBytes {
+		return nil, fmt.Errorf("%w of %d bytes", ErrFileTooLarge, maxBytes)
+	}
+
+	return content, nil
+}
+
 // GetVMStats fetches stats for all VMs or a specific VM if hostname is provided.
 // If hostname is empty, returns stats for all VMs.
 func (c *SlicerClient) GetVMStats(ctx context.Context, hostname string) ([]SlicerNodeStat, error) {
@@ -497,7 +1201,7 @@ func (c *SlicerClient) GetVMStats(ctx context.Context, hostname string) ([]Slice
 		req.Header.Set("Authorization", "Bearer "+c.token)
 	}
 
-	res, err := c.httpClient.Do(req)
+	res, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to perform GET request: %w", err)
 	}
@@ -546,7 +1250,7 @@ func (c *SlicerClient) GetVMLogs(ctx context.Context, hostname string, lines int
 		req.Header.Set("Authorization", "Bearer "+c.token)
 	}
 
-	res, err := c.httpClient.Do(req)
+	res, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch logs: %w", err)
 	}
@@ -571,6 +1275,12 @@ func (c *SlicerClient) GetVMLogs(ctx context.Context, hostname string, lines int
 
 // ListVMs fetches all VMs (nodes).
 func (c *SlicerClient) ListVMs(ctx context.Context) ([]SlicerNode, error) {
+	return c.ListVMsFiltered(ctx, SlicerListVMsFilter{})
+}
+
+// ListVMsFiltered fetches the VMs (nodes) matching filter. Older APIs ignore
+// the filter and return every VM, so callers must still filter the result.
+func (c *SlicerClient) ListVMsFiltered(ctx context.Context, filter SlicerListVMsFilter) ([]SlicerNode, error) {
 	u, err := url.Parse(c.baseURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse API URL: %w", err)
@@ -578,6 +1288,18 @@ func (c *SlicerClient) ListVMs(ctx context.Context) ([]SlicerNode, error) {
 
 	u.Path = "/nodes"
 
+	q := url.Values{}
+	if filter.HostGroup != "" {
+		q.Set("host_group", filter.HostGroup)
+	}
+	if filter.Arch != "" {
+		q.Set("arch", filter.Arch)
+	}
+	if !filter.CreatedAfter.IsZero() {
+		q.Set("created_after", filter.CreatedAfter.Format(time.RFC3339))
+	}
+	u.RawQuery = q.Encode()
+
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
@@ -590,7 +1312,7 @@ func (c *SlicerClient) ListVMs(ctx context.Context) ([]SlicerNode, error) {
 		req.Header.Set("Authorization", "Bearer "+c.token)
 	}
 
-	res, err := c.httpClient.Do(req)
+	res, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch VMs: %w", err)
 	}
@@ -613,6 +1335,53 @@ func (c *SlicerClient) ListVMs(ctx context.Context) ([]SlicerNode, error) {
 	return nodes, nil
 }
 
+// GetVM fetches a single VM by hostname.
+// Returns ErrVMNotFound if the VM does not exist. APIs without the single-VM
+// endpoint answer 404 for every VM, so a 404 is confirmed against ListVMs.
+func (c *SlicerClient) GetVM(ctx context.Context, hostname string) (*SlicerNode, error) {
+	res, err := c.makeJSONRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("node/%s", hostname), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch VM: %w", err)
+	}
+
+	var body []byte
+	if res.Body != nil {
+		defer res.Body.Close()
+		body, _ = io.ReadAll(res.Body)
+	}
+
+	if res.StatusCode == http.StatusNotFound || res.StatusCode == http.StatusMethodNotAllowed {
+		return c.findVM(ctx, hostname)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API request failed: %s - %s", res.Status, string(body))
+	}
+
+	var node SlicerNode
+	if err := json.Unmarshal(body, &node); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &node, nil
+}
+
+// findVM looks a VM up in the full VM list.
+func (c *SlicerClient) findVM(ctx context.Context, hostname string) (*SlicerNode, error) {
+	nodes, err := c.ListVMs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, node := range nodes {
+		if node.Hostname == hostname {
+			return &node, nil
+		}
+	}
+
+	return nil, ErrVMNotFound
+}
+
 // DeleteVM deletes a VM from a host group.
 func (c *SlicerClient) DeleteVM(ctx context.Context, groupName, hostname string) (*SlicerDeleteResponse, error) {
 	u, err := url.Parse(c.baseURL)
@@ -634,7 +1403,7 @@ func (c *SlicerClient) DeleteVM(ctx context.Context, groupName, hostname string)
 		req.Header.Set("Authorization", "Bearer "+c.token)
 	}
 
-	res, err := c.httpClient.Do(req)
+	res, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to delete VM: %w", err)
 	}
@@ -661,8 +1430,183 @@ func (c *SlicerClient) DeleteVM(ctx context.Context, groupName, hostname string)
 	return &delResp, nil
 }
 
+// UpdateVM changes the settings of a running VM in place.
+// Returns ErrNotSupported if the API does not allow updating VMs.
+func (c *SlicerClient) UpdateVM(ctx context.Context, groupName, hostname string, request SlicerUpdateNodeRequest) (*SlicerNode, error) {
+	endpoint := fmt.Sprintf("hostgroup/%s/nodes/%s", groupName, hostname)
+	res, err := c.makeJSONRequestWithContext(ctx, http.MethodPatch, endpoint, request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update VM: %w", err)
+	}
+
+	var body []byte
+	if res.Body != nil {
+		defer res.Body.Close()
+		body, _ = io.ReadAll(res.Body)
+	}
+
+	if res.StatusCode == http.StatusMethodNotAllowed {
+		return nil, ErrNotSupported
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API request failed: %s - %s", res.Status, string(body))
+	}
+
+	var node SlicerNode
+	if err := json.Unmarshal(body, &node); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &node, nil
+}
+
+// SetVMSchedule replaces the start/stop schedule of a VM.
+// Returns ErrNotSupported if the API does not support VM schedules.
+func (c *SlicerClient) SetVMSchedule(ctx context.Context, groupName, hostname string, schedule SlicerVMSchedule) error {
+	return c.vmScheduleRequest(ctx, http.MethodPut, groupName, hostname, schedule)
+}
+
+// DeleteVMSchedule removes the start/stop schedule of a VM.
+// The VM is left in its current power state.
+func (c *SlicerClient) DeleteVMSchedule(ctx context.Context, groupName, hostname string) error {
+	return c.vmScheduleRequest(ctx, http.MethodDelete, groupName, hostname, nil)
+}
+
+func (c *SlicerClient) vmScheduleRequest(ctx context.Context, method, groupName, hostname string, request interface{}) error {
+	endpoint := fmt.Sprintf("hostgroup/%s/nodes/%s/schedule", groupName, hostname)
+	res, err := c.makeJSONRequestWithContext(ctx, method, endpoint, request)
+	if err != nil {
+		return fmt.Errorf("failed to update VM schedule: %w", err)
+	}
+
+	var body []byte
+	if res.Body != nil {
+		defer res.Body.Close()
+		body, _ = io.ReadAll(res.Body)
+	}
+
+	if res.StatusCode == http.StatusMethodNotAllowed {
+		return ErrNotSupported
+	}
+
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("API request failed: %s - %s", res.Status, string(body))
+	}
+
+	return nil
+}
+
+// batchDeleteFallbackConcurrency bounds the number of parallel DeleteVM calls
+// used when the API does not support batch deletion.
+const batchDeleteFallbackConcurrency = 10
+
+// DeleteVMs deletes several VMs from a host group in a single API call.
+// If the API does not expose the batch endpoint, the VMs are deleted
+// individually with bounded concurrency.
+// An error is returned if any of the VMs could not be deleted; the response
+// still lists the VMs that were removed.
+func (c *SlicerClient) DeleteVMs(ctx context.Context, groupName string, hostnames []string) (*SlicerBatchDeleteResponse, error) {
+	if len(hostnames) == 0 {
+		return &SlicerBatchDeleteResponse{}, nil
+	}
+
+	endpoint := fmt.Sprintf("hostgroup/%s/nodes/delete", groupName)
+	res, err := c.makeJSONRequestWithContext(ctx, http.MethodPost, endpoint, SlicerBatchDeleteRequest{Hostnames: hostnames})
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete VMs: %w", err)
+	}
+
+	var body []byte
+	if res.Body != nil {
+		defer res.Body.Close()
+		body, _ = io.ReadAll(res.Body)
+	}
+
+	if res.StatusCode == http.StatusNotFound || res.StatusCode == http.StatusMethodNotAllowed {
+		return c.deleteVMsIndividually(ctx, groupName, hostnames)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status %s: %s", res.Status, strings.TrimSpace(string(body)))
+	}
+
+	var delResp SlicerBatchDeleteResponse
+	if err := json.Unmarshal(body, &delResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &delResp, vmFailuresError("delete", delResp.Errors)
+}
+
+func (c *SlicerClient) deleteVMsIndividually(ctx context.Context, groupName string, hostnames []string) (*SlicerBatchDeleteResponse, error) {
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		sem     = make(chan struct{}, batchDeleteFallbackConcurrency)
+		delResp = &SlicerBatchDeleteResponse{Errors: map[string]string{}}
+	)
+
+	for _, hostname := range hostnames {
+		wg.Add(1)
+		go func(hostname string) {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			_, err := c.DeleteVM(ctx, groupName, hostname)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				delResp.Errors[hostname] = err.Error()
+				return
+			}
+			delResp.Deleted = append(delResp.Deleted, hostname)
+		}(hostname)
+	}
+
+	wg.Wait()
+
+	sort.Strings(delResp.Deleted)
+
+	return delResp, vmFailuresError("delete", delResp.Errors)
+}
+
+// vmFailuresError combines per-VM failures of action into a single error.
+func vmFailuresError(action string, failures map[string]string) error {
+	if len(failures) == 0 {
+		return nil
+	}
+
+	hostnames := make([]string, 0, len(failures))
+	for hostname := range failures {
+		hostnames = append(hostnames, hostname)
+	}
+	sort.Strings(hostnames)
+
+	msgs := make([]string, 0, len(hostnames))
+	for _, hostname := range hostnames {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", hostname, failures[hostname]))
+	}
+
+	return fmt.Errorf("failed to %s %d VM(s): %s", action, len(failures), strings.Join(msgs, "; "))
+}
+
 // CreateVM creates a new VM in a host group.
+// If the client was created WithMaxConcurrentCreates, the call waits for a free
+// slot before contacting the API.
 func (c *SlicerClient) CreateVM(ctx context.Context, groupName string, request SlicerCreateNodeRequest) (*SlicerCreateNodeResponse, error) {
+	if c.createSem != nil {
+		select {
+		case c.createSem <- struct{}{}:
+			defer func() { <-c.createSem }()
+		case <-ctx.Done():
+			return nil, fmt.Errorf("waiting to create VM: %w", ctx.Err())
+		}
+	}
+
 	u, err := url.Parse(c.baseURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse API URL: %w", err)
@@ -688,7 +1632,7 @@ func (c *SlicerClient) CreateVM(ctx context.Context, groupName string, request S
 		req.Header.Set("Authorization", "Bearer "+c.token)
 	}
 
-	res, err := c.httpClient.Do(req)
+	res, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create VM: %w", err)
 	}
@@ -738,7 +1682,7 @@ func (c *SlicerClient) GetAgentHealth(ctx context.Context, hostname string, incl
 		req.Header.Set("Authorization", "Bearer "+c.token)
 	}
 
-	res, err := c.httpClient.Do(req)
+	res, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch agent health: %w", err)
 	}
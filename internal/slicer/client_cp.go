@@ -1,7 +1,9 @@
 package slicer
 
 import (
+	"compress/gzip"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -10,8 +12,96 @@ import (
 	"os/user"
 	"path/filepath"
 	"strconv"
+	"time"
 )
 
+// errUnsupportedEncoding is returned when the agent rejects a compressed upload.
+var errUnsupportedEncoding = errors.New("agent does not support compressed uploads")
+
+// CpOption configures optional behaviour of CpToVM.
+type CpOption func(*cpOptions)
+
+type cpOptions struct {
+	compress         bool
+	progress         ProgressFunc
+	progressInterval time.Duration
+}
+
+// ProgressFunc receives the number of bytes sent so far and the total number
+// of bytes to send, or -1 when the total is not known up front.
+type ProgressFunc func(sent, total int64)
+
+// WithCompression gzip-compresses the upload stream. Agents that do not accept
+// compressed uploads answer with 415 Unsupported Media Type, in which case
+// the upload is retried uncompressed.
+func WithCompression() CpOption {
+	return func(o *cpOptions) {
+		o.compress = true
+	}
+}
+
+// WithProgress reports upload progress to fn at most once per interval and
+// once more when the upload completes.
+func WithProgress(interval time.Duration, fn ProgressFunc) CpOption {
+	return func(o *cpOptions) {
+		o.progress = fn
+		o.progressInterval = interval
+	}
+}
+
+// progressReader wraps an upload body and reports the bytes read through it.
+type progressReader struct {
+	r        io.Reader
+	total    int64
+	sent     int64
+	fn       ProgressFunc
+	interval time.Duration
+	last     time.Time
+}
+
+// withProgress wraps r so that reads are reported to the configured ProgressFunc.
+func (o cpOptions) withProgress(r io.Reader, total int64) io.Reader {
+	if o.progress == nil {
+		return r
+	}
+	return &progressReader{
+		r:        r,
+		total:    total,
+		fn:       o.progress,
+		interval: o.progressInterval,
+		last:     time.Now(),
+	}
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.sent += int64(n)
+
+	if err == io.EOF || time.Since(p.last) >= p.interval {
+		p.fn(p.sent, p.total)
+		p.last = time.Now()
+	}
+
+	return n, err
+}
+
+// gzipStream returns a reader producing the gzip-compressed contents of r.
+// The caller must close the returned reader to release the compressing goroutine.
+func gzipStream(r io.Reader) io.ReadCloser {
+	pr, pw := io.Pipe()
+
+	go func() {
+		gw := gzip.NewWriter(pw)
+		_, err := io.Copy(gw, r)
+		if closeErr := gw.Close(); err == nil {
+			err = closeErr
+		}
+		pw.CloseWithError(err)
+	}()
+
+	return pr
+}
+
 // getCurrentUIDGID returns the current user's UID and GID.
 // On Windows, returns 0,0 (chown operations will be skipped).
 func getCurrentUIDGID() (uid, gid uint32) {
@@ -36,7 +126,7 @@ func (c *SlicerClient) setAuthHeaders(req *http.Request) {
 	}
 }
 
-func copyToVMBinary(ctx context.Context, c *SlicerClient, absSrc, vmName, vmPath string, uid, gid uint32, permissions string) error {
+func copyToVMBinary(ctx context.Context, c *SlicerClient, absSrc, vmName, vmPath string, uid, gid uint32, permissions string, o cpOptions) error {
 	u, err := url.Parse(c.baseURL)
 	if err != nil {
 		return fmt.Errorf("failed to parse API URL: %w", err)
@@ -65,20 +155,39 @@ func copyToVMBinary(ctx context.Context, c *SlicerClient, absSrc, vmName, vmPath
 	}
 	defer f.Close()
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), f)
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat source file: %w", err)
+	}
+
+	body := o.withProgress(f, info.Size())
+	if o.compress {
+		gz := gzipStream(body)
+		defer gz.Close()
+		body = gz
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), body)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/octet-stream")
+	if o.compress {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
 	c.setAuthHeaders(req)
 
-	res, err := c.httpClient.Do(req)
+	res, err := c.do(req)
 	if err != nil {
 		return fmt.Errorf("failed to perform POST request: %w", err)
 	}
 	defer res.Body.Close()
 
+	if o.compress && res.StatusCode == http.StatusUnsupportedMediaType {
+		return errUnsupportedEncoding
+	}
+
 	if res.StatusCode != http.StatusOK {
 		var body []byte
 		if res.Body != nil {
@@ -90,7 +199,7 @@ func copyToVMBinary(ctx context.Context, c *SlicerClient, absSrc, vmName, vmPath
 	return nil
 }
 
-func copyToVMTar(ctx context.Context, c *SlicerClient, absSrc, vmName, vmPath string, uid, gid uint32, permissions string) error {
+func copyToVMTar(ctx context.Context, c *SlicerClient, absSrc, vmName, vmPath string, uid, gid uint32, permissions string, o cpOptions) error {
 	parentDir := filepath.Dir(absSrc)
 	baseName := filepath.Base(absSrc)
 
@@ -99,7 +208,19 @@ func copyToVMTar(ctx context.Context, c *SlicerClient, absSrc, vmName, vmPath st
 
 	go func() {
 		defer pw.Close()
-		if err := StreamTarArchive(ctx, pw, parentDir, baseName); err != nil {
+
+		var err error
+		if o.compress {
+			gw := gzip.NewWriter(pw)
+			err = StreamTarArchive(ctx, gw, parentDir, baseName)
+			if closeErr := gw.Close(); err == nil {
+				err = closeErr
+			}
+		} else {
+			err = StreamTarArchive(ctx, pw, parentDir, baseName)
+		}
+
+		if err != nil {
 			pw.CloseWithError(fmt.Errorf("failed to stream tar: %w", err))
 		}
 	}()
@@ -124,15 +245,19 @@ func copyToVMTar(ctx context.Context, c *SlicerClient, absSrc, vmName, vmPath st
 	u.Path = fmt.Sprintf("/vm/%s/cp", vmName)
 	u.RawQuery = q.Encode()
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), pr)
+	// The size of the tar stream is not known up front
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), o.withProgress(pr, -1))
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/x-tar")
+	if o.compress {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
 	c.setAuthHeaders(req)
 
-	res, err := c.httpClient.Do(req)
+	res, err := c.do(req)
 	if err != nil {
 		return fmt.Errorf("failed to perform POST request: %w", err)
 	}
@@ -141,6 +266,10 @@ func copyToVMTar(ctx context.Context, c *SlicerClient, absSrc, vmName, vmPath st
 		defer res.Body.Close()
 	}
 
+	if o.compress && res.StatusCode == http.StatusUnsupportedMediaType {
+		return errUnsupportedEncoding
+	}
+
 	if res.StatusCode != http.StatusOK {
 		var body []byte
 		if res.Body != nil {
@@ -171,7 +300,7 @@ func copyFromVMTar(ctx context.Context, c *SlicerClient, vmName, vmPath, localPa
 	req.Header.Set("Accept", "application/x-tar")
 	c.setAuthHeaders(req)
 
-	res, err := c.httpClient.Do(req)
+	res, err := c.do(req)
 	if err != nil {
 		return fmt.Errorf("failed to perform GET request: %w", err)
 	}
@@ -225,7 +354,7 @@ func copyFromVMBinary(ctx context.Context, c *SlicerClient, vmName, vmPath, loca
 	req.Header.Set("Accept", "application/octet-stream")
 	c.setAuthHeaders(req)
 
-	res, err := c.httpClient.Do(req)
+	res, err := c.do(req)
 	if err != nil {
 		return fmt.Errorf("request failed: %w", err)
 	}
@@ -0,0 +1,132 @@
+package slicer
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCpToVM_Compressed(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "config.txt")
+	if err := os.WriteFile(src, []byte("hello hello hello"), 0o600); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Encoding") != "gzip" {
+			t.Errorf("Want gzip Content-Encoding, got '%s'", r.Header.Get("Content-Encoding"))
+		}
+		gr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		body, _ := io.ReadAll(gr)
+		if string(body) != "hello hello hello" {
+			t.Errorf("Want decompressed body 'hello hello hello', got '%s'", string(body))
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil)
+	err := client.CpToVM(context.Background(), "vm-1", src, "/etc/config.txt", 1000, 1000, "0644", "binary", WithCompression())
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestCpToVM_CompressionFallback(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "config.txt")
+	if err := os.WriteFile(src, []byte("plain"), 0o600); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("Content-Encoding") != "" {
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != "plain" {
+			t.Errorf("Want body 'plain', got '%s'", string(body))
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil)
+	err := client.CpToVM(context.Background(), "vm-1", src, "/etc/config.txt", 1000, 1000, "0644", "binary", WithCompression())
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("Want 2 requests, got %d", requests)
+	}
+}
+
+func TestCpToVM_Progress(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "image.bin")
+	if err := os.WriteFile(src, make([]byte, 1024), 0o600); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var lastSent, lastTotal int64
+	progress := func(sent, total int64) {
+		lastSent, lastTotal = sent, total
+	}
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil)
+	err := client.CpToVM(context.Background(), "vm-1", src, "/var/lib/image.bin", 0, 0, "0644", "binary", WithProgress(0, progress))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if lastSent != 1024 || lastTotal != 1024 {
+		t.Errorf("Want final progress 1024/1024, got %d/%d", lastSent, lastTotal)
+	}
+}
+
+func TestReadFileFromVM(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/vm/vm-1/cp" || r.URL.Query().Get("path") != "/var/lib/token" {
+			t.Errorf("Want /vm/vm-1/cp?path=/var/lib/token, got %s", r.URL.String())
+		}
+		_, _ = w.Write([]byte("K10abc"))
+	}))
+	defer server.Close()
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil)
+	content, err := client.ReadFileFromVM(context.Background(), "vm-1", "/var/lib/token", 6)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if string(content) != "K10abc" {
+		t.Errorf("Want content 'K10abc', got '%s'", string(content))
+	}
+}
+
+func TestReadFileFromVM_TooLarge(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("0123456789"))
+	}))
+	defer server.Close()
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil)
+	_, err := client.ReadFileFromVM(context.Background(), "vm-1", "/var/log/big", 4)
+	if !errors.Is(err, ErrFileTooLarge) {
+		t.Errorf("Want ErrFileTooLarge, got %v", err)
+	}
+}
@@ -0,0 +1,192 @@
+package slicer
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ExecProtocolHeader negotiates the exec stream protocol. The client offers
+// ExecProtocolV2 and agents that support it echo the header in the response;
+// older agents ignore it and stream v1 results.
+const (
+	ExecProtocolHeader = "X-Slicer-Exec-Protocol"
+	ExecProtocolV2     = "2"
+)
+
+// ExecIDHeader carries the ID of a v2 exec session. Agents that can resume
+// a stream after a disconnect set it on the exec response.
+const ExecIDHeader = "X-Slicer-Exec-ID"
+
+// execMaxResumes is how many times a v2 exec stream is resumed after losing
+// the connection before the command is reported as failed.
+const execMaxResumes = 5
+
+// errExecDisconnected marks a v2 stream that ended before the exit frame.
+var errExecDisconnected = errors.New("exec stream disconnected")
+
+// streamExecV2 reads the frames of a v2 exec stream from body and sends them
+// to resChan as SlicerExecWriteResults, one per output chunk, in the order
+// the agent produced them. When the connection drops and the agent reported
+// an exec ID, the stream is resumed after the last frame received, so the
+// command survives brief network outages.
+func (c *SlicerClient) streamExecV2(ctx context.Context, nodeName, execID string, body io.ReadCloser, resChan chan SlicerExecWriteResult) {
+	defer close(resChan)
+
+	var lastSeq uint64
+	resumes := 0
+	for {
+		err := readExecV2Frames(ctx, body, &lastSeq, resChan)
+		body.Close()
+		if !errors.Is(err, errExecDisconnected) || execID == "" || resumes >= execMaxResumes {
+			if err != nil {
+				sendExecResult(ctx, resChan, SlicerExecWriteResult{
+					Timestamp: time.Now(),
+					Error:     err.Error(),
+				})
+			}
+			return
+		}
+
+		// Back off before every attempt, the agent may still be unreachable
+		for {
+			resumes++
+			if waitErr := c.pollWait(ctx, c.pollInterval*time.Duration(resumes)); waitErr != nil {
+				sendExecResult(ctx, resChan, SlicerExecWriteResult{
+					Timestamp: time.Now(),
+					Error:     fmt.Sprintf("%v: %v", err, waitErr),
+				})
+				return
+			}
+
+			var resumeErr error
+			body, resumeErr = c.resumeExec(ctx, nodeName, execID, lastSeq)
+			if resumeErr == nil {
+				break
+			}
+			if resumes >= execMaxResumes {
+				sendExecResult(ctx, resChan, SlicerExecWriteResult{
+					Timestamp: time.Now(),
+					Error:     fmt.Sprintf("%v: unable to resume: %v", err, resumeErr),
+				})
+				return
+			}
+		}
+	}
+}
+
+// resumeExec reopens the stream of exec session execID with the frames
+// after seq.
+func (c *SlicerClient) resumeExec(ctx context.Context, nodeName, execID string, seq uint64) (io.ReadCloser, error) {
+	u, err := url.Parse(c.baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse API URL: %w", err)
+	}
+	u.Path = fmt.Sprintf("/vm/%s/exec/%s", nodeName, execID)
+	u.RawQuery = url.Values{"after": {strconv.FormatUint(seq, 10)}}.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	c.setAuthHeaders(req)
+	req.Header.Set(ExecProtocolHeader, ExecProtocolV2)
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		body, _ := io.ReadAll(res.Body)
+		return nil, fmt.Errorf("API request failed: %s - %s", res.Status, string(body))
+	}
+
+	return res.Body, nil
+}
+
+// readExecV2Frames sends the frames in body to resChan until the exit frame.
+// lastSeq is the sequence number of the last frame already received and is
+// advanced as frames arrive; frames at or before it are skipped, since a
+// resumed stream may repeat them. errExecDisconnected is returned when body
+// ends or fails before the exit frame.
+func readExecV2Frames(ctx context.Context, body io.Reader, lastSeq *uint64, resChan chan SlicerExecWriteResult) error {
+	scanner := bufio.NewScanner(body)
+	// Frames carry base64 encoded output, allow chunks of up to 1 MiB
+	scanner.Buffer(make([]byte, 64*1024), 2*1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var event SlicerExecEvent
+		if err := json.Unmarshal(line, &event); err != nil {
+			return fmt.Errorf("failed to decode exec frame: %v", err)
+		}
+
+		if event.Seq <= *lastSeq {
+			continue
+		}
+		if event.Seq != *lastSeq+1 {
+			return fmt.Errorf("exec stream out of order: want frame %d, got %d", *lastSeq+1, event.Seq)
+		}
+		*lastSeq = event.Seq
+
+		switch event.Type {
+		case ExecEventStdout, ExecEventStderr:
+			data, err := base64.StdEncoding.DecodeString(event.Data)
+			if err != nil {
+				return fmt.Errorf("failed to decode output: %s: %v", event.Type, err)
+			}
+
+			result := SlicerExecWriteResult{Timestamp: event.Timestamp}
+			if event.Type == ExecEventStdout {
+				result.Stdout = string(data)
+			} else {
+				result.Stderr = string(data)
+			}
+			if !sendExecResult(ctx, resChan, result) {
+				return nil
+			}
+
+		case ExecEventError:
+			return fmt.Errorf("failed to execute command: %s", event.Data)
+
+		case ExecEventExit:
+			sendExecResult(ctx, resChan, SlicerExecWriteResult{Timestamp: event.Timestamp, ExitCode: event.ExitCode})
+			return nil
+
+		default:
+			// Frames added by newer agents are skipped
+		}
+	}
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("%w: failed to read response: %v", errExecDisconnected, err)
+	}
+	return fmt.Errorf("%w before the exit frame", errExecDisconnected)
+}
+
+// sendExecResult sends result unless ctx is done first.
+func sendExecResult(ctx context.Context, resChan chan SlicerExecWriteResult, result SlicerExecWriteResult) bool {
+	select {
+	case resChan <- result:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
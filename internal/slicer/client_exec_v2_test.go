@@ -0,0 +1,124 @@
+package slicer
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExec_V2Protocol(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(ExecProtocolHeader) != ExecProtocolV2 {
+			t.Errorf("Want %s: %s, got '%s'", ExecProtocolHeader, ExecProtocolV2, r.Header.Get(ExecProtocolHeader))
+		}
+		w.Header().Set(ExecProtocolHeader, ExecProtocolV2)
+		// "one\n", "two\n", "three\n"
+		_, _ = w.Write([]byte(strings.Join([]string{
+			`{"seq":1,"type":"stdout","data":"b25lCg=="}`,
+			`{"seq":2,"type":"stderr","data":"dHdvCg=="}`,
+			`{"seq":3,"type":"stdout","data":"dGhyZWUK"}`,
+			`{"seq":4,"type":"exit","exit_code":0}`,
+		}, "\n") + "\n"))
+	}))
+	defer server.Close()
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil)
+	resChan, err := client.Exec(context.Background(), "vm-1", SlicerExecRequest{Command: "run"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var chunks []string
+	for res := range resChan {
+		if res.Error != "" {
+			t.Fatalf("Unexpected error: %s", res.Error)
+		}
+		if res.Stdout != "" {
+			chunks = append(chunks, "stdout:"+res.Stdout)
+		}
+		if res.Stderr != "" {
+			chunks = append(chunks, "stderr:"+res.Stderr)
+		}
+	}
+
+	want := []string{"stdout:one\n", "stderr:two\n", "stdout:three\n"}
+	if !reflect.DeepEqual(chunks, want) {
+		t.Errorf("Want chunks %q, got %q", want, chunks)
+	}
+}
+
+func TestExec_V2ProtocolExitCode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(ExecProtocolHeader, ExecProtocolV2)
+		_, _ = w.Write([]byte(`{"seq":1,"type":"exit","exit_code":3}` + "\n"))
+	}))
+	defer server.Close()
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil)
+	resChan, err := client.Exec(context.Background(), "vm-1", SlicerExecRequest{Command: "false"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var last SlicerExecWriteResult
+	for res := range resChan {
+		last = res
+	}
+	if last.Error != "" {
+		t.Errorf("Want no error for a non-zero exit, got '%s'", last.Error)
+	}
+	if last.ExitCode != 3 {
+		t.Errorf("Want exit code 3, got %d", last.ExitCode)
+	}
+}
+
+func TestExec_V2ProtocolResume(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(ExecProtocolHeader, ExecProtocolV2)
+
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/vm/vm-1/exec":
+			w.Header().Set(ExecIDHeader, "e1")
+			// The connection drops before the exit frame
+			_, _ = w.Write([]byte(strings.Join([]string{
+				`{"seq":1,"type":"stdout","data":"b25lCg=="}`,
+				`{"seq":2,"type":"stdout","data":"dHdvCg=="}`,
+			}, "\n") + "\n"))
+		case r.Method == http.MethodGet && r.URL.Path == "/vm/vm-1/exec/e1":
+			if r.URL.Query().Get("after") != "2" {
+				t.Errorf("Want after=2, got '%s'", r.URL.Query().Get("after"))
+			}
+			// Frame 2 is repeated and must be skipped
+			_, _ = w.Write([]byte(strings.Join([]string{
+				`{"seq":2,"type":"stdout","data":"dHdvCg=="}`,
+				`{"seq":3,"type":"stdout","data":"dGhyZWUK"}`,
+				`{"seq":4,"type":"exit","exit_code":0}`,
+			}, "\n") + "\n"))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil, WithPolling(time.Millisecond, 0))
+	resChan, err := client.Exec(context.Background(), "vm-1", SlicerExecRequest{Command: "run"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var stdout string
+	for res := range resChan {
+		if res.Error != "" {
+			t.Fatalf("Unexpected error: %s", res.Error)
+		}
+		stdout += res.Stdout
+	}
+
+	if stdout != "one\ntwo\nthree\n" {
+		t.Errorf("Want stdout %q, got %q", "one\ntwo\nthree\n", stdout)
+	}
+}
@@ -0,0 +1,118 @@
+package slicer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+)
+
+// ListFirewallRules retrieves all firewall rules.
+// Returns ErrNotSupported if the API does not support network policies.
+func (c *SlicerClient) ListFirewallRules(ctx context.Context) ([]SlicerFirewallRule, error) {
+	res, err := c.makeJSONRequestWithContext(ctx, http.MethodGet, "/firewall/rules", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list firewall rules: %w", err)
+	}
+
+	var body []byte
+	if res.Body != nil {
+		defer res.Body.Close()
+		body, _ = io.ReadAll(res.Body)
+	}
+
+	if res.StatusCode == http.StatusNotFound || res.StatusCode == http.StatusMethodNotAllowed {
+		return nil, ErrNotSupported
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API request failed: %s - %s", res.Status, string(body))
+	}
+
+	var rules []SlicerFirewallRule
+	if err := json.Unmarshal(body, &rules); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return rules, nil
+}
+
+// CreateFirewallRule creates a firewall rule and returns it with the ID
+// assigned by the API.
+// Returns ErrNotSupported if the API does not support network policies.
+func (c *SlicerClient) CreateFirewallRule(ctx context.Context, rule SlicerFirewallRule) (*SlicerFirewallRule, error) {
+	res, err := c.makeJSONRequestWithContext(ctx, http.MethodPost, "/firewall/rules", rule)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create firewall rule: %w", err)
+	}
+
+	return decodeFirewallRule(res, http.StatusOK, http.StatusCreated)
+}
+
+// UpdateFirewallRule replaces the rule with the given ID.
+// Returns ErrNotSupported if the API does not support network policies.
+func (c *SlicerClient) UpdateFirewallRule(ctx context.Context, id string, rule SlicerFirewallRule) (*SlicerFirewallRule, error) {
+	res, err := c.makeJSONRequestWithContext(ctx, http.MethodPut, path.Join("/firewall/rules", id), rule)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update firewall rule: %w", err)
+	}
+
+	return decodeFirewallRule(res, http.StatusOK)
+}
+
+// DeleteFirewallRule removes a firewall rule. Deleting a rule that no longer
+// exists is not an error.
+func (c *SlicerClient) DeleteFirewallRule(ctx context.Context, id string) error {
+	res, err := c.makeJSONRequestWithContext(ctx, http.MethodDelete, path.Join("/firewall/rules", id), nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete firewall rule: %w", err)
+	}
+
+	var body []byte
+	if res.Body != nil {
+		defer res.Body.Close()
+		body, _ = io.ReadAll(res.Body)
+	}
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil
+	}
+
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("API request failed: %s - %s", res.Status, string(body))
+	}
+
+	return nil
+}
+
+// decodeFirewallRule reads a firewall rule from res, which must have one of
+// the given status codes. 404 and 405 mean the API does not support network
+// policies.
+func decodeFirewallRule(res *http.Response, okStatus ...int) (*SlicerFirewallRule, error) {
+	var body []byte
+	if res.Body != nil {
+		defer res.Body.Close()
+		body, _ = io.ReadAll(res.Body)
+	}
+
+	if res.StatusCode == http.StatusNotFound || res.StatusCode == http.StatusMethodNotAllowed {
+		return nil, ErrNotSupported
+	}
+
+	ok := false
+	for _, status := range okStatus {
+		ok = ok || res.StatusCode == status
+	}
+	if !ok {
+		return nil, fmt.Errorf("API request failed: %s - %s", res.Status, string(body))
+	}
+
+	var rule SlicerFirewallRule
+	if err := json.Unmarshal(body, &rule); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &rule, nil
+}
@@ -0,0 +1,93 @@
+package slicer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCreateFirewallRule(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/firewall/rules" {
+			t.Errorf("Want POST /firewall/rules, got %s %s", r.Method, r.URL.Path)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"direction":"ingress","action":"allow","protocol":"tcp","port":"5432","source_tags":["role=app"],"target_tags":["role=db"]}` {
+			t.Errorf("Want rule in body, got '%s'", string(body))
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"fw-1","direction":"ingress","action":"allow","protocol":"tcp","port":"5432","source_tags":["role=app"],"target_tags":["role=db"]}`))
+	}))
+	defer server.Close()
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil)
+	rule, err := client.CreateFirewallRule(context.Background(), SlicerFirewallRule{
+		Direction:  FirewallDirectionIngress,
+		Action:     FirewallActionAllow,
+		Protocol:   "tcp",
+		Port:       "5432",
+		SourceTags: []string{"role=app"},
+		TargetTags: []string{"role=db"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if rule.ID != "fw-1" {
+		t.Errorf("Want ID fw-1, got '%s'", rule.ID)
+	}
+}
+
+func TestUpdateFirewallRule(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/firewall/rules/fw-1" {
+			t.Errorf("Want PUT /firewall/rules/fw-1, got %s %s", r.Method, r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{"id":"fw-1","direction":"ingress","action":"deny","protocol":"all","target_tags":["role=db"]}`))
+	}))
+	defer server.Close()
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil)
+	rule, err := client.UpdateFirewallRule(context.Background(), "fw-1", SlicerFirewallRule{
+		Direction:  FirewallDirectionIngress,
+		Action:     FirewallActionDeny,
+		Protocol:   "all",
+		TargetTags: []string{"role=db"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if rule.Action != FirewallActionDeny {
+		t.Errorf("Want action deny, got '%s'", rule.Action)
+	}
+}
+
+func TestListFirewallRules_NotSupported(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil)
+	_, err := client.ListFirewallRules(context.Background())
+	if !errors.Is(err, ErrNotSupported) {
+		t.Errorf("Want ErrNotSupported, got %v", err)
+	}
+}
+
+func TestDeleteFirewallRule_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || r.URL.Path != "/firewall/rules/fw-1" {
+			t.Errorf("Want DELETE /firewall/rules/fw-1, got %s %s", r.Method, r.URL.Path)
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil)
+	if err := client.DeleteFirewallRule(context.Background(), "fw-1"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
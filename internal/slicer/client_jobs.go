@@ -0,0 +1,112 @@
+package slicer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"time"
+)
+
+// ErrJobNotFound is returned when a job does not exist, e.g. because the
+// API has expired it.
+var ErrJobNotFound = errors.New("job not found")
+
+// jobPollMaxInterval caps the backoff of WaitForJob, which starts at the
+// client's poll interval and doubles after every poll.
+const jobPollMaxInterval = 30 * time.Second
+
+// SubmitJob starts an asynchronous job and returns it without waiting for it
+// to finish. Returns ErrNotSupported if the API does not support jobs.
+func (c *SlicerClient) SubmitJob(ctx context.Context, request SlicerJobRequest) (*SlicerJob, error) {
+	res, err := c.makeJSONRequestWithContext(ctx, http.MethodPost, "/jobs", request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to submit job: %w", err)
+	}
+
+	var body []byte
+	if res.Body != nil {
+		defer res.Body.Close()
+		body, _ = io.ReadAll(res.Body)
+	}
+
+	if res.StatusCode == http.StatusNotFound || res.StatusCode == http.StatusMethodNotAllowed {
+		return nil, ErrNotSupported
+	}
+
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusCreated && res.StatusCode != http.StatusAccepted {
+		return nil, fmt.Errorf("API request failed: %s - %s", res.Status, string(body))
+	}
+
+	var job SlicerJob
+	if err := json.Unmarshal(body, &job); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &job, nil
+}
+
+// GetJob fetches the current status of a job.
+// Returns ErrJobNotFound if the job does not exist.
+func (c *SlicerClient) GetJob(ctx context.Context, id string) (*SlicerJob, error) {
+	res, err := c.makeJSONRequestWithContext(ctx, http.MethodGet, path.Join("/jobs", id), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch job: %w", err)
+	}
+
+	var body []byte
+	if res.Body != nil {
+		defer res.Body.Close()
+		body, _ = io.ReadAll(res.Body)
+	}
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil, ErrJobNotFound
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API request failed: %s - %s", res.Status, string(body))
+	}
+
+	var job SlicerJob
+	if err := json.Unmarshal(body, &job); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &job, nil
+}
+
+// WaitForJob polls a job with exponential backoff until it finishes or ctx
+// is done. onProgress, if not nil, is called with every status received.
+// The finished job is returned; an error is returned if the job failed.
+func (c *SlicerClient) WaitForJob(ctx context.Context, id string, onProgress func(*SlicerJob)) (*SlicerJob, error) {
+	interval := c.pollInterval
+	maxInterval := max(jobPollMaxInterval, c.pollInterval)
+
+	for {
+		job, err := c.GetJob(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		if onProgress != nil {
+			onProgress(job)
+		}
+
+		if job.Done() {
+			if job.Status == JobStatusFailed {
+				return job, fmt.Errorf("job %s failed: %s", id, job.Error)
+			}
+			return job, nil
+		}
+
+		if err := c.pollWait(ctx, interval); err != nil {
+			return job, fmt.Errorf("timed out waiting for job %s at %d%%: %w", id, job.Progress, err)
+		}
+
+		interval = min(interval*2, maxInterval)
+	}
+}
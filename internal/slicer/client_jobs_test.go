@@ -0,0 +1,58 @@
+package slicer
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWaitForJob_PollsUntilDone(t *testing.T) {
+	responses := []string{
+		`{"id":"j1","status":"pending"}`,
+		`{"id":"j1","status":"running","progress":50}`,
+		`{"id":"j1","status":"succeeded","progress":100,"result":{"image":"ubuntu-24.04"}}`,
+	}
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/jobs/j1" {
+			t.Errorf("Want path /jobs/j1, got %s", r.URL.Path)
+		}
+		_, _ = w.Write([]byte(responses[polls]))
+		polls++
+	}))
+	defer server.Close()
+
+	var progress []int
+	client := NewSlicerClient(server.URL, "token", "agent", nil, WithPolling(time.Millisecond, 0))
+	job, err := client.WaitForJob(context.Background(), "j1", func(j *SlicerJob) {
+		progress = append(progress, j.Progress)
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if polls != 3 {
+		t.Errorf("Want 3 polls, got %d", polls)
+	}
+	if len(progress) != 3 || progress[1] != 50 {
+		t.Errorf("Want progress [0 50 100], got %v", progress)
+	}
+	if job.Result["image"] != "ubuntu-24.04" {
+		t.Errorf("Want result image ubuntu-24.04, got %q", job.Result["image"])
+	}
+}
+
+func TestWaitForJob_Failed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"id":"j1","status":"failed","error":"image checksum mismatch"}`))
+	}))
+	defer server.Close()
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil)
+	_, err := client.WaitForJob(context.Background(), "j1", nil)
+	if err == nil || !strings.Contains(err.Error(), "image checksum mismatch") {
+		t.Errorf("Want error with the job's message, got %v", err)
+	}
+}
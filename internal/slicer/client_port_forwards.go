@@ -0,0 +1,96 @@
+package slicer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+)
+
+// ListPortForwards retrieves all port forwards.
+// Returns ErrNotSupported if the API does not support port forwarding.
+func (c *SlicerClient) ListPortForwards(ctx context.Context) ([]SlicerPortForward, error) {
+	res, err := c.makeJSONRequestWithContext(ctx, http.MethodGet, "/port-forwards", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list port forwards: %w", err)
+	}
+
+	var body []byte
+	if res.Body != nil {
+		defer res.Body.Close()
+		body, _ = io.ReadAll(res.Body)
+	}
+
+	if res.StatusCode == http.StatusNotFound || res.StatusCode == http.StatusMethodNotAllowed {
+		return nil, ErrNotSupported
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API request failed: %s - %s", res.Status, string(body))
+	}
+
+	var forwards []SlicerPortForward
+	if err := json.Unmarshal(body, &forwards); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return forwards, nil
+}
+
+// CreatePortForward publishes a VM port and returns the port forward with the
+// ID and, if none was requested, the external port assigned by the API.
+// Returns ErrNotSupported if the API does not support port forwarding.
+func (c *SlicerClient) CreatePortForward(ctx context.Context, forward SlicerPortForward) (*SlicerPortForward, error) {
+	res, err := c.makeJSONRequestWithContext(ctx, http.MethodPost, "/port-forwards", forward)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create port forward: %w", err)
+	}
+
+	var body []byte
+	if res.Body != nil {
+		defer res.Body.Close()
+		body, _ = io.ReadAll(res.Body)
+	}
+
+	if res.StatusCode == http.StatusNotFound || res.StatusCode == http.StatusMethodNotAllowed {
+		return nil, ErrNotSupported
+	}
+
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("API request failed: %s - %s", res.Status, string(body))
+	}
+
+	var created SlicerPortForward
+	if err := json.Unmarshal(body, &created); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &created, nil
+}
+
+// DeletePortForward removes a port forward. Deleting a port forward that no
+// longer exists is not an error.
+func (c *SlicerClient) DeletePortForward(ctx context.Context, id string) error {
+	res, err := c.makeJSONRequestWithContext(ctx, http.MethodDelete, path.Join("/port-forwards", id), nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete port forward: %w", err)
+	}
+
+	var body []byte
+	if res.Body != nil {
+		defer res.Body.Close()
+		body, _ = io.ReadAll(res.Body)
+	}
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil
+	}
+
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("API request failed: %s - %s", res.Status, string(body))
+	}
+
+	return nil
+}
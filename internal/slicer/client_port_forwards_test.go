@@ -0,0 +1,69 @@
+package slicer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCreatePortForward(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/port-forwards" {
+			t.Errorf("Want POST /port-forwards, got %s %s", r.Method, r.URL.Path)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"hostname":"web-1","vm_port":8080,"protocol":"tcp"}` {
+			t.Errorf("Want port forward in body, got '%s'", string(body))
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"pf-1","hostname":"web-1","vm_port":8080,"external_port":30080,"protocol":"tcp","external_address":"192.168.1.10"}`))
+	}))
+	defer server.Close()
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil)
+	forward, err := client.CreatePortForward(context.Background(), SlicerPortForward{
+		Hostname: "web-1",
+		VMPort:   8080,
+		Protocol: "tcp",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if forward.ID != "pf-1" {
+		t.Errorf("Want ID pf-1, got '%s'", forward.ID)
+	}
+	if forward.ExternalPort != 30080 {
+		t.Errorf("Want external port 30080, got %d", forward.ExternalPort)
+	}
+}
+
+func TestListPortForwards_NotSupported(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil)
+	_, err := client.ListPortForwards(context.Background())
+	if !errors.Is(err, ErrNotSupported) {
+		t.Errorf("Want ErrNotSupported, got %v", err)
+	}
+}
+
+func TestDeletePortForward_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || r.URL.Path != "/port-forwards/pf-1" {
+			t.Errorf("Want DELETE /port-forwards/pf-1, got %s %s", r.Method, r.URL.Path)
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil)
+	if err := client.DeletePortForward(context.Background(), "pf-1"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
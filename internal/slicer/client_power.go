@@ -0,0 +1,51 @@
+package slicer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// StartVM powers on a stopped VM. Starting a running VM is not an error.
+// Returns ErrNotSupported if the API does not support power management.
+func (c *SlicerClient) StartVM(ctx context.Context, groupName, hostname string) error {
+	return c.vmPowerRequest(ctx, groupName, hostname, "start")
+}
+
+// StopVM shuts down a running VM. Its disk is kept, so it can be started
+// again. Stopping a stopped VM is not an error.
+// Returns ErrNotSupported if the API does not support power management.
+func (c *SlicerClient) StopVM(ctx context.Context, groupName, hostname string) error {
+	return c.vmPowerRequest(ctx, groupName, hostname, "stop")
+}
+
+// RebootVM restarts a running VM.
+// Returns ErrNotSupported if the API does not support power management.
+func (c *SlicerClient) RebootVM(ctx context.Context, groupName, hostname string) error {
+	return c.vmPowerRequest(ctx, groupName, hostname, "reboot")
+}
+
+func (c *SlicerClient) vmPowerRequest(ctx context.Context, groupName, hostname, action string) error {
+	endpoint := fmt.Sprintf("hostgroup/%s/nodes/%s/%s", groupName, hostname, action)
+	res, err := c.makeJSONRequestWithContext(ctx, http.MethodPost, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to %s VM: %w", action, err)
+	}
+
+	var body []byte
+	if res.Body != nil {
+		defer res.Body.Close()
+		body, _ = io.ReadAll(res.Body)
+	}
+
+	if res.StatusCode == http.StatusMethodNotAllowed {
+		return ErrNotSupported
+	}
+
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusAccepted && res.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("API request failed: %s - %s", res.Status, string(body))
+	}
+
+	return nil
+}
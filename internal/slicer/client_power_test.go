@@ -0,0 +1,51 @@
+package slicer
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestStopVM(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/hostgroup/w1/nodes/w1-1/stop" {
+			t.Errorf("Want POST /hostgroup/w1/nodes/w1-1/stop, got %s %s", r.Method, r.URL.Path)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil)
+	if err := client.StopVM(context.Background(), "w1", "w1-1"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestStartVM_NotSupported(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}))
+	defer server.Close()
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil)
+	err := client.StartVM(context.Background(), "w1", "w1-1")
+	if !errors.Is(err, ErrNotSupported) {
+		t.Errorf("Want ErrNotSupported, got %v", err)
+	}
+}
+
+func TestRebootVM_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		_, _ = w.Write([]byte("VM is stopped"))
+	}))
+	defer server.Close()
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil)
+	err := client.RebootVM(context.Background(), "w1", "w1-1")
+	if err == nil || errors.Is(err, ErrNotSupported) {
+		t.Errorf("Want API error, got %v", err)
+	}
+}
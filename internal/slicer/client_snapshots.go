@@ -0,0 +1,96 @@
+package slicer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+)
+
+// ListSnapshots retrieves all VM disk snapshots.
+// Returns ErrNotSupported if the API does not support snapshots.
+func (c *SlicerClient) ListSnapshots(ctx context.Context) ([]SlicerDiskSnapshot, error) {
+	res, err := c.makeJSONRequestWithContext(ctx, http.MethodGet, "/snapshots", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+
+	var body []byte
+	if res.Body != nil {
+		defer res.Body.Close()
+		body, _ = io.ReadAll(res.Body)
+	}
+
+	if res.StatusCode == http.StatusNotFound || res.StatusCode == http.StatusMethodNotAllowed {
+		return nil, ErrNotSupported
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API request failed: %s - %s", res.Status, string(body))
+	}
+
+	var snapshots []SlicerDiskSnapshot
+	if err := json.Unmarshal(body, &snapshots); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return snapshots, nil
+}
+
+// CreateSnapshot snapshots the disk of a persistent VM and returns the
+// snapshot once it is complete.
+// Returns ErrNotSupported if the API does not support snapshots.
+func (c *SlicerClient) CreateSnapshot(ctx context.Context, request SlicerCreateDiskSnapshotRequest) (*SlicerDiskSnapshot, error) {
+	res, err := c.makeJSONRequestWithContext(ctx, http.MethodPost, "/snapshots", request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create snapshot: %w", err)
+	}
+
+	var body []byte
+	if res.Body != nil {
+		defer res.Body.Close()
+		body, _ = io.ReadAll(res.Body)
+	}
+
+	if res.StatusCode == http.StatusNotFound || res.StatusCode == http.StatusMethodNotAllowed {
+		return nil, ErrNotSupported
+	}
+
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("API request failed: %s - %s", res.Status, string(body))
+	}
+
+	var snapshot SlicerDiskSnapshot
+	if err := json.Unmarshal(body, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &snapshot, nil
+}
+
+// DeleteSnapshot removes a snapshot. Deleting a snapshot that no longer
+// exists is not an error.
+func (c *SlicerClient) DeleteSnapshot(ctx context.Context, name string) error {
+	res, err := c.makeJSONRequestWithContext(ctx, http.MethodDelete, path.Join("/snapshots", name), nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete snapshot: %w", err)
+	}
+
+	var body []byte
+	if res.Body != nil {
+		defer res.Body.Close()
+		body, _ = io.ReadAll(res.Body)
+	}
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil
+	}
+
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("API request failed: %s - %s", res.Status, string(body))
+	}
+
+	return nil
+}
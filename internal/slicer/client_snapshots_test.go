@@ -0,0 +1,62 @@
+package slicer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCreateSnapshot(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/snapshots" {
+			t.Errorf("Want POST /snapshots, got %s %s", r.Method, r.URL.Path)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"name":"pre-deploy","hostname":"w1-1"}` {
+			t.Errorf("Want name and hostname in body, got '%s'", string(body))
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"name":"pre-deploy","hostname":"w1-1","size_bytes":1024,"created_at":"2025-11-14T13:28:34Z"}`))
+	}))
+	defer server.Close()
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil)
+	snapshot, err := client.CreateSnapshot(context.Background(), SlicerCreateDiskSnapshotRequest{Name: "pre-deploy", Hostname: "w1-1"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if snapshot.SizeBytes != 1024 {
+		t.Errorf("Want size 1024, got %d", snapshot.SizeBytes)
+	}
+}
+
+func TestListSnapshots_NotSupported(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil)
+	_, err := client.ListSnapshots(context.Background())
+	if !errors.Is(err, ErrNotSupported) {
+		t.Errorf("Want ErrNotSupported, got %v", err)
+	}
+}
+
+func TestDeleteSnapshot_AlreadyDeleted(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || r.URL.Path != "/snapshots/pre-deploy" {
+			t.Errorf("Want DELETE /snapshots/pre-deploy, got %s %s", r.Method, r.URL.Path)
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil)
+	if err := client.DeleteSnapshot(context.Background(), "pre-deploy"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
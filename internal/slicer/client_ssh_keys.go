@@ -0,0 +1,95 @@
+package slicer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+)
+
+// ListSSHKeys retrieves all stored SSH keys.
+// Returns ErrNotSupported if the API does not store SSH keys.
+func (c *SlicerClient) ListSSHKeys(ctx context.Context) ([]SlicerSSHKey, error) {
+	res, err := c.makeJSONRequestWithContext(ctx, http.MethodGet, "/ssh-keys", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list SSH keys: %w", err)
+	}
+
+	var body []byte
+	if res.Body != nil {
+		defer res.Body.Close()
+		body, _ = io.ReadAll(res.Body)
+	}
+
+	if res.StatusCode == http.StatusNotFound || res.StatusCode == http.StatusMethodNotAllowed {
+		return nil, ErrNotSupported
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API request failed: %s - %s", res.Status, string(body))
+	}
+
+	var keys []SlicerSSHKey
+	if err := json.Unmarshal(body, &keys); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return keys, nil
+}
+
+// CreateSSHKey stores an SSH public key under a name.
+// Returns ErrNotSupported if the API does not store SSH keys.
+func (c *SlicerClient) CreateSSHKey(ctx context.Context, request SlicerCreateSSHKeyRequest) (*SlicerSSHKey, error) {
+	res, err := c.makeJSONRequestWithContext(ctx, http.MethodPost, "/ssh-keys", request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SSH key: %w", err)
+	}
+
+	var body []byte
+	if res.Body != nil {
+		defer res.Body.Close()
+		body, _ = io.ReadAll(res.Body)
+	}
+
+	if res.StatusCode == http.StatusNotFound || res.StatusCode == http.StatusMethodNotAllowed {
+		return nil, ErrNotSupported
+	}
+
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("API request failed: %s - %s", res.Status, string(body))
+	}
+
+	var key SlicerSSHKey
+	if err := json.Unmarshal(body, &key); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &key, nil
+}
+
+// DeleteSSHKey removes a stored SSH key. VMs the key was injected into keep
+// it. Deleting a key that no longer exists is not an error.
+func (c *SlicerClient) DeleteSSHKey(ctx context.Context, name string) error {
+	res, err := c.makeJSONRequestWithContext(ctx, http.MethodDelete, path.Join("/ssh-keys", name), nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete SSH key: %w", err)
+	}
+
+	var body []byte
+	if res.Body != nil {
+		defer res.Body.Close()
+		body, _ = io.ReadAll(res.Body)
+	}
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil
+	}
+
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("API request failed: %s - %s", res.Status, string(body))
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package slicer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCreateSSHKey(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/ssh-keys" {
+			t.Errorf("Want POST /ssh-keys, got %s %s", r.Method, r.URL.Path)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"name":"alice","public_key":"ssh-ed25519 AAAA alice@example.com"}` {
+			t.Errorf("Want name and public key in body, got '%s'", string(body))
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"name":"alice","public_key":"ssh-ed25519 AAAA alice@example.com","fingerprint":"SHA256:abc","created_at":"2025-11-14T13:28:34Z"}`))
+	}))
+	defer server.Close()
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil)
+	key, err := client.CreateSSHKey(context.Background(), SlicerCreateSSHKeyRequest{Name: "alice", PublicKey: "ssh-ed25519 AAAA alice@example.com"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if key.Fingerprint != "SHA256:abc" {
+		t.Errorf("Want fingerprint SHA256:abc, got '%s'", key.Fingerprint)
+	}
+}
+
+func TestListSSHKeys_NotSupported(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil)
+	_, err := client.ListSSHKeys(context.Background())
+	if !errors.Is(err, ErrNotSupported) {
+		t.Errorf("Want ErrNotSupported, got %v", err)
+	}
+}
+
+func TestDeleteSSHKey_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || r.URL.Path != "/ssh-keys/alice" {
+			t.Errorf("Want DELETE /ssh-keys/alice, got %s %s", r.Method, r.URL.Path)
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil)
+	if err := client.DeleteSSHKey(context.Background(), "alice"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
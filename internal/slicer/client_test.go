@@ -1,10 +1,15 @@
 package slicer
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestMakeRequest_AuthHeaderWithToken(t *testing.T) {
@@ -117,6 +122,30 @@ func TestMakeRequest_WithoutBody(t *testing.T) {
 	}
 }
 
+func TestMakeRequest_ContextDeadlineOverridesClientTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewSlicerClient(server.URL, "token", "agent", &http.Client{Timeout: 50 * time.Millisecond})
+
+	// Without a deadline the client timeout applies
+	if _, err := client.makeJSONRequest(http.MethodGet, "/test", nil); err == nil {
+		t.Error("Want timeout error, got nil")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp, err := client.makeJSONRequestWithContext(ctx, http.MethodGet, "/test", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	resp.Body.Close()
+}
+
 func TestMakeRequest_InvalidJSON(t *testing.T) {
 	client := NewSlicerClient("http://localhost", "token", "agent", nil)
 
@@ -159,3 +188,616 @@ func TestMakeRequest_InvalidBaseURL(t *testing.T) {
 		t.Error("Want error, got nil")
 	}
 }
+
+func TestDeleteVMs_Batch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		want := "/hostgroup/w1/nodes/delete"
+		if r.Method != http.MethodPost || r.URL.Path != want {
+			t.Errorf("Want POST %s, got %s %s", want, r.Method, r.URL.Path)
+		}
+		body, _ := io.ReadAll(r.Body)
+		wantBody := `{"hostnames":["w1-1","w1-2"]}`
+		if string(body) != wantBody {
+			t.Errorf("Want body '%s', got '%s'", wantBody, string(body))
+		}
+		_, _ = w.Write([]byte(`{"deleted":["w1-1","w1-2"]}`))
+	}))
+	defer server.Close()
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil)
+	res, err := client.DeleteVMs(context.Background(), "w1", []string{"w1-1", "w1-2"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(res.Deleted) != 2 {
+		t.Errorf("Want 2 deleted VMs, got %v", res.Deleted)
+	}
+}
+
+func TestDeleteVMs_FallbackToIndividualDeletes(t *testing.T) {
+	var mu sync.Mutex
+	deleted := map[string]bool{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.URL.Path == "/hostgroup/w1/nodes/w1-2" {
+			_, _ = w.Write([]byte(`{"error":"vm is locked"}`))
+			return
+		}
+		mu.Lock()
+		deleted[r.URL.Path] = true
+		mu.Unlock()
+		_, _ = w.Write([]byte(`{"message":"deleted"}`))
+	}))
+	defer server.Close()
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil)
+	res, err := client.DeleteVMs(context.Background(), "w1", []string{"w1-1", "w1-2", "w1-3"})
+	if err == nil {
+		t.Error("Want error for locked VM, got nil")
+	}
+	if len(res.Deleted) != 2 || res.Deleted[0] != "w1-1" || res.Deleted[1] != "w1-3" {
+		t.Errorf("Want [w1-1 w1-3] deleted, got %v", res.Deleted)
+	}
+	if !deleted["/hostgroup/w1/nodes/w1-1"] || !deleted["/hostgroup/w1/nodes/w1-3"] {
+		t.Errorf("Want individual deletes, got %v", deleted)
+	}
+}
+
+func TestCreateVM_MaxConcurrentCreates(t *testing.T) {
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"hostname":"w1-1"}`))
+	}))
+	defer server.Close()
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil, WithMaxConcurrentCreates(1))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.CreateVM(context.Background(), "w1", SlicerCreateNodeRequest{}); err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if maxInFlight != 1 {
+		t.Errorf("Want at most 1 create in flight, got %d", maxInFlight)
+	}
+}
+
+func TestListVMs_MaxConcurrentRequests(t *testing.T) {
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil, WithMaxConcurrentRequests(2))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.ListVMs(context.Background()); err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if maxInFlight > 2 {
+		t.Errorf("Want at most 2 requests in flight, got %d", maxInFlight)
+	}
+}
+
+func TestListVMs_MaxConcurrentRequestsCanceled(t *testing.T) {
+	client := NewSlicerClient("http://127.0.0.1:0", "token", "agent", nil, WithMaxConcurrentRequests(1))
+	// Occupy the only slot
+	client.requestSem <- struct{}{}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := client.ListVMs(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Want context.Canceled, got %v", err)
+	}
+}
+
+func TestExec_Base64OutputEncoding(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("encoding") != ExecOutputEncodingBase64 {
+			t.Errorf("Want encoding=base64, got '%s'", r.URL.Query().Get("encoding"))
+		}
+		// "\xff\x00" is not valid UTF-8
+		_, _ = w.Write([]byte(`{"stdout":"/wA=","stderr":""}` + "\n"))
+	}))
+	defer server.Close()
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil)
+	resChan, err := client.Exec(context.Background(), "vm-1", SlicerExecRequest{
+		Command:        "cat",
+		OutputEncoding: ExecOutputEncodingBase64,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var stdout string
+	for res := range resChan {
+		if res.Error != "" {
+			t.Fatalf("Unexpected error: %s", res.Error)
+		}
+		stdout += res.Stdout
+	}
+
+	if stdout != "\xff\x00" {
+		t.Errorf("Want raw bytes %q, got %q", "\xff\x00", stdout)
+	}
+}
+
+func TestExec_NonZeroExitCode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"stdout":"no match\n"}` + "\n" + `{"exit_code":1}` + "\n"))
+	}))
+	defer server.Close()
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil)
+	resChan, err := client.Exec(context.Background(), "vm-1", SlicerExecRequest{Command: "grep"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var stdout string
+	var exitCode int
+	for res := range resChan {
+		if res.Error != "" {
+			t.Fatalf("Unexpected error: %s", res.Error)
+		}
+		stdout += res.Stdout
+		exitCode = res.ExitCode
+	}
+
+	if exitCode != 1 {
+		t.Errorf("Want exit code 1, got %d", exitCode)
+	}
+	if stdout != "no match\n" {
+		t.Errorf("Want stdout %q, got %q", "no match\n", stdout)
+	}
+}
+
+func TestCreateHostGroup_NotSupported(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/hostgroup" {
+			t.Errorf("Want POST /hostgroup, got %s %s", r.Method, r.URL.Path)
+		}
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}))
+	defer server.Close()
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil)
+	_, err := client.CreateHostGroup(context.Background(), SlicerHostGroup{Name: "w1-medium", CPUs: 2})
+	if !errors.Is(err, ErrNotSupported) {
+		t.Errorf("Want ErrNotSupported, got %v", err)
+	}
+}
+
+func TestEvacuateHostGroup_PartialFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/hostgroup/w1/evacuate" {
+			t.Errorf("Want POST /hostgroup/w1/evacuate, got %s %s", r.Method, r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{"migrated":["w1-1"],"errors":{"w1-2":"no capacity"}}`))
+	}))
+	defer server.Close()
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil)
+	result, err := client.EvacuateHostGroup(context.Background(), "w1")
+	if err == nil {
+		t.Error("Want error for failed migration, got nil")
+	}
+	if result == nil || len(result.Migrated) != 1 || result.Migrated[0] != "w1-1" {
+		t.Errorf("Want migrated [w1-1], got %v", result)
+	}
+}
+
+func TestGetServerInfo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/info" {
+			t.Errorf("Want /info, got %s", r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{"version":"0.1.40","features":["exec_base64"],"agent_versions":{"vm-1":"0.1.2"}}`))
+	}))
+	defer server.Close()
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil)
+	info, err := client.GetServerInfo(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if info.Version != "0.1.40" {
+		t.Errorf("Want version '0.1.40', got '%s'", info.Version)
+	}
+	if len(info.Features) != 1 || info.Features[0] != "exec_base64" {
+		t.Errorf("Want features [exec_base64], got %v", info.Features)
+	}
+	if info.AgentVersions["vm-1"] != "0.1.2" {
+		t.Errorf("Want agent version '0.1.2', got '%s'", info.AgentVersions["vm-1"])
+	}
+}
+
+func TestGetHealth_Unavailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/healthz" {
+			t.Errorf("Want /healthz, got %s", r.URL.Path)
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"checks":{"database":"connection refused"}}`))
+	}))
+	defer server.Close()
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil)
+	health, err := client.GetHealth(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if health.Healthy() {
+		t.Errorf("Want unhealthy, got status '%s'", health.Status)
+	}
+	if health.Checks["database"] != "connection refused" {
+		t.Errorf("Want database check 'connection refused', got '%s'", health.Checks["database"])
+	}
+}
+
+func TestGetTokenInfo_NotSupported(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil)
+	_, err := client.GetTokenInfo(context.Background())
+	if !errors.Is(err, ErrNotSupported) {
+		t.Errorf("Want ErrNotSupported, got %v", err)
+	}
+}
+
+func TestUpdateVM(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch || r.URL.Path != "/hostgroup/w1/nodes/w1-1" {
+			t.Errorf("Want PATCH /hostgroup/w1/nodes/w1-1, got %s %s", r.Method, r.URL.Path)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"priority":"high"}` {
+			t.Errorf("Want priority in body, got '%s'", string(body))
+		}
+		_, _ = w.Write([]byte(`{"hostname":"w1-1","priority":"high"}`))
+	}))
+	defer server.Close()
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil)
+	node, err := client.UpdateVM(context.Background(), "w1", "w1-1", SlicerUpdateNodeRequest{Priority: "high"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if node.Priority != "high" {
+		t.Errorf("Want priority 'high', got '%s'", node.Priority)
+	}
+}
+
+func TestUpdateVM_Tags(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		_, _ = w.Write([]byte(`{"hostname":"w1-1"}`))
+	}))
+	defer server.Close()
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil)
+	tags := []string{"env=dev"}
+	if _, err := client.UpdateVM(context.Background(), "w1", "w1-1", SlicerUpdateNodeRequest{Tags: &tags}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// An empty list removes all tags, so it must be sent
+	if _, err := client.UpdateVM(context.Background(), "w1", "w1-1", SlicerUpdateNodeRequest{Tags: &[]string{}}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := []string{`{"tags":["env=dev"]}`, `{"tags":[]}`}
+	for i, body := range bodies {
+		if body != want[i] {
+			t.Errorf("Want body '%s', got '%s'", want[i], body)
+		}
+	}
+}
+
+func TestUpdateVM_Metadata(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// An empty map removes all metadata, so it must be sent
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"metadata":{}}` {
+			t.Errorf("Want body '{\"metadata\":{}}', got '%s'", body)
+		}
+		_, _ = w.Write([]byte(`{"hostname":"w1-1"}`))
+	}))
+	defer server.Close()
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil)
+	if _, err := client.UpdateVM(context.Background(), "w1", "w1-1", SlicerUpdateNodeRequest{Metadata: &map[string]string{}}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestGetVM(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/node/w1-1" {
+			t.Errorf("Want GET /node/w1-1, got %s %s", r.Method, r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{"hostname":"w1-1","ip":"192.168.137.2/24"}`))
+	}))
+	defer server.Close()
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil)
+	node, err := client.GetVM(context.Background(), "w1-1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if node.IP != "192.168.137.2/24" {
+		t.Errorf("Want IP '192.168.137.2/24', got '%s'", node.IP)
+	}
+}
+
+func TestGetVM_FallbackToList(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/nodes" {
+			_, _ = w.Write([]byte(`[{"hostname":"w1-1"},{"hostname":"w1-2"}]`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil)
+	node, err := client.GetVM(context.Background(), "w1-2")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if node.Hostname != "w1-2" {
+		t.Errorf("Want hostname 'w1-2', got '%s'", node.Hostname)
+	}
+
+	_, err = client.GetVM(context.Background(), "w1-3")
+	if !errors.Is(err, ErrVMNotFound) {
+		t.Errorf("Want ErrVMNotFound, got %v", err)
+	}
+}
+
+func TestGetSecretValue(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/secrets/db-password/value" {
+			t.Errorf("Want GET /secrets/db-password/value, got %s %s", r.Method, r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{"data":"hunter2"}`))
+	}))
+	defer server.Close()
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil)
+	value, err := client.GetSecretValue(context.Background(), "db-password")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if value != "hunter2" {
+		t.Errorf("Want value 'hunter2', got '%s'", value)
+	}
+}
+
+func TestCreateSecretBase64(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/secrets" {
+			t.Errorf("Want POST /secrets, got %s %s", r.Method, r.URL.Path)
+		}
+
+		var req CreateSecretRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("Unable to decode request: %v", err)
+		}
+		if req.Encoding != SecretEncodingBase64 {
+			t.Errorf("Want encoding '%s', got '%s'", SecretEncodingBase64, req.Encoding)
+		}
+		if req.Data != "AAEC/w==" {
+			t.Errorf("Want data 'AAEC/w==', got '%s'", req.Data)
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil)
+	err := client.CreateSecret(context.Background(), CreateSecretRequest{
+		Name:     "keystore",
+		Data:     "AAEC/w==",
+		Encoding: SecretEncodingBase64,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestSetVMSchedule(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/hostgroup/w1/nodes/w1-1/schedule" {
+			t.Errorf("Want PUT /hostgroup/w1/nodes/w1-1/schedule, got %s %s", r.Method, r.URL.Path)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"start":"0 8 * * 1-5","stop":"0 19 * * 1-5"}` {
+			t.Errorf("Want schedule in body, got '%s'", string(body))
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil)
+	err := client.SetVMSchedule(context.Background(), "w1", "w1-1", SlicerVMSchedule{
+		Start: "0 8 * * 1-5",
+		Stop:  "0 19 * * 1-5",
+	})
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestCreateTemplate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/templates" {
+			t.Errorf("Want POST /templates, got %s %s", r.Method, r.URL.Path)
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"name":"worker","cpus":2,"revision":1}`))
+	}))
+	defer server.Close()
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil)
+	template, err := client.CreateTemplate(context.Background(), SlicerVMTemplate{Name: "worker", CPUs: 2})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if template.Revision != 1 {
+		t.Errorf("Want revision 1, got %d", template.Revision)
+	}
+}
+
+func TestCreateIPReservation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/hostgroup/w1/reservations" {
+			t.Errorf("Want POST /hostgroup/w1/reservations, got %s %s", r.Method, r.URL.Path)
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"ip":"192.168.137.10","description":"dns"}`))
+	}))
+	defer server.Close()
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil)
+	reservation, err := client.CreateIPReservation(context.Background(), "w1", SlicerIPReservation{Description: "dns"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if reservation.IP != "192.168.137.10" {
+		t.Errorf("Want IP 192.168.137.10, got %s", reservation.IP)
+	}
+}
+
+func TestExec_TimeoutQueryParameters(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("timeout") != "5m0s" {
+			t.Errorf("Want timeout '5m0s', got '%s'", q.Get("timeout"))
+		}
+		if q.Get("signal") != "INT" {
+			t.Errorf("Want signal 'INT', got '%s'", q.Get("signal"))
+		}
+		if q.Get("grace_period") != "30s" {
+			t.Errorf("Want grace_period '30s', got '%s'", q.Get("grace_period"))
+		}
+		_, _ = w.Write([]byte(`{"stdout":"done"}` + "\n"))
+	}))
+	defer server.Close()
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil)
+	resChan, err := client.Exec(context.Background(), "vm-1", SlicerExecRequest{
+		Command:         "sleep",
+		Timeout:         5 * time.Minute,
+		KillSignal:      "INT",
+		KillGracePeriod: 30 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for range resChan {
+	}
+}
+
+func TestListVMsFiltered_QueryParameters(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/nodes" {
+			t.Errorf("Want path '/nodes', got '%s'", r.URL.Path)
+		}
+		q := r.URL.Query()
+		if q.Get("host_group") != "w1" {
+			t.Errorf("Want host_group 'w1', got '%s'", q.Get("host_group"))
+		}
+		if q.Get("arch") != "arm64" {
+			t.Errorf("Want arch 'arm64', got '%s'", q.Get("arch"))
+		}
+		if q.Get("created_after") != "2024-01-02T03:04:05Z" {
+			t.Errorf("Want created_after '2024-01-02T03:04:05Z', got '%s'", q.Get("created_after"))
+		}
+		_, _ = w.Write([]byte(`[{"hostname":"w1-1"}]`))
+	}))
+	defer server.Close()
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil)
+	nodes, err := client.ListVMsFiltered(context.Background(), SlicerListVMsFilter{
+		HostGroup:    "w1",
+		Arch:         "arm64",
+		CreatedAfter: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(nodes) != 1 {
+		t.Errorf("Want 1 node, got %d", len(nodes))
+	}
+}
+
+func TestListVMs_NoQueryParameters(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.RawQuery != "" {
+			t.Errorf("Want no query, got '%s'", r.URL.RawQuery)
+		}
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil)
+	if _, err := client.ListVMs(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
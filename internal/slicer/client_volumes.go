@@ -0,0 +1,154 @@
+package slicer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+)
+
+// ListVolumes retrieves all volumes.
+// Returns ErrNotSupported if the API does not support volumes.
+func (c *SlicerClient) ListVolumes(ctx context.Context) ([]SlicerVolume, error) {
+	res, err := c.makeJSONRequestWithContext(ctx, http.MethodGet, "/volumes", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list volumes: %w", err)
+	}
+
+	var body []byte
+	if res.Body != nil {
+		defer res.Body.Close()
+		body, _ = io.ReadAll(res.Body)
+	}
+
+	if res.StatusCode == http.StatusNotFound || res.StatusCode == http.StatusMethodNotAllowed {
+		return nil, ErrNotSupported
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API request failed: %s - %s", res.Status, string(body))
+	}
+
+	var volumes []SlicerVolume
+	if err := json.Unmarshal(body, &volumes); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return volumes, nil
+}
+
+// CreateVolume creates a detached volume.
+// Returns ErrNotSupported if the API does not support volumes.
+func (c *SlicerClient) CreateVolume(ctx context.Context, request SlicerCreateVolumeRequest) (*SlicerVolume, error) {
+	res, err := c.makeJSONRequestWithContext(ctx, http.MethodPost, "/volumes", request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create volume: %w", err)
+	}
+
+	return decodeVolume(res, http.StatusOK, http.StatusCreated)
+}
+
+// ResizeVolume grows a volume. Volumes cannot be shrunk.
+func (c *SlicerClient) ResizeVolume(ctx context.Context, name string, request SlicerResizeVolumeRequest) (*SlicerVolume, error) {
+	res, err := c.makeJSONRequestWithContext(ctx, http.MethodPatch, path.Join("/volumes", name), request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resize volume: %w", err)
+	}
+
+	return decodeVolume(res, http.StatusOK)
+}
+
+// DeleteVolume removes a detached volume and its data. Deleting a volume
+// that no longer exists is not an error.
+func (c *SlicerClient) DeleteVolume(ctx context.Context, name string) error {
+	res, err := c.makeJSONRequestWithContext(ctx, http.MethodDelete, path.Join("/volumes", name), nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete volume: %w", err)
+	}
+
+	var body []byte
+	if res.Body != nil {
+		defer res.Body.Close()
+		body, _ = io.ReadAll(res.Body)
+	}
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil
+	}
+
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("API request failed: %s - %s", res.Status, string(body))
+	}
+
+	return nil
+}
+
+// AttachVolume attaches a volume to a running VM and returns it with the
+// device it was attached as.
+// Returns ErrNotSupported if the API does not support volumes.
+func (c *SlicerClient) AttachVolume(ctx context.Context, groupName, hostname, volume string) (*SlicerVolume, error) {
+	endpoint := fmt.Sprintf("hostgroup/%s/nodes/%s/volumes", groupName, hostname)
+	res, err := c.makeJSONRequestWithContext(ctx, http.MethodPost, endpoint, SlicerAttachVolumeRequest{Volume: volume})
+	if err != nil {
+		return nil, fmt.Errorf("failed to attach volume: %w", err)
+	}
+
+	return decodeVolume(res, http.StatusOK, http.StatusCreated)
+}
+
+// DetachVolume detaches a volume from a VM. Its data is kept. Detaching a
+// volume that is not attached is not an error.
+func (c *SlicerClient) DetachVolume(ctx context.Context, groupName, hostname, volume string) error {
+	endpoint := fmt.Sprintf("hostgroup/%s/nodes/%s/volumes/%s", groupName, hostname, volume)
+	res, err := c.makeJSONRequestWithContext(ctx, http.MethodDelete, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to detach volume: %w", err)
+	}
+
+	var body []byte
+	if res.Body != nil {
+		defer res.Body.Close()
+		body, _ = io.ReadAll(res.Body)
+	}
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil
+	}
+
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("API request failed: %s - %s", res.Status, string(body))
+	}
+
+	return nil
+}
+
+// decodeVolume reads a volume from res, which must have one of the given
+// status codes. 404 and 405 mean the API does not support volumes.
+func decodeVolume(res *http.Response, okStatus ...int) (*SlicerVolume, error) {
+	var body []byte
+	if res.Body != nil {
+		defer res.Body.Close()
+		body, _ = io.ReadAll(res.Body)
+	}
+
+	if res.StatusCode == http.StatusNotFound || res.StatusCode == http.StatusMethodNotAllowed {
+		return nil, ErrNotSupported
+	}
+
+	ok := false
+	for _, status := range okStatus {
+		ok = ok || res.StatusCode == status
+	}
+	if !ok {
+		return nil, fmt.Errorf("API request failed: %s - %s", res.Status, string(body))
+	}
+
+	var volume SlicerVolume
+	if err := json.Unmarshal(body, &volume); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &volume, nil
+}
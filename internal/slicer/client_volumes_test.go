@@ -0,0 +1,85 @@
+package slicer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCreateVolume(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/volumes" {
+			t.Errorf("Want POST /volumes, got %s %s", r.Method, r.URL.Path)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"name":"pgdata","host_group":"w1","size_bytes":10737418240}` {
+			t.Errorf("Want name, host group and size in body, got '%s'", string(body))
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"name":"pgdata","host_group":"w1","size_bytes":10737418240,"created_at":"2025-11-14T13:28:34Z"}`))
+	}))
+	defer server.Close()
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil)
+	volume, err := client.CreateVolume(context.Background(), SlicerCreateVolumeRequest{Name: "pgdata", HostGroup: "w1", SizeBytes: GiB(10)})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if volume.SizeBytes != GiB(10) {
+		t.Errorf("Want size %d, got %d", GiB(10), volume.SizeBytes)
+	}
+}
+
+func TestAttachVolume(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/hostgroup/w1/nodes/w1-1/volumes" {
+			t.Errorf("Want POST /hostgroup/w1/nodes/w1-1/volumes, got %s %s", r.Method, r.URL.Path)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"volume":"pgdata"}` {
+			t.Errorf("Want volume in body, got '%s'", string(body))
+		}
+		_, _ = w.Write([]byte(`{"name":"pgdata","host_group":"w1","size_bytes":1024,"attached_to":"w1-1","device":"/dev/vdb"}`))
+	}))
+	defer server.Close()
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil)
+	volume, err := client.AttachVolume(context.Background(), "w1", "w1-1", "pgdata")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if volume.Device != "/dev/vdb" {
+		t.Errorf("Want device /dev/vdb, got '%s'", volume.Device)
+	}
+}
+
+func TestListVolumes_NotSupported(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil)
+	_, err := client.ListVolumes(context.Background())
+	if !errors.Is(err, ErrNotSupported) {
+		t.Errorf("Want ErrNotSupported, got %v", err)
+	}
+}
+
+func TestDetachVolume_NotAttached(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || r.URL.Path != "/hostgroup/w1/nodes/w1-1/volumes/pgdata" {
+			t.Errorf("Want DELETE /hostgroup/w1/nodes/w1-1/volumes/pgdata, got %s %s", r.Method, r.URL.Path)
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil)
+	if err := client.DetachVolume(context.Background(), "w1", "w1-1", "pgdata"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
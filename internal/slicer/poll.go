@@ -0,0 +1,62 @@
+package slicer
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+)
+
+// DefaultPollInterval is how often wait operations poll the API unless
+// WithPolling sets another interval.
+const DefaultPollInterval = time.Second
+
+// WithPolling sets the interval wait operations, such as WaitForJob, poll the
+// API at. Up to jitter is added to every wait at random so many waiters do
+// not poll in lockstep. Non-positive intervals keep DefaultPollInterval.
+func WithPolling(interval, jitter time.Duration) ClientOption {
+	return func(c *SlicerClient) {
+		if interval > 0 {
+			c.pollInterval = interval
+		}
+		if jitter > 0 {
+			c.pollJitter = jitter
+		}
+	}
+}
+
+// pollWait sleeps for interval plus a random jitter. It returns ctx.Err() if
+// ctx is done first.
+func (c *SlicerClient) pollWait(ctx context.Context, interval time.Duration) error {
+	if c.pollJitter > 0 {
+		interval += rand.N(c.pollJitter)
+	}
+
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// WaitUntil calls condition at the client's poll interval until it reports
+// done, returns an error, or ctx is done. Conditions should report transient
+// failures, such as a VM that is still booting, as not done.
+func (c *SlicerClient) WaitUntil(ctx context.Context, condition func(ctx context.Context) (bool, error)) error {
+	for {
+		done, err := condition(ctx)
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+
+		if err := c.pollWait(ctx, c.pollInterval); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package slicer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWithPolling(t *testing.T) {
+	client := NewSlicerClient("http://localhost", "token", "agent", nil)
+	if client.pollInterval != DefaultPollInterval {
+		t.Errorf("Want default poll interval %s, got %s", DefaultPollInterval, client.pollInterval)
+	}
+
+	client = NewSlicerClient("http://localhost", "token", "agent", nil, WithPolling(5*time.Second, time.Second))
+	if client.pollInterval != 5*time.Second || client.pollJitter != time.Second {
+		t.Errorf("Want interval 5s and jitter 1s, got %s and %s", client.pollInterval, client.pollJitter)
+	}
+}
+
+func TestPollWait_ContextDone(t *testing.T) {
+	client := NewSlicerClient("http://localhost", "token", "agent", nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := client.pollWait(ctx, time.Hour); !errors.Is(err, context.Canceled) {
+		t.Errorf("Want context.Canceled, got %v", err)
+	}
+}
+
+func TestWaitUntil(t *testing.T) {
+	client := NewSlicerClient("http://localhost", "token", "agent", nil, WithPolling(time.Millisecond, 0))
+
+	calls := 0
+	err := client.WaitUntil(context.Background(), func(ctx context.Context) (bool, error) {
+		calls++
+		return calls == 3, nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("Want 3 calls, got %d", calls)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err = client.WaitUntil(ctx, func(ctx context.Context) (bool, error) {
+		return false, nil
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Want context.DeadlineExceeded, got %v", err)
+	}
+}
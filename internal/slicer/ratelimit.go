@@ -0,0 +1,141 @@
+package slicer
+
+import (
+	"context"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultMaxRetries is how often a request rejected with 429 Too Many
+// Requests is retried unless WithRetries sets another limit.
+const DefaultMaxRetries = 5
+
+const (
+	// retryBaseWait is the first backoff for a 429 without Retry-After. It
+	// doubles with every retry up to retryMaxWait. A Retry-After longer than
+	// retryMaxWait is not waited for.
+	retryBaseWait = time.Second
+	retryMaxWait  = 30 * time.Second
+)
+
+// WithRateLimit caps the rate of requests sent to the API. Requests beyond
+// the rate wait for their turn. 0 or less means unlimited.
+func WithRateLimit(requestsPerSecond float64) ClientOption {
+	return func(c *SlicerClient) {
+		if requestsPerSecond > 0 {
+			c.limiter.interval = time.Duration(float64(time.Second) / requestsPerSecond)
+		}
+	}
+}
+
+// WithRetries sets how often a request rejected with 429 Too Many Requests is
+// retried before the response is returned to the caller. 0 disables retries.
+func WithRetries(n int) ClientOption {
+	return func(c *SlicerClient) {
+		if n >= 0 {
+			c.maxRetries = n
+		}
+	}
+}
+
+// rateLimiter spaces requests interval apart and holds all of them back
+// while the API asked the client to back off.
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// wait blocks until the caller may send a request. It returns ctx.Err() if
+// ctx is done first.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	slot := l.next
+	if slot.Before(now) {
+		slot = now
+	}
+	if l.interval > 0 {
+		l.next = slot.Add(l.interval)
+	}
+	l.mu.Unlock()
+
+	return sleep(ctx, time.Until(slot))
+}
+
+// pause holds back all requests until the given time.
+func (l *rateLimiter) pause(until time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if until.After(l.next) {
+		l.next = until
+	}
+}
+
+// retryWait returns how long to wait before retrying a request rejected with
+// res, honouring its Retry-After header. Without one, the wait grows
+// exponentially with attempt. Up to half the wait is added as jitter, so
+// clients throttled together do not retry in lockstep. It returns false if
+// Retry-After asks for longer than retryMaxWait.
+func retryWait(res *http.Response, attempt int) (time.Duration, bool) {
+	wait := min(retryBaseWait<<min(attempt, 5), retryMaxWait)
+	if header := res.Header.Get("Retry-After"); header != "" {
+		if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+			wait = time.Duration(seconds) * time.Second
+		} else if at, err := http.ParseTime(header); err == nil {
+			wait = time.Until(at)
+		}
+	}
+
+	if wait > retryMaxWait {
+		return 0, false
+	}
+
+	wait = max(wait, 0)
+	if wait > 0 {
+		wait += rand.N(wait/2 + 1)
+	}
+	return wait, true
+}
+
+// retryRequest prepares req to be sent again after res was rejected. It
+// returns false if the body cannot be replayed, e.g. a streamed upload.
+func retryRequest(req *http.Request, res *http.Response) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return false
+		}
+		req.Body = body
+	}
+
+	_, _ = io.Copy(io.Discard, res.Body)
+	res.Body.Close()
+	return true
+}
+
+// sleep waits for d. It returns ctx.Err() if ctx is done first.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,166 @@
+package slicer
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDo_RetriesTooManyRequests(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"name":"db-password","data":"hunter2"}` {
+			t.Errorf("Want the request body on every attempt, got %s", body)
+		}
+
+		if calls.Add(1) < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil)
+	err := client.CreateSecret(context.Background(), CreateSecretRequest{Name: "db-password", Data: "hunter2"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("Want 3 calls, got %d", calls.Load())
+	}
+}
+
+func TestDo_RetriesExhausted(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil, WithRetries(2))
+	res, err := client.makeJSONRequest(http.MethodGet, "/nodes", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	res.Body.Close()
+
+	if res.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("Want status 429, got %d", res.StatusCode)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("Want 3 calls, got %d", calls.Load())
+	}
+}
+
+func TestDo_RetryAfterBeyondDeadline(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil)
+	res, err := client.makeJSONRequestWithContext(ctx, http.MethodGet, "/nodes", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	res.Body.Close()
+
+	if res.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("Want status 429, got %d", res.StatusCode)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Want 1 call, got %d", calls.Load())
+	}
+}
+
+func TestDo_RetryAfterBeyondMaxWait(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil)
+	res, err := client.makeJSONRequest(http.MethodGet, "/nodes", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	res.Body.Close()
+
+	if res.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("Want status 429, got %d", res.StatusCode)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Want 1 call, got %d", calls.Load())
+	}
+
+	// The client must not hold back later requests for the hour asked for
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := client.limiter.wait(ctx); err != nil {
+		t.Errorf("Want later requests to go out at once, got %v", err)
+	}
+}
+
+func TestWithRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewSlicerClient(server.URL, "token", "agent", nil, WithRateLimit(20))
+
+	start := time.Now()
+	for range 3 {
+		res, err := client.makeJSONRequest(http.MethodGet, "/nodes", nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		res.Body.Close()
+	}
+
+	// The first request goes out at once, the others 50ms apart
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("Want at least 100ms for 3 requests at 20/s, got %s", elapsed)
+	}
+}
+
+func TestRetryWait(t *testing.T) {
+	res := &http.Response{Header: http.Header{}}
+	res.Header.Set("Retry-After", "2")
+	if wait, ok := retryWait(res, 0); !ok || wait < 2*time.Second || wait > 3*time.Second {
+		t.Errorf("Want 2-3s for Retry-After: 2, got %s", wait)
+	}
+
+	res.Header.Set("Retry-After", time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat))
+	if wait, ok := retryWait(res, 0); !ok || wait != 0 {
+		t.Errorf("Want no wait for a Retry-After date in the past, got %s", wait)
+	}
+
+	res.Header.Set("Retry-After", "3600")
+	if wait, ok := retryWait(res, 0); ok {
+		t.Errorf("Want no retry for Retry-After beyond %s, got a wait of %s", retryMaxWait, wait)
+	}
+
+	res.Header.Del("Retry-After")
+	if wait, ok := retryWait(res, 10); !ok || wait < retryMaxWait || wait > retryMaxWait*3/2 {
+		t.Errorf("Want backoff capped at %s plus jitter, got %s", retryMaxWait, wait)
+	}
+}
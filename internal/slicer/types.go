@@ -2,6 +2,7 @@ package slicer
 
 import (
 	"net"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -9,29 +10,212 @@ import (
 // SlicerNode represents a node managed by the slicer REST API.
 type SlicerNode struct {
 	Hostname  string    `json:"hostname"`
+	HostGroup string    `json:"host_group,omitempty"` // Empty when the API does not report it
 	IP        string    `json:"ip"`
 	RamBytes  int64     `json:"ram_bytes,omitempty"` // RAM size in bytes
 	CPUs      int       `json:"cpus,omitempty"`
 	CreatedAt time.Time `json:"created_at"`
 	Arch      string    `json:"arch,omitempty"`
 	Tags      []string  `json:"tags,omitempty"`
+	Secrets   []string  `json:"secrets,omitempty"` // Names of secrets mounted into the VM
+
+	// Metadata is served to the VM by the metadata service. Nil when the API
+	// does not report it.
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// UserdataSHA256 is the hex encoded SHA256 of the userdata the VM was
+	// provisioned with. Empty when the API does not report it.
+	UserdataSHA256 string `json:"userdata_sha256,omitempty"`
+
+	// Priority is the CPU/IO share class of the VM
+	Priority string `json:"priority,omitempty"`
+
+	// Schedule is the active start/stop schedule of the VM, nil if it has none
+	Schedule *SlicerVMSchedule `json:"schedule,omitempty"`
+
+	// Template is the name of the VM template the VM was created from
+	Template string `json:"template,omitempty"`
+
+	// ReverseDNS is the name the PTR record of the VM's IP points to
+	ReverseDNS string `json:"reverse_dns,omitempty"`
+
+	// CDROMImage is the ISO image attached to the VM's virtual CD-ROM drive
+	CDROMImage string `json:"cdrom_image,omitempty"`
+
+	// Firmware holds the firmware options of the VM, nil if the API does not
+	// report them
+	Firmware *SlicerFirmwareOptions `json:"firmware,omitempty"`
+
+	// Watchdog is the watchdog device of the VM, nil if it has none
+	Watchdog *SlicerWatchdog `json:"watchdog,omitempty"`
+
+	// PowerState is one of the VMPowerState constants. Empty when the API
+	// does not report it.
+	PowerState string `json:"power_state,omitempty"`
+
+	// AdditionalDisks are the data disks created with the VM, nil if the API
+	// does not report them
+	AdditionalDisks []SlicerDisk `json:"additional_disks,omitempty"`
+
+	SSHAccess
+	ConsoleAccess
+}
+
+// Power states of a VM.
+const (
+	VMPowerStateRunning = "running"
+	VMPowerStateStopped = "stopped"
+)
+
+// SlicerListVMsFilter narrows the VMs returned by /nodes. Zero fields do not
+// filter.
+type SlicerListVMsFilter struct {
+	HostGroup    string
+	Arch         string
+	CreatedAfter time.Time
+}
+
+// SSHAccess describes how to reach a VM over SSH. Fields are empty when the
+// API does not report them.
+type SSHAccess struct {
+	IPv6        string   `json:"ipv6,omitempty"`
+	SSHUser     string   `json:"ssh_user,omitempty"`
+	SSHPort     int      `json:"ssh_port,omitempty"`
+	SSHHostKeys []string `json:"ssh_host_keys,omitempty"` // Public host keys in authorized_keys format
+}
+
+// ConsoleAccess holds per-VM console credentials for emergency access.
+// Fields are empty when the API does not issue them.
+type ConsoleAccess struct {
+	ConsoleUser     string `json:"console_user,omitempty"`
+	ConsolePassword string `json:"console_password,omitempty"`
+}
+
+// SlicerVMTemplate is a reusable VM spec that VMs can be created from.
+type SlicerVMTemplate struct {
+	Name       string   `json:"name"`
+	RamBytes   int64    `json:"ram_bytes,omitempty"` // RAM size in bytes
+	CPUs       int      `json:"cpus,omitempty"`
+	DiskImage  string   `json:"disk_image,omitempty"`
+	ImportUser string   `json:"import_user,omitempty"`
+	SSHKeys    []string `json:"ssh_keys,omitempty"`
+	Userdata   string   `json:"userdata,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+	Secrets    []string `json:"secrets,omitempty"`
+
+	// Revision is incremented by the API every time the template changes
+	Revision int64 `json:"revision,omitempty"`
+}
+
+// SlicerVMSchedule powers a VM on and off using cron expressions.
+// Either expression may be empty to only start or only stop the VM.
+type SlicerVMSchedule struct {
+	Start    string `json:"start,omitempty"`
+	Stop     string `json:"stop,omitempty"`
+	Timezone string `json:"timezone,omitempty"` // IANA time zone, UTC when empty
+}
+
+// SlicerUpdateNodeRequest contains the VM settings that can be changed in place.
+// Empty fields are left unchanged.
+type SlicerUpdateNodeRequest struct {
+	Priority string `json:"priority,omitempty"`
+
+	// ReverseDNS sets the PTR record of the VM's IP; an empty string removes it
+	ReverseDNS *string `json:"reverse_dns,omitempty"`
+
+	// CDROMImage attaches an ISO image to the VM's CD-ROM drive; an empty
+	// string ejects it
+	CDROMImage *string `json:"cdrom_image,omitempty"`
+
+	// CPUs and RamBytes resize the VM
+	CPUs     int   `json:"cpus,omitempty"`
+	RamBytes int64 `json:"ram_bytes,omitempty"`
+
+	// Tags replaces all tags of the VM (key=value); an empty list removes them
+	Tags *[]string `json:"tags,omitempty"`
+
+	// Metadata replaces all metadata of the VM; an empty map removes it
+	Metadata *map[string]string `json:"metadata,omitempty"`
 }
 
 // SlicerCreateNodeRequest contains parameters for creating a node.
 type SlicerCreateNodeRequest struct {
+	// Hostname is the exact hostname of the new VM; HostnamePrefix replaces
+	// the host group name in a generated hostname. Both are optional.
+	Hostname       string `json:"hostname,omitempty"`
+	HostnamePrefix string `json:"hostname_prefix,omitempty"`
+
 	RamBytes   int64    `json:"ram_bytes,omitempty"` // RAM size in bytes (must not exceed host group limit)
 	CPUs       int      `json:"cpus,omitempty"`      // Number of CPUs (must not exceed host group limit)
 	GPUCount   int      `json:"gpu_count,omitempty"`
+	GPUType    string   `json:"gpu_type,omitempty"` // GPU model, e.g. "a100"; any GPU in the host group if empty
 	Persistent bool     `json:"persistent,omitempty"`
 	DiskImage  string   `json:"disk_image,omitempty"`
 	ImportUser string   `json:"import_user,omitempty"`
 	SSHKeys    []string `json:"ssh_keys,omitempty"`
 	Userdata   string   `json:"userdata,omitempty"`
-	IP         string   `json:"ip,omitempty"`
+	IP         string   `json:"ip,omitempty"` // Static IP address; assigned from the pool if empty
 	Tags       []string `json:"tags,omitempty"`
 	Secrets    []string `json:"secrets,omitempty"`
+	Priority   string   `json:"priority,omitempty"` // CPU/IO share class: low, normal or high
+
+	// Metadata is served to the VM by the metadata service and available to
+	// cloud-init at boot
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	Schedule *SlicerVMSchedule `json:"schedule,omitempty"`
+
+	// Subnet is the CIDR of the subnet the VM is attached to; the host
+	// group's default subnet if empty
+	Subnet string `json:"subnet,omitempty"`
+
+	// Template is the name of a VM template providing defaults for fields
+	// not set in the request
+	Template string `json:"template,omitempty"`
+
+	// Source is the hostname of an existing VM whose disk is cloned into
+	// the new VM
+	Source string `json:"source,omitempty"`
+
+	// ReverseDNS is the name the PTR record of the VM's IP points to
+	ReverseDNS string `json:"reverse_dns,omitempty"`
+
+	// CDROMImage is an ISO image attached to the VM's CD-ROM drive at boot
+	CDROMImage string `json:"cdrom_image,omitempty"`
+
+	Firmware *SlicerFirmwareOptions `json:"firmware,omitempty"`
+	Watchdog *SlicerWatchdog        `json:"watchdog,omitempty"`
+
+	// AdditionalDisks are created with the VM and deleted with it
+	AdditionalDisks []SlicerDisk `json:"additional_disks,omitempty"`
+}
+
+// SlicerDisk is a data disk created with a VM in addition to its root disk.
+type SlicerDisk struct {
+	SizeBytes int64  `json:"size_bytes"`
+	Label     string `json:"label,omitempty"`  // Filesystem label, e.g. for /dev/disk/by-label
+	Format    string `json:"format,omitempty"` // Filesystem to create, the disk is left unformatted when empty
 }
 
+// SlicerWatchdog is a virtual watchdog device. The hypervisor performs
+// Action when the guest stops petting the device.
+type SlicerWatchdog struct {
+	Model  string `json:"model,omitempty"`  // Device model, the hypervisor default when empty
+	Action string `json:"action,omitempty"` // "reset" or "poweroff"
+}
+
+// SlicerFirmwareOptions are the UEFI firmware settings of a VM.
+type SlicerFirmwareOptions struct {
+	SecureBoot bool `json:"secure_boot,omitempty"`
+	TPM        bool `json:"tpm,omitempty"` // Attach a virtual TPM 2.0 device
+}
+
+// Firmware features a host group can support.
+const (
+	FirmwareFeatureSecureBoot = "secure_boot"
+	FirmwareFeatureTPM        = "tpm"
+)
+
 // MiB converts megabytes to bytes.
 func MiB(mb int64) int64 {
 	return mb * 1024 * 1024
@@ -42,6 +226,12 @@ func GiB(gb int64) int64 {
 	return gb * 1024 * 1024 * 1024
 }
 
+// BytesToGiB converts bytes to gigabytes, rounding to the nearest whole
+// gigabyte so allocations just under a boundary are not truncated.
+func BytesToGiB(bytes int64) int64 {
+	return (bytes + GiB(1)/2) / GiB(1)
+}
+
 // SlicerCreateNodeResponse is the response from the REST API when creating a node.
 type SlicerCreateNodeResponse struct {
 	///{"hostname":"api-1","ip":"192.168.137.2/24","created_at":"2025-11-14T13:28:34.218182826Z"}
@@ -50,6 +240,9 @@ type SlicerCreateNodeResponse struct {
 	IP        string    `json:"ip"`
 	CreatedAt time.Time `json:"created_at"`
 	Arch      string    `json:"arch,omitempty"`
+
+	SSHAccess
+	ConsoleAccess
 }
 
 func (n *SlicerCreateNodeResponse) IPAddress() net.IP {
@@ -68,6 +261,38 @@ type SlicerHostGroup struct {
 	CPUs     int    `json:"cpus,omitempty"`
 	Arch     string `json:"arch,omitempty"`
 	GPUCount int    `json:"gpu_count,omitempty"`
+
+	DiskBytes            int64  `json:"disk_bytes,omitempty"`             // Disk size per VM in bytes
+	DiskType             string `json:"disk_type,omitempty"`              // "ssd" or "hdd"
+	NetworkBandwidthMbps int64  `json:"network_bandwidth_mbps,omitempty"` // Network bandwidth per VM
+
+	// FirmwareFeatures lists the firmware options VMs in the group may use,
+	// e.g. "secure_boot" or "tpm". Nil when the API does not report them.
+	FirmwareFeatures []string `json:"firmware_features,omitempty"`
+}
+
+// SlicerTokenInfo describes the API token in use.
+type SlicerTokenInfo struct {
+	// Scopes granted to the token, e.g. "secrets:write" or "vms:*". Nil for
+	// tokens without scopes, which have full access.
+	Scopes []string `json:"scopes,omitempty"`
+}
+
+// SlicerSubnet is a network VMs are attached to.
+type SlicerSubnet struct {
+	Name      string `json:"name"`
+	CIDR      string `json:"cidr"`
+	Gateway   string `json:"gateway,omitempty"`
+	HostGroup string `json:"host_group,omitempty"` // Host group whose VMs use the subnet
+}
+
+// SlicerIPReservation is an IP address reserved in a host group's pool.
+// Reserved addresses are not handed out to new VMs unless requested by IP.
+type SlicerIPReservation struct {
+	IP          string `json:"ip,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Hostname is the VM currently using the address, if any
+	Hostname string `json:"hostname,omitempty"`
 }
 
 // ExecWriteResult represents output from commands executing within a microVM.
@@ -79,6 +304,30 @@ type SlicerExecWriteResult struct {
 	Error     string    `json:"error,omitempty"`
 }
 
+// SlicerExecEvent is a single frame of the v2 exec stream. Each frame carries
+// one kind of event, so stdout and stderr chunks keep their relative order.
+type SlicerExecEvent struct {
+	// Seq numbers the frames of a stream from 1 without gaps
+	Seq       uint64    `json:"seq"`
+	Type      string    `json:"type"` // One of the ExecEvent* constants
+	Timestamp time.Time `json:"timestamp"`
+
+	// Data is the output chunk of stdout and stderr events, always base64
+	// encoded, or the message of error events
+	Data string `json:"data,omitempty"`
+
+	// ExitCode is set on exit events
+	ExitCode int `json:"exit_code,omitempty"`
+}
+
+// Event types of the v2 exec stream.
+const (
+	ExecEventStdout = "stdout"
+	ExecEventStderr = "stderr"
+	ExecEventExit   = "exit"
+	ExecEventError  = "error"
+)
+
 // SlicerExecRequest contains parameters for invoking a command
 // within a VM.
 type SlicerExecRequest struct {
@@ -92,8 +341,23 @@ type SlicerExecRequest struct {
 	Shell       string   `json:"shell,omitempty"`
 	Cwd         string   `json:"cwd,omitempty"`
 	Permissions string   `json:"permissions,omitempty"`
+
+	// OutputEncoding asks the agent to encode stdout/stderr chunks, e.g.
+	// ExecOutputEncodingBase64 so that non-UTF-8 output survives the JSON stream.
+	// Chunks are decoded by the client before they are returned.
+	OutputEncoding string `json:"output_encoding,omitempty"`
+
+	// Timeout asks the agent to stop the command after the given duration by
+	// sending KillSignal, followed by SIGKILL once KillGracePeriod has passed.
+	// Zero means no timeout.
+	Timeout         time.Duration `json:"timeout,omitempty"`
+	KillSignal      string        `json:"kill_signal,omitempty"`
+	KillGracePeriod time.Duration `json:"kill_grace_period,omitempty"`
 }
 
+// ExecOutputEncodingBase64 transports exec output as base64 encoded chunks.
+const ExecOutputEncodingBase64 = "base64"
+
 // SlicerCpRequest contains parameters for copying files to/from a VM.
 type SlicerCpRequest struct {
 	VM   string // VM name
@@ -151,6 +415,110 @@ type SlicerDeleteResponse struct {
 	Error       string `json:"error"`
 }
 
+// SlicerBatchDeleteRequest contains the hostnames to delete from a host group in one call.
+type SlicerBatchDeleteRequest struct {
+	Hostnames []string `json:"hostnames"`
+}
+
+// SlicerBatchDeleteResponse represents the response from the batch delete endpoint.
+type SlicerBatchDeleteResponse struct {
+	Deleted []string          `json:"deleted"`
+	Errors  map[string]string `json:"errors,omitempty"`
+}
+
+// SlicerServerInfo describes the Slicer control plane.
+type SlicerServerInfo struct {
+	// Version is the version of the Slicer control plane
+	Version string `json:"version"`
+
+	// GitCommit is the commit the control plane was built from
+	GitCommit string `json:"git_commit,omitempty"`
+
+	// Features lists the optional API features supported by the control plane
+	Features []string `json:"features,omitempty"`
+
+	// AgentVersions maps VM hostnames to the version of their guest agent
+	AgentVersions map[string]string `json:"agent_versions,omitempty"`
+}
+
+// SlicerHealth is the health of the control plane.
+type SlicerHealth struct {
+	// Status is HealthStatusOK when the control plane can serve requests
+	Status string `json:"status"`
+
+	// Checks maps the control plane's components, e.g. its database, to
+	// their status
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// Health statuses reported by the control plane.
+const (
+	HealthStatusOK          = "ok"
+	HealthStatusUnavailable = "unavailable"
+)
+
+// Healthy reports whether the control plane can serve requests.
+func (h *SlicerHealth) Healthy() bool {
+	return h.Status == HealthStatusOK
+}
+
+// AtLeast reports whether the control plane version is at least minVersion.
+// Versions are compared as major.minor.patch with an optional "v" prefix;
+// pre-release and build suffixes are ignored. A version that cannot be
+// parsed is assumed to be new enough.
+func (i *SlicerServerInfo) AtLeast(minVersion string) bool {
+	have, ok := parseVersion(i.Version)
+	if !ok {
+		return true
+	}
+	want, ok := parseVersion(minVersion)
+	if !ok {
+		return true
+	}
+
+	for n := range have {
+		if have[n] != want[n] {
+			return have[n] > want[n]
+		}
+	}
+	return true
+}
+
+func parseVersion(v string) ([3]int, bool) {
+	var parsed [3]int
+
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+
+	parts := strings.Split(v, ".")
+	if len(parts) == 0 || len(parts) > 3 {
+		return parsed, false
+	}
+
+	for n, part := range parts {
+		num, err := strconv.Atoi(part)
+		if err != nil || num < 0 {
+			return parsed, false
+		}
+		parsed[n] = num
+	}
+
+	return parsed, true
+}
+
+// SlicerCordonRequest is sent when putting a host group into maintenance.
+type SlicerCordonRequest struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+// SlicerEvacuateResponse represents the response from the evacuate endpoint.
+type SlicerEvacuateResponse struct {
+	Migrated []string          `json:"migrated"`
+	Errors   map[string]string `json:"errors,omitempty"`
+}
+
 type SlicerAgentHealthResponse struct {
 	// Hostname is the hostname of the agent
 	Hostname string `json:"hostname,omitempty"`
@@ -0,0 +1,42 @@
+package slicer
+
+// SlicerFirewallRule is a network policy rule enforced on the VMs whose tags
+// match TargetTags.
+type SlicerFirewallRule struct {
+	// ID is assigned by the API when the rule is created
+	ID string `json:"id,omitempty"`
+	// Description is a free-form note about the rule
+	Description string `json:"description,omitempty"`
+	// Direction is one of the FirewallDirection constants
+	Direction string `json:"direction"`
+	// Action is one of the FirewallAction constants
+	Action string `json:"action"`
+	// Protocol is tcp, udp, icmp or all
+	Protocol string `json:"protocol"`
+	// Port is a single port or an inclusive range such as 8000-8080. Empty
+	// matches every port.
+	Port string `json:"port,omitempty"`
+	// Source is the CIDR ingress traffic comes from. Empty matches any
+	// address.
+	Source string `json:"source,omitempty"`
+	// SourceTags selects the VMs ingress traffic comes from, as key=value
+	// pairs
+	SourceTags []string `json:"source_tags,omitempty"`
+	// Destination is the CIDR egress traffic goes to. Empty matches any
+	// address.
+	Destination string `json:"destination,omitempty"`
+	// TargetTags selects the VMs the rule is enforced on, as key=value pairs
+	TargetTags []string `json:"target_tags"`
+}
+
+// Directions of a firewall rule.
+const (
+	FirewallDirectionIngress = "ingress"
+	FirewallDirectionEgress  = "egress"
+)
+
+// Actions of a firewall rule.
+const (
+	FirewallActionAllow = "allow"
+	FirewallActionDeny  = "deny"
+)
@@ -0,0 +1,46 @@
+package slicer
+
+import "time"
+
+// Job statuses reported by the API.
+const (
+	JobStatusPending   = "pending"
+	JobStatusRunning   = "running"
+	JobStatusSucceeded = "succeeded"
+	JobStatusFailed    = "failed"
+)
+
+// SlicerJob is a long-running backend operation such as an image import or
+// a large clone.
+type SlicerJob struct {
+	// ID is the unique identifier of the job
+	ID string `json:"id"`
+	// Type is the kind of operation, e.g. "image_import"
+	Type string `json:"type"`
+	// Status is one of the JobStatus constants
+	Status string `json:"status"`
+	// Progress is the completion percentage from 0 to 100
+	Progress int `json:"progress,omitempty"`
+	// Message is a human readable description of the current step
+	Message string `json:"message,omitempty"`
+	// Error is the reason the job failed
+	Error string `json:"error,omitempty"`
+	// Result holds outputs of a succeeded job, e.g. the name of an imported image
+	Result map[string]string `json:"result,omitempty"`
+
+	CreatedAt  time.Time  `json:"created_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// Done reports whether the job has finished, successfully or not.
+func (j *SlicerJob) Done() bool {
+	return j.Status == JobStatusSucceeded || j.Status == JobStatusFailed
+}
+
+// SlicerJobRequest is the payload for submitting a job.
+type SlicerJobRequest struct {
+	// Type is the kind of operation to run
+	Type string `json:"type"`
+	// Params are the operation specific parameters
+	Params map[string]string `json:"params,omitempty"`
+}
@@ -0,0 +1,20 @@
+package slicer
+
+// SlicerPortForward publishes a port of a VM on the host or gateway in
+// front of its host group.
+type SlicerPortForward struct {
+	// ID is assigned by the API when the port forward is created
+	ID string `json:"id,omitempty"`
+	// Hostname is the VM traffic is forwarded to
+	Hostname string `json:"hostname"`
+	// VMPort is the port on the VM
+	VMPort int `json:"vm_port"`
+	// ExternalPort is the port published on the host. The API picks a free
+	// port if it is 0.
+	ExternalPort int `json:"external_port,omitempty"`
+	// Protocol is tcp or udp
+	Protocol string `json:"protocol"`
+	// ExternalAddress is the address the port is published on. Empty when
+	// the API does not report it.
+	ExternalAddress string `json:"external_address,omitempty"`
+}
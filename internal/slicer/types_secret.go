@@ -25,12 +25,24 @@ type Secret struct {
 	ModifiedAt *time.Time `json:"modified_at,omitempty"`
 }
 
+// SecretValue is the data of a secret as returned by the REST API.
+type SecretValue struct {
+	// Data is the secret content
+	Data string `json:"data"`
+}
+
+// SecretEncodingBase64 marks secret data that is base64 encoded, so binary
+// values survive JSON encoding. The API stores the decoded bytes.
+const SecretEncodingBase64 = "base64"
+
 // CreateSecretRequest is the payload for creating a new secret via the REST API.
 type CreateSecretRequest struct {
 	// Name is the unique name of the secret
 	Name string `json:"name"`
 	// Data is the secret content
 	Data string `json:"data"`
+	// Encoding is SecretEncodingBase64 when Data is base64 encoded
+	Encoding string `json:"encoding,omitempty"`
 	// Permissions specifies the file permissions (defaults to system default)
 	Permissions string `json:"permissions,omitempty"`
 
@@ -48,6 +60,8 @@ type CreateSecretRequest struct {
 type UpdateSecretRequest struct {
 	// Data is the updated secret content
 	Data string `json:"data"`
+	// Encoding is SecretEncodingBase64 when Data is base64 encoded
+	Encoding string `json:"encoding,omitempty"`
 	// Permissions specifies the file permissions
 	Permissions string `json:"permissions,omitempty"`
 
@@ -0,0 +1,21 @@
+package slicer
+
+import "time"
+
+// SlicerDiskSnapshot is a point-in-time copy of a persistent VM's disk.
+type SlicerDiskSnapshot struct {
+	// Name is the unique name of the snapshot
+	Name string `json:"name"`
+	// Hostname is the VM the snapshot was taken of
+	Hostname string `json:"hostname"`
+	// SizeBytes is the disk space used by the snapshot
+	SizeBytes int64 `json:"size_bytes,omitempty"`
+	// CreatedAt is the time the snapshot was taken
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SlicerCreateDiskSnapshotRequest is the payload for taking a snapshot.
+type SlicerCreateDiskSnapshotRequest struct {
+	Name     string `json:"name"`
+	Hostname string `json:"hostname"`
+}
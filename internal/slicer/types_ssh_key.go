@@ -0,0 +1,22 @@
+package slicer
+
+import "time"
+
+// SlicerSSHKey is an SSH public key stored centrally, so VMs can reference
+// it by name.
+type SlicerSSHKey struct {
+	// Name is the unique name of the key
+	Name string `json:"name"`
+	// PublicKey is the key in authorized_keys format
+	PublicKey string `json:"public_key"`
+	// Fingerprint is the SHA256 fingerprint of the key
+	Fingerprint string `json:"fingerprint,omitempty"`
+	// CreatedAt is the time the key was added
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SlicerCreateSSHKeyRequest is the payload for adding an SSH key.
+type SlicerCreateSSHKeyRequest struct {
+	Name      string `json:"name"`
+	PublicKey string `json:"public_key"`
+}
@@ -0,0 +1,44 @@
+package slicer
+
+import "testing"
+
+func TestSlicerServerInfo_AtLeast(t *testing.T) {
+	tests := []struct {
+		version    string
+		minVersion string
+		want       bool
+	}{
+		{"0.2.0", "0.2.0", true},
+		{"v0.2.1", "0.2.0", true},
+		{"0.10.0", "0.9.5", true},
+		{"0.1.40", "0.2.0", false},
+		{"0.2.0-rc1", "0.2.1", false},
+		{"1.0", "0.9.0", true},
+		{"dev", "0.2.0", true},
+	}
+
+	for _, tt := range tests {
+		info := SlicerServerInfo{Version: tt.version}
+		if got := info.AtLeast(tt.minVersion); got != tt.want {
+			t.Errorf("Want AtLeast(%q) for version %q to be %t, got %t", tt.minVersion, tt.version, tt.want, got)
+		}
+	}
+}
+
+func TestBytesToGiB(t *testing.T) {
+	tests := []struct {
+		bytes int64
+		want  int64
+	}{
+		{GiB(4), 4},
+		{GiB(4) - 1024*1024, 4},
+		{GiB(1) + GiB(1)/2, 2},
+		{GiB(1) / 4, 0},
+	}
+
+	for _, tt := range tests {
+		if got := BytesToGiB(tt.bytes); got != tt.want {
+			t.Errorf("Want BytesToGiB(%d) to be %d, got %d", tt.bytes, tt.want, got)
+		}
+	}
+}
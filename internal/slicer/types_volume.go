@@ -0,0 +1,37 @@
+package slicer
+
+import "time"
+
+// SlicerVolume is a persistent data disk that exists independently of VMs.
+type SlicerVolume struct {
+	// Name is the unique name of the volume
+	Name string `json:"name"`
+	// HostGroup is the host group whose VMs can attach the volume
+	HostGroup string `json:"host_group"`
+	// SizeBytes is the capacity of the volume
+	SizeBytes int64 `json:"size_bytes"`
+	// AttachedTo is the hostname of the VM the volume is attached to, empty
+	// when it is detached
+	AttachedTo string `json:"attached_to,omitempty"`
+	// Device is the block device of the volume inside the VM, e.g. /dev/vdb
+	Device string `json:"device,omitempty"`
+	// CreatedAt is the time the volume was created
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SlicerCreateVolumeRequest is the payload for creating a volume.
+type SlicerCreateVolumeRequest struct {
+	Name      string `json:"name"`
+	HostGroup string `json:"host_group"`
+	SizeBytes int64  `json:"size_bytes"`
+}
+
+// SlicerResizeVolumeRequest is the payload for growing a volume.
+type SlicerResizeVolumeRequest struct {
+	SizeBytes int64 `json:"size_bytes"`
+}
+
+// SlicerAttachVolumeRequest is the payload for attaching a volume to a VM.
+type SlicerAttachVolumeRequest struct {
+	Volume string `json:"volume"`
+}
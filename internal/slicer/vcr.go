@@ -0,0 +1,302 @@
+package slicer
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// VCRMode selects whether the VCR transport records or replays interactions.
+type VCRMode string
+
+const (
+	// VCRModeRecord forwards requests to the real API and stores every
+	// interaction in the cassette file.
+	VCRModeRecord VCRMode = "record"
+	// VCRModeReplay serves responses from the cassette file without
+	// contacting the API.
+	VCRModeReplay VCRMode = "replay"
+)
+
+// ErrNoInteraction is returned in replay mode when the cassette holds no
+// unused interaction matching a request.
+var ErrNoInteraction = errors.New("no recorded interaction matches request")
+
+// redactedValue replaces secret values in recorded bodies.
+const redactedValue = "REDACTED"
+
+// Cassette is the JSON fixture format written by the VCR transport.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a single recorded request/response pair.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest holds the parts of a request used for matching on replay.
+// Headers are not recorded so that tokens never end up in fixtures, and
+// secret values are redacted from the bodies of /secrets requests.
+type RecordedRequest struct {
+	Method       string `json:"method"`
+	URL          string `json:"url"`
+	Body         string `json:"body,omitempty"`
+	BodyEncoding string `json:"body_encoding,omitempty"`
+}
+
+// RecordedResponse holds the response replayed for a matching request.
+type RecordedResponse struct {
+	StatusCode   int         `json:"status_code"`
+	Headers      http.Header `json:"headers,omitempty"`
+	Body         string      `json:"body,omitempty"`
+	BodyEncoding string      `json:"body_encoding,omitempty"`
+}
+
+// VCRTransport is an http.RoundTripper that records API interactions to a
+// JSON cassette or replays them deterministically. Secret values sent to or
+// read from /secrets are redacted in the cassette, so replayed secret values
+// read back as "REDACTED".
+// In replay mode identical requests are answered in the order they were recorded.
+type VCRTransport struct {
+	mode     VCRMode
+	path     string
+	next     http.RoundTripper
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool
+}
+
+// NewVCRTransport creates a VCR transport for the cassette at path.
+// In record mode requests are forwarded to next (http.DefaultTransport when nil)
+// and the cassette is rewritten after every interaction.
+// In replay mode the cassette must already exist.
+func NewVCRTransport(mode VCRMode, path string, next http.RoundTripper) (*VCRTransport, error) {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	t := &VCRTransport{
+		mode: mode,
+		path: path,
+		next: next,
+	}
+
+	switch mode {
+	default:
+		return nil, fmt.Errorf("invalid VCR mode: %s", mode)
+	case VCRModeRecord:
+	case VCRModeReplay:
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cassette: %w", err)
+		}
+		if err := json.Unmarshal(data, &t.cassette); err != nil {
+			return nil, fmt.Errorf("failed to decode cassette: %w", err)
+		}
+		t.used = make([]bool, len(t.cassette.Interactions))
+	}
+
+	return t, nil
+}
+
+// RoundTrip implements http.RoundTripper. The request body is read to match
+// or record it, so the request sent on is a clone carrying a copy of it.
+func (t *VCRTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		reqBody, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+	}
+
+	recorded := RecordedRequest{
+		Method: req.Method,
+		URL:    req.URL.RequestURI(),
+	}
+	recorded.Body, recorded.BodyEncoding = encodeBody(redactBody(req.URL.Path, reqBody))
+
+	if t.mode == VCRModeReplay {
+		return t.replay(req, recorded)
+	}
+
+	clone := req.Clone(req.Context())
+	if req.Body != nil {
+		clone.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+	return t.record(clone, recorded)
+}
+
+func (t *VCRTransport) replay(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for i, interaction := range t.cassette.Interactions {
+		if t.used[i] || interaction.Request != recorded {
+			continue
+		}
+		t.used[i] = true
+
+		body, err := decodeBody(interaction.Response.Body, interaction.Response.BodyEncoding)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode recorded response body: %w", err)
+		}
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        interaction.Response.Headers.Clone(),
+			Body:          io.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s %s", ErrNoInteraction, recorded.Method, recorded.URL)
+}
+
+// record forwards req and stores the interaction once the response body has
+// been read to the end or closed. The body is passed through as it arrives,
+// so streamed responses such as exec output still stream while recording.
+func (t *VCRTransport) record(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	res, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	interaction := Interaction{
+		Request: recorded,
+		Response: RecordedResponse{
+			StatusCode: res.StatusCode,
+			Headers:    res.Header.Clone(),
+		},
+	}
+
+	save := func(body []byte) error {
+		interaction.Response.Body, interaction.Response.BodyEncoding = encodeBody(redactBody(req.URL.Path, body))
+
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		t.cassette.Interactions = append(t.cassette.Interactions, interaction)
+		return t.save()
+	}
+
+	if res.Body == nil {
+		if err := save(nil); err != nil {
+			return nil, err
+		}
+		return res, nil
+	}
+
+	res.Body = &recordingBody{ReadCloser: res.Body, save: save}
+	return res, nil
+}
+
+// recordingBody copies a response body into the cassette as it is read.
+type recordingBody struct {
+	io.ReadCloser
+	buf     bytes.Buffer
+	save    func([]byte) error
+	once    sync.Once
+	saveErr error
+}
+
+func (b *recordingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.buf.Write(p[:n])
+	if err == io.EOF {
+		if saveErr := b.finish(); saveErr != nil {
+			return n, saveErr
+		}
+	}
+	return n, err
+}
+
+func (b *recordingBody) Close() error {
+	err := b.ReadCloser.Close()
+	if saveErr := b.finish(); saveErr != nil {
+		return saveErr
+	}
+	return err
+}
+
+// finish stores the body read so far, once.
+func (b *recordingBody) finish() error {
+	b.once.Do(func() {
+		b.saveErr = b.save(b.buf.Bytes())
+	})
+	return b.saveErr
+}
+
+// save writes the cassette to disk. The caller must hold t.mu.
+func (t *VCRTransport) save() error {
+	data, err := json.MarshalIndent(t.cassette, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cassette: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(t.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create cassette directory: %w", err)
+	}
+
+	if err := os.WriteFile(t.path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+
+	return nil
+}
+
+// encodeBody returns body as a string, base64 encoding it when it is not valid UTF-8.
+func encodeBody(body []byte) (string, string) {
+	if utf8.Valid(body) {
+		return string(body), ""
+	}
+	return base64.StdEncoding.EncodeToString(body), "base64"
+}
+
+func decodeBody(body, encoding string) ([]byte, error) {
+	if encoding == "base64" {
+		return base64.StdEncoding.DecodeString(body)
+	}
+	return []byte(body), nil
+}
+
+// redactBody masks the secret value in the body of a request to or response
+// from path: the "data" field of /secrets bodies, which carries the value in
+// CreateSecretRequest, UpdateSecretRequest and SecretValue. Other bodies,
+// such as secret listings, are returned as is.
+func redactBody(path string, body []byte) []byte {
+	if path != "/secrets" && !strings.HasPrefix(path, "/secrets/") {
+		return body
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return body
+	}
+	if _, ok := fields["data"]; !ok {
+		return body
+	}
+
+	// Marshalling a map of raw messages cannot fail
+	fields["data"], _ = json.Marshal(redactedValue)
+	redacted, _ := json.Marshal(fields)
+	return redacted
+}
@@ -0,0 +1,201 @@
+package slicer
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestVCRTransport_RecordThenReplay(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.Header.Get("Authorization") != "Bearer token" {
+			t.Errorf("Want Authorization header to be forwarded, got '%s'", r.Header.Get("Authorization"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"hostname":"vm-1","ip":"192.168.137.2/24"}]`))
+	}))
+	defer server.Close()
+
+	cassette := filepath.Join(t.TempDir(), "fixtures", "list.json")
+
+	recorder, err := NewVCRTransport(VCRModeRecord, cassette, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	client := NewSlicerClient(server.URL, "token", "agent", &http.Client{Transport: recorder})
+	nodes, err := client.ListVMs(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(nodes) != 1 || nodes[0].Hostname != "vm-1" {
+		t.Fatalf("Want one node 'vm-1', got %v", nodes)
+	}
+
+	player, err := NewVCRTransport(VCRModeReplay, cassette, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	server.Close()
+
+	client = NewSlicerClient(server.URL, "token", "agent", &http.Client{Transport: player})
+	nodes, err = client.ListVMs(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(nodes) != 1 || nodes[0].Hostname != "vm-1" {
+		t.Errorf("Want replayed node 'vm-1', got %v", nodes)
+	}
+	if calls != 1 {
+		t.Errorf("Want 1 call to the server, got %d", calls)
+	}
+
+	// Each recorded interaction is replayed only once
+	_, err = client.ListVMs(context.Background())
+	if !errors.Is(err, ErrNoInteraction) {
+		t.Errorf("Want ErrNoInteraction, got %v", err)
+	}
+}
+
+func TestVCRTransport_InvalidMode(t *testing.T) {
+	_, err := NewVCRTransport("rewind", filepath.Join(t.TempDir(), "cassette.json"), nil)
+	if err == nil {
+		t.Error("Want error, got nil")
+	}
+}
+
+func TestVCRTransport_RedactsSecrets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/secrets":
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodGet && r.URL.Path == "/secrets/db-password/value":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"data":"hunter2"}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	cassette := filepath.Join(t.TempDir(), "secrets.json")
+
+	recorder, err := NewVCRTransport(VCRModeRecord, cassette, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	client := NewSlicerClient(server.URL, "token", "agent", &http.Client{Transport: recorder})
+	if err := client.CreateSecret(context.Background(), CreateSecretRequest{Name: "db-password", Data: "hunter2"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	value, err := client.GetSecretValue(context.Background(), "db-password")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if value != "hunter2" {
+		t.Errorf("Want the real value while recording, got %q", value)
+	}
+
+	data, err := os.ReadFile(cassette)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Contains(string(data), "hunter2") {
+		t.Errorf("Want secret values redacted from the cassette, got %s", data)
+	}
+
+	player, err := NewVCRTransport(VCRModeReplay, cassette, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Requests carrying the real value still match their redacted recording
+	client = NewSlicerClient(server.URL, "token", "agent", &http.Client{Transport: player})
+	if err := client.CreateSecret(context.Background(), CreateSecretRequest{Name: "db-password", Data: "hunter2"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	value, err = client.GetSecretValue(context.Background(), "db-password")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if value != redactedValue {
+		t.Errorf("Want replayed value %q, got %q", redactedValue, value)
+	}
+}
+
+func TestVCRTransport_RecordStreams(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("{\"stdout\":\"first\\n\"}\n"))
+		w.(http.Flusher).Flush()
+		<-release
+		_, _ = w.Write([]byte("{\"exit_code\":0}\n"))
+	}))
+	defer server.Close()
+
+	cassette := filepath.Join(t.TempDir(), "exec.json")
+
+	recorder, err := NewVCRTransport(VCRModeRecord, cassette, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/vm/vm-1/exec", strings.NewReader("echo first"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	res, err := recorder.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// The first line arrives before the server finishes the response
+	line, err := bufio.NewReader(res.Body).ReadString('\n')
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if line != "{\"stdout\":\"first\\n\"}\n" {
+		t.Errorf("Want the first line, got %q", line)
+	}
+	close(release)
+
+	if _, err := io.Copy(io.Discard, res.Body); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	res.Body.Close()
+
+	var recorded Cassette
+	data, err := os.ReadFile(cassette)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := json.Unmarshal(data, &recorded); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(recorded.Interactions) != 1 {
+		t.Fatalf("Want 1 interaction, got %d", len(recorded.Interactions))
+	}
+	if got := recorded.Interactions[0].Response.Body; got != "{\"stdout\":\"first\\n\"}\n{\"exit_code\":0}\n" {
+		t.Errorf("Want the full stream recorded, got %q", got)
+	}
+	if got := recorded.Interactions[0].Request.Body; got != "echo first" {
+		t.Errorf("Want the request body recorded, got %q", got)
+	}
+
+	// The caller's request is left untouched
+	if body, _ := io.ReadAll(req.Body); len(body) != 0 {
+		t.Errorf("Want the caller's body consumed, not replaced, got %q", body)
+	}
+}